@@ -10,6 +10,7 @@ Copyright 2022 Upbound Inc.
 package v1beta1
 
 import (
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -174,6 +175,16 @@ func (in *AnalyzerStatus) DeepCopyInto(out *AnalyzerStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerStatus.
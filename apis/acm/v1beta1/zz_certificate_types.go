@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type CertificateObservation struct {
@@ -134,6 +135,16 @@ type CertificateSpec struct {
 type CertificateStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        CertificateObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -169,6 +180,26 @@ var (
 	Certificate_GroupVersionKind = CRDGroupVersion.WithKind(Certificate_Kind)
 )
 
+// GetPlannedChanges of this Certificate.
+func (mg *Certificate) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Certificate.
+func (mg *Certificate) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Certificate.
+func (mg *Certificate) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Certificate.
+func (mg *Certificate) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
 }
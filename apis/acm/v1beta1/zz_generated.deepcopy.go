@@ -11,6 +11,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -248,6 +249,16 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
@@ -407,6 +418,16 @@ func (in *CertificateValidationStatus) DeepCopyInto(out *CertificateValidationSt
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateValidationStatus.
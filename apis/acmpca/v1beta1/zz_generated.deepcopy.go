@@ -11,6 +11,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -210,6 +211,16 @@ func (in *CertificateAuthorityCertificateStatus) DeepCopyInto(out *CertificateAu
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthorityCertificateStatus.
@@ -462,6 +473,16 @@ func (in *CertificateAuthorityStatus) DeepCopyInto(out *CertificateAuthorityStat
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthorityStatus.
@@ -616,6 +637,16 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
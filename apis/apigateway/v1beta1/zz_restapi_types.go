@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type RestAPIEndpointConfigurationObservation struct {
@@ -111,6 +112,16 @@ type RestAPISpec struct {
 type RestAPIStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        RestAPIObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -146,6 +157,26 @@ var (
 	RestAPI_GroupVersionKind = CRDGroupVersion.WithKind(RestAPI_Kind)
 )
 
+// GetPlannedChanges of this RestAPI.
+func (mg *RestAPI) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this RestAPI.
+func (mg *RestAPI) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this RestAPI.
+func (mg *RestAPI) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this RestAPI.
+func (mg *RestAPI) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&RestAPI{}, &RestAPIList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type AwsCloudMapObservation struct {
@@ -1034,6 +1035,16 @@ type VirtualNodeSpec struct {
 type VirtualNodeStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        VirtualNodeObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1069,6 +1080,26 @@ var (
 	VirtualNode_GroupVersionKind = CRDGroupVersion.WithKind(VirtualNode_Kind)
 )
 
+// GetPlannedChanges of this VirtualNode.
+func (mg *VirtualNode) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this VirtualNode.
+func (mg *VirtualNode) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this VirtualNode.
+func (mg *VirtualNode) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this VirtualNode.
+func (mg *VirtualNode) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&VirtualNode{}, &VirtualNodeList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type VaultNotificationsObservation struct {
@@ -71,6 +72,16 @@ type VaultNotificationsSpec struct {
 type VaultNotificationsStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        VaultNotificationsObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -106,6 +117,26 @@ var (
 	VaultNotifications_GroupVersionKind = CRDGroupVersion.WithKind(VaultNotifications_Kind)
 )
 
+// GetPlannedChanges of this VaultNotifications.
+func (mg *VaultNotifications) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this VaultNotifications.
+func (mg *VaultNotifications) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this VaultNotifications.
+func (mg *VaultNotifications) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this VaultNotifications.
+func (mg *VaultNotifications) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&VaultNotifications{}, &VaultNotificationsList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type EnvironmentEC2Observation struct {
@@ -91,6 +92,16 @@ type EnvironmentEC2Spec struct {
 type EnvironmentEC2Status struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        EnvironmentEC2Observation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -126,6 +137,26 @@ var (
 	EnvironmentEC2_GroupVersionKind = CRDGroupVersion.WithKind(EnvironmentEC2_Kind)
 )
 
+// GetPlannedChanges of this EnvironmentEC2.
+func (mg *EnvironmentEC2) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this EnvironmentEC2.
+func (mg *EnvironmentEC2) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this EnvironmentEC2.
+func (mg *EnvironmentEC2) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this EnvironmentEC2.
+func (mg *EnvironmentEC2) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&EnvironmentEC2{}, &EnvironmentEC2List{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type PublicKeyObservation struct {
@@ -55,6 +56,16 @@ type PublicKeySpec struct {
 type PublicKeyStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        PublicKeyObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -90,6 +101,26 @@ var (
 	PublicKey_GroupVersionKind = CRDGroupVersion.WithKind(PublicKey_Kind)
 )
 
+// GetPlannedChanges of this PublicKey.
+func (mg *PublicKey) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this PublicKey.
+func (mg *PublicKey) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this PublicKey.
+func (mg *PublicKey) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this PublicKey.
+func (mg *PublicKey) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&PublicKey{}, &PublicKeyList{})
 }
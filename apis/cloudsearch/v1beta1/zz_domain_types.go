@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type DomainObservation struct {
@@ -52,6 +53,11 @@ type DomainParameters struct {
 	// Domain scaling parameters. Documented below.
 	// +kubebuilder:validation:Optional
 	ScalingParameters []ScalingParametersParameters `json:"scalingParameters,omitempty" tf:"scaling_parameters,omitempty"`
+
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []TimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
 }
 
 type EndpointOptionsObservation struct {
@@ -128,6 +134,27 @@ type ScalingParametersParameters struct {
 	DesiredReplicationCount *float64 `json:"desiredReplicationCount,omitempty" tf:"desired_replication_count,omitempty"`
 }
 
+type TimeoutsObservation struct {
+}
+
+type TimeoutsParameters struct {
+
+	// (Default 30 minutes) How long to wait for the CloudSearch domain to be created.
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 20 minutes) How long to wait for the CloudSearch domain to be deleted.
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 30 minutes) How long to wait for the CloudSearch domain to be updated.
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 // DomainSpec defines the desired state of Domain
 type DomainSpec struct {
 	v1.ResourceSpec `json:",inline"`
@@ -138,6 +165,16 @@ type DomainSpec struct {
 type DomainStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        DomainObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -173,6 +210,26 @@ var (
 	Domain_GroupVersionKind = CRDGroupVersion.WithKind(Domain_Kind)
 )
 
+// GetPlannedChanges of this Domain.
+func (mg *Domain) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Domain.
+func (mg *Domain) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Domain.
+func (mg *Domain) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Domain.
+func (mg *Domain) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Domain{}, &DomainList{})
 }
@@ -11,6 +11,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -147,6 +148,13 @@ func (in *DomainParameters) DeepCopyInto(out *DomainParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = make([]TimeoutsParameters, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainParameters.
@@ -300,6 +308,16 @@ func (in *DomainServiceAccessPolicyStatus) DeepCopyInto(out *DomainServiceAccess
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainServiceAccessPolicyStatus.
@@ -334,6 +352,16 @@ func (in *DomainStatus) DeepCopyInto(out *DomainStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainStatus.
@@ -505,3 +533,48 @@ func (in *ScalingParametersParameters) DeepCopy() *ScalingParametersParameters {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsObservation) DeepCopyInto(out *TimeoutsObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsObservation.
+func (in *TimeoutsObservation) DeepCopy() *TimeoutsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsParameters) DeepCopyInto(out *TimeoutsParameters) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = new(string)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(string)
+		**out = **in
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsParameters.
+func (in *TimeoutsParameters) DeepCopy() *TimeoutsParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsParameters)
+	in.DeepCopyInto(out)
+	return out
+}
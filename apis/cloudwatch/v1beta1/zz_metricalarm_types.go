@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type MetricAlarmObservation struct {
@@ -198,6 +199,16 @@ type MetricAlarmSpec struct {
 type MetricAlarmStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        MetricAlarmObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -233,6 +244,26 @@ var (
 	MetricAlarm_GroupVersionKind = CRDGroupVersion.WithKind(MetricAlarm_Kind)
 )
 
+// GetPlannedChanges of this MetricAlarm.
+func (mg *MetricAlarm) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this MetricAlarm.
+func (mg *MetricAlarm) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this MetricAlarm.
+func (mg *MetricAlarm) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this MetricAlarm.
+func (mg *MetricAlarm) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&MetricAlarm{}, &MetricAlarmList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type AWSConfigurationRecorderStatusObservation struct {
@@ -39,6 +40,16 @@ type AWSConfigurationRecorderStatusSpec struct {
 type AWSConfigurationRecorderStatusStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        AWSConfigurationRecorderStatusObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -74,6 +85,26 @@ var (
 	AWSConfigurationRecorderStatus_GroupVersionKind = CRDGroupVersion.WithKind(AWSConfigurationRecorderStatus_Kind)
 )
 
+// GetPlannedChanges of this AWSConfigurationRecorderStatus.
+func (mg *AWSConfigurationRecorderStatus) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this AWSConfigurationRecorderStatus.
+func (mg *AWSConfigurationRecorderStatus) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this AWSConfigurationRecorderStatus.
+func (mg *AWSConfigurationRecorderStatus) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this AWSConfigurationRecorderStatus.
+func (mg *AWSConfigurationRecorderStatus) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&AWSConfigurationRecorderStatus{}, &AWSConfigurationRecorderStatusList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type PhoneConfigObservation struct {
@@ -136,6 +137,16 @@ type QuickConnectSpec struct {
 type QuickConnectStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        QuickConnectObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -171,6 +182,26 @@ var (
 	QuickConnect_GroupVersionKind = CRDGroupVersion.WithKind(QuickConnect_Kind)
 )
 
+// GetPlannedChanges of this QuickConnect.
+func (mg *QuickConnect) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this QuickConnect.
+func (mg *QuickConnect) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this QuickConnect.
+func (mg *QuickConnect) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this QuickConnect.
+func (mg *QuickConnect) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&QuickConnect{}, &QuickConnectList{})
 }
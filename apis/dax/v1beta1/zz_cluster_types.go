@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type ClusterObservation struct {
@@ -130,6 +131,11 @@ type ClusterParameters struct {
 	// Key-value map of resource tags.
 	// +kubebuilder:validation:Optional
 	Tags map[string]*string `json:"tags,omitempty" tf:"tags,omitempty"`
+
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []TimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
 }
 
 type NodesObservation struct {
@@ -156,6 +162,27 @@ type ServerSideEncryptionParameters struct {
 	Enabled *bool `json:"enabled,omitempty" tf:"enabled,omitempty"`
 }
 
+type TimeoutsObservation struct {
+}
+
+type TimeoutsParameters struct {
+
+	// (Default 45 minutes) Used for creating a DAX cluster
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 90 minutes) Used for destroying a DAX cluster
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 45 minutes) Used for cluster modifications
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 // ClusterSpec defines the desired state of Cluster
 type ClusterSpec struct {
 	v1.ResourceSpec `json:",inline"`
@@ -166,6 +193,16 @@ type ClusterSpec struct {
 type ClusterStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        ClusterObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -201,6 +238,26 @@ var (
 	Cluster_GroupVersionKind = CRDGroupVersion.WithKind(Cluster_Kind)
 )
 
+// GetPlannedChanges of this Cluster.
+func (mg *Cluster) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Cluster.
+func (mg *Cluster) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Cluster.
+func (mg *Cluster) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Cluster.
+func (mg *Cluster) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
 }
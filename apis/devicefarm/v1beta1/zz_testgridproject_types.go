@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type TestGridProjectObservation struct {
@@ -108,6 +109,16 @@ type TestGridProjectSpec struct {
 type TestGridProjectStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        TestGridProjectObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -143,6 +154,26 @@ var (
 	TestGridProject_GroupVersionKind = CRDGroupVersion.WithKind(TestGridProject_Kind)
 )
 
+// GetPlannedChanges of this TestGridProject.
+func (mg *TestGridProject) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this TestGridProject.
+func (mg *TestGridProject) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this TestGridProject.
+func (mg *TestGridProject) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this TestGridProject.
+func (mg *TestGridProject) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&TestGridProject{}, &TestGridProjectList{})
 }
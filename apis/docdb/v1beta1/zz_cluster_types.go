@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type ClusterObservation struct {
@@ -148,6 +149,11 @@ type ClusterParameters struct {
 	// +kubebuilder:validation:Optional
 	Tags map[string]*string `json:"tags,omitempty" tf:"tags,omitempty"`
 
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []TimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+
 	// References to SecurityGroup in ec2 to populate vpcSecurityGroupIds.
 	// +kubebuilder:validation:Optional
 	VPCSecurityGroupIDRefs []v1.Reference `json:"vpcSecurityGroupIdRefs,omitempty" tf:"-"`
@@ -165,6 +171,28 @@ type ClusterParameters struct {
 	VPCSecurityGroupIds []*string `json:"vpcSecurityGroupIds,omitempty" tf:"vpc_security_group_ids,omitempty"`
 }
 
+type TimeoutsObservation struct {
+}
+
+type TimeoutsParameters struct {
+
+	// (Default 120 minutes) Used for Cluster creation
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 120 minutes) Used for destroying cluster. This includes
+	// any cleanup task during the destroying process.
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 120 minutes) Used for Cluster modifications
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 // ClusterSpec defines the desired state of Cluster
 type ClusterSpec struct {
 	v1.ResourceSpec `json:",inline"`
@@ -175,6 +203,16 @@ type ClusterSpec struct {
 type ClusterStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        ClusterObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -210,6 +248,26 @@ var (
 	Cluster_GroupVersionKind = CRDGroupVersion.WithKind(Cluster_Kind)
 )
 
+// GetPlannedChanges of this Cluster.
+func (mg *Cluster) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Cluster.
+func (mg *Cluster) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Cluster.
+func (mg *Cluster) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Cluster.
+func (mg *Cluster) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
 }
@@ -11,6 +11,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -268,6 +269,13 @@ func (in *ClusterInstanceParameters) DeepCopyInto(out *ClusterInstanceParameters
 			(*out)[key] = outVal
 		}
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = make([]ClusterInstanceTimeoutsParameters, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInstanceParameters.
@@ -302,6 +310,16 @@ func (in *ClusterInstanceStatus) DeepCopyInto(out *ClusterInstanceStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInstanceStatus.
@@ -314,6 +332,51 @@ func (in *ClusterInstanceStatus) DeepCopy() *ClusterInstanceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInstanceTimeoutsObservation) DeepCopyInto(out *ClusterInstanceTimeoutsObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInstanceTimeoutsObservation.
+func (in *ClusterInstanceTimeoutsObservation) DeepCopy() *ClusterInstanceTimeoutsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInstanceTimeoutsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInstanceTimeoutsParameters) DeepCopyInto(out *ClusterInstanceTimeoutsParameters) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = new(string)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(string)
+		**out = **in
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInstanceTimeoutsParameters.
+func (in *ClusterInstanceTimeoutsParameters) DeepCopy() *ClusterInstanceTimeoutsParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInstanceTimeoutsParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -562,6 +625,13 @@ func (in *ClusterParameters) DeepCopyInto(out *ClusterParameters) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = make([]TimeoutsParameters, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.VPCSecurityGroupIDRefs != nil {
 		in, out := &in.VPCSecurityGroupIDRefs, &out.VPCSecurityGroupIDRefs
 		*out = make([]v1.Reference, len(*in))
@@ -619,6 +689,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -825,6 +905,13 @@ func (in *GlobalClusterParameters) DeepCopyInto(out *GlobalClusterParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = make([]GlobalClusterTimeoutsParameters, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalClusterParameters.
@@ -859,6 +946,16 @@ func (in *GlobalClusterStatus) DeepCopyInto(out *GlobalClusterStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalClusterStatus.
@@ -871,6 +968,51 @@ func (in *GlobalClusterStatus) DeepCopy() *GlobalClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalClusterTimeoutsObservation) DeepCopyInto(out *GlobalClusterTimeoutsObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalClusterTimeoutsObservation.
+func (in *GlobalClusterTimeoutsObservation) DeepCopy() *GlobalClusterTimeoutsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalClusterTimeoutsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalClusterTimeoutsParameters) DeepCopyInto(out *GlobalClusterTimeoutsParameters) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = new(string)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(string)
+		**out = **in
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalClusterTimeoutsParameters.
+func (in *GlobalClusterTimeoutsParameters) DeepCopy() *GlobalClusterTimeoutsParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalClusterTimeoutsParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetGroup) DeepCopyInto(out *SubnetGroup) {
 	*out = *in
@@ -1055,6 +1197,16 @@ func (in *SubnetGroupStatus) DeepCopyInto(out *SubnetGroupStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = new(apisv1beta1.ChangeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(apisv1beta1.DriftReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetGroupStatus.
@@ -1066,3 +1218,48 @@ func (in *SubnetGroupStatus) DeepCopy() *SubnetGroupStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsObservation) DeepCopyInto(out *TimeoutsObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsObservation.
+func (in *TimeoutsObservation) DeepCopy() *TimeoutsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsParameters) DeepCopyInto(out *TimeoutsParameters) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = new(string)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(string)
+		**out = **in
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsParameters.
+func (in *TimeoutsParameters) DeepCopy() *TimeoutsParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsParameters)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type GlobalClusterMembersObservation struct {
@@ -86,6 +87,32 @@ type GlobalClusterParameters struct {
 	// Specifies whether the DB cluster is encrypted. The default is false unless source_db_cluster_identifier is specified and encrypted.
 	// +kubebuilder:validation:Optional
 	StorageEncrypted *bool `json:"storageEncrypted,omitempty" tf:"storage_encrypted,omitempty"`
+
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []GlobalClusterTimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+}
+
+type GlobalClusterTimeoutsObservation struct {
+}
+
+type GlobalClusterTimeoutsParameters struct {
+
+	// (Defaults to 5 mins) Used when creating the Global Cluster
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Defaults to 5 mins) Used when deleting the Global Cluster members (time is per member)
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Defaults to 5 mins) Used when updating the Global Cluster members (time is per member)
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
 }
 
 // GlobalClusterSpec defines the desired state of GlobalCluster
@@ -98,6 +125,16 @@ type GlobalClusterSpec struct {
 type GlobalClusterStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        GlobalClusterObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -133,6 +170,26 @@ var (
 	GlobalCluster_GroupVersionKind = CRDGroupVersion.WithKind(GlobalCluster_Kind)
 )
 
+// GetPlannedChanges of this GlobalCluster.
+func (mg *GlobalCluster) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this GlobalCluster.
+func (mg *GlobalCluster) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this GlobalCluster.
+func (mg *GlobalCluster) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this GlobalCluster.
+func (mg *GlobalCluster) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&GlobalCluster{}, &GlobalClusterList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type AttributeObservation struct {
@@ -240,6 +241,11 @@ type TableParameters struct {
 	// +kubebuilder:validation:Optional
 	Tags map[string]*string `json:"tags,omitempty" tf:"tags,omitempty"`
 
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []TimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+
 	// The number of write units for this table. If the billing_mode is PROVISIONED, this field is required.
 	// +kubebuilder:validation:Optional
 	WriteCapacity *float64 `json:"writeCapacity,omitempty" tf:"write_capacity,omitempty"`
@@ -259,6 +265,27 @@ type TableReplicaParameters struct {
 	RegionName *string `json:"regionName" tf:"region_name,omitempty"`
 }
 
+type TimeoutsObservation struct {
+}
+
+type TimeoutsParameters struct {
+
+	// (Defaults to 10 mins) Used when creating the table
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Defaults to 10 mins) Used when deleting the table
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Defaults to 60 mins) Used when updating the table configuration and reset for each individual Global Secondary Index and Replica update
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 // TableSpec defines the desired state of Table
 type TableSpec struct {
 	v1.ResourceSpec `json:",inline"`
@@ -269,6 +296,16 @@ type TableSpec struct {
 type TableStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        TableObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -304,6 +341,26 @@ var (
 	Table_GroupVersionKind = CRDGroupVersion.WithKind(Table_Kind)
 )
 
+// GetPlannedChanges of this Table.
+func (mg *Table) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Table.
+func (mg *Table) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Table.
+func (mg *Table) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Table.
+func (mg *Table) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Table{}, &TableList{})
 }
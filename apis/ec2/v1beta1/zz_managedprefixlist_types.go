@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type EntryObservation struct {
@@ -93,6 +94,16 @@ type ManagedPrefixListSpec struct {
 type ManagedPrefixListStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        ManagedPrefixListObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -128,6 +139,26 @@ var (
 	ManagedPrefixList_GroupVersionKind = CRDGroupVersion.WithKind(ManagedPrefixList_Kind)
 )
 
+// GetPlannedChanges of this ManagedPrefixList.
+func (mg *ManagedPrefixList) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this ManagedPrefixList.
+func (mg *ManagedPrefixList) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this ManagedPrefixList.
+func (mg *ManagedPrefixList) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this ManagedPrefixList.
+func (mg *ManagedPrefixList) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&ManagedPrefixList{}, &ManagedPrefixListList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type EgressObservation struct {
@@ -156,6 +157,16 @@ type NetworkACLSpec struct {
 type NetworkACLStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        NetworkACLObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -191,6 +202,26 @@ var (
 	NetworkACL_GroupVersionKind = CRDGroupVersion.WithKind(NetworkACL_Kind)
 )
 
+// GetPlannedChanges of this NetworkACL.
+func (mg *NetworkACL) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this NetworkACL.
+func (mg *NetworkACL) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this NetworkACL.
+func (mg *NetworkACL) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this NetworkACL.
+func (mg *NetworkACL) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&NetworkACL{}, &NetworkACLList{})
 }
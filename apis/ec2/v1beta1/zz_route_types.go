@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type RouteObservation_2 struct {
@@ -138,6 +139,11 @@ type RouteParameters_2 struct {
 	// +kubebuilder:validation:Optional
 	RouteTableIDSelector *v1.Selector `json:"routeTableIdSelector,omitempty" tf:"-"`
 
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []RouteTimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+
 	// Identifier of an EC2 Transit Gateway.
 	// +crossplane:generate:reference:type=TransitGateway
 	// +kubebuilder:validation:Optional
@@ -178,6 +184,27 @@ type RouteParameters_2 struct {
 	VPCPeeringConnectionIDSelector *v1.Selector `json:"vpcPeeringConnectionIdSelector,omitempty" tf:"-"`
 }
 
+type RouteTimeoutsObservation struct {
+}
+
+type RouteTimeoutsParameters struct {
+
+	// (Default 5 minutes) Used for route creation
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 5 minutes) Used for route deletion
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 2 minutes) Used for route creation
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 // RouteSpec defines the desired state of Route
 type RouteSpec struct {
 	v1.ResourceSpec `json:",inline"`
@@ -188,6 +215,16 @@ type RouteSpec struct {
 type RouteStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        RouteObservation_2 `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -223,6 +260,26 @@ var (
 	Route_GroupVersionKind = CRDGroupVersion.WithKind(Route_Kind)
 )
 
+// GetPlannedChanges of this Route.
+func (mg *Route) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this Route.
+func (mg *Route) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this Route.
+func (mg *Route) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this Route.
+func (mg *Route) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&Route{}, &RouteList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type VPCEndpointSubnetAssociationObservation struct {
@@ -64,6 +65,16 @@ type VPCEndpointSubnetAssociationSpec struct {
 type VPCEndpointSubnetAssociationStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        VPCEndpointSubnetAssociationObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -99,6 +110,26 @@ var (
 	VPCEndpointSubnetAssociation_GroupVersionKind = CRDGroupVersion.WithKind(VPCEndpointSubnetAssociation_Kind)
 )
 
+// GetPlannedChanges of this VPCEndpointSubnetAssociation.
+func (mg *VPCEndpointSubnetAssociation) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this VPCEndpointSubnetAssociation.
+func (mg *VPCEndpointSubnetAssociation) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this VPCEndpointSubnetAssociation.
+func (mg *VPCEndpointSubnetAssociation) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this VPCEndpointSubnetAssociation.
+func (mg *VPCEndpointSubnetAssociation) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&VPCEndpointSubnetAssociation{}, &VPCEndpointSubnetAssociationList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type FargateProfileObservation struct {
@@ -84,6 +85,31 @@ type FargateProfileParameters struct {
 	// Key-value map of resource tags.
 	// +kubebuilder:validation:Optional
 	Tags map[string]*string `json:"tags,omitempty" tf:"tags,omitempty"`
+
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []FargateProfileTimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+}
+
+type FargateProfileTimeoutsObservation struct {
+}
+
+type FargateProfileTimeoutsParameters struct {
+
+	// (Default 10 minutes) How long to wait for the EKS Fargate Profile to be created.
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 10 minutes) How long to wait for the EKS Fargate Profile to be deleted.
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
 }
 
 type SelectorObservation struct {
@@ -110,6 +136,16 @@ type FargateProfileSpec struct {
 type FargateProfileStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        FargateProfileObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -145,6 +181,26 @@ var (
 	FargateProfile_GroupVersionKind = CRDGroupVersion.WithKind(FargateProfile_Kind)
 )
 
+// GetPlannedChanges of this FargateProfile.
+func (mg *FargateProfile) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this FargateProfile.
+func (mg *FargateProfile) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this FargateProfile.
+func (mg *FargateProfile) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this FargateProfile.
+func (mg *FargateProfile) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&FargateProfile{}, &FargateProfileList{})
 }
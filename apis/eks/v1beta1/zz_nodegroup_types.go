@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type AutoscalingGroupsObservation struct {
@@ -156,6 +157,11 @@ type NodeGroupParameters struct {
 	// +kubebuilder:validation:Optional
 	Taint []TaintParameters `json:"taint,omitempty" tf:"taint,omitempty"`
 
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []NodeGroupTimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+
 	// +kubebuilder:validation:Optional
 	UpdateConfig []UpdateConfigParameters `json:"updateConfig,omitempty" tf:"update_config,omitempty"`
 
@@ -164,6 +170,27 @@ type NodeGroupParameters struct {
 	Version *string `json:"version,omitempty" tf:"version,omitempty"`
 }
 
+type NodeGroupTimeoutsObservation struct {
+}
+
+type NodeGroupTimeoutsParameters struct {
+
+	// (Default 60 minutes) How long to wait for the EKS Node Group to be created.
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 60 minutes) How long to wait for the EKS Node Group to be deleted.
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 60 minutes) How long to wait for the EKS Node Group to be updated. Note that the update timeout is used separately for both configuration and version update operations.
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
+}
+
 type RemoteAccessObservation struct {
 }
 
@@ -261,6 +288,16 @@ type NodeGroupSpec struct {
 type NodeGroupStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        NodeGroupObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -296,6 +333,26 @@ var (
 	NodeGroup_GroupVersionKind = CRDGroupVersion.WithKind(NodeGroup_Kind)
 )
 
+// GetPlannedChanges of this NodeGroup.
+func (mg *NodeGroup) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this NodeGroup.
+func (mg *NodeGroup) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this NodeGroup.
+func (mg *NodeGroup) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this NodeGroup.
+func (mg *NodeGroup) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&NodeGroup{}, &NodeGroupList{})
 }
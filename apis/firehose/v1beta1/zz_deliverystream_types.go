@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type CloudwatchLoggingOptionsObservation struct {
@@ -1269,6 +1270,16 @@ type DeliveryStreamSpec struct {
 type DeliveryStreamStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        DeliveryStreamObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1304,6 +1315,26 @@ var (
 	DeliveryStream_GroupVersionKind = CRDGroupVersion.WithKind(DeliveryStream_Kind)
 )
 
+// GetPlannedChanges of this DeliveryStream.
+func (mg *DeliveryStream) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this DeliveryStream.
+func (mg *DeliveryStream) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this DeliveryStream.
+func (mg *DeliveryStream) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this DeliveryStream.
+func (mg *DeliveryStream) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&DeliveryStream{}, &DeliveryStreamList{})
 }
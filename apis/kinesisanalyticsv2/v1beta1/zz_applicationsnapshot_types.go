@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type ApplicationSnapshotObservation struct {
@@ -56,6 +57,16 @@ type ApplicationSnapshotSpec struct {
 type ApplicationSnapshotStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        ApplicationSnapshotObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -91,6 +102,26 @@ var (
 	ApplicationSnapshot_GroupVersionKind = CRDGroupVersion.WithKind(ApplicationSnapshot_Kind)
 )
 
+// GetPlannedChanges of this ApplicationSnapshot.
+func (mg *ApplicationSnapshot) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this ApplicationSnapshot.
+func (mg *ApplicationSnapshot) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this ApplicationSnapshot.
+func (mg *ApplicationSnapshot) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this ApplicationSnapshot.
+func (mg *ApplicationSnapshot) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&ApplicationSnapshot{}, &ApplicationSnapshotList{})
 }
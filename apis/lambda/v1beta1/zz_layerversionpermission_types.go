@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type LayerVersionPermissionObservation struct {
@@ -67,6 +68,16 @@ type LayerVersionPermissionSpec struct {
 type LayerVersionPermissionStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        LayerVersionPermissionObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -102,6 +113,26 @@ var (
 	LayerVersionPermission_GroupVersionKind = CRDGroupVersion.WithKind(LayerVersionPermission_Kind)
 )
 
+// GetPlannedChanges of this LayerVersionPermission.
+func (mg *LayerVersionPermission) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this LayerVersionPermission.
+func (mg *LayerVersionPermission) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this LayerVersionPermission.
+func (mg *LayerVersionPermission) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this LayerVersionPermission.
+func (mg *LayerVersionPermission) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&LayerVersionPermission{}, &LayerVersionPermissionList{})
 }
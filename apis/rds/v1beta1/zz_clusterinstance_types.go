@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type ClusterInstanceObservation struct {
@@ -185,6 +186,34 @@ type ClusterInstanceParameters struct {
 	// Key-value map of resource tags.
 	// +kubebuilder:validation:Optional
 	Tags map[string]*string `json:"tags,omitempty" tf:"tags,omitempty"`
+
+	// Timeouts of the create, update and delete operations of this resource, such as 60m. The operations are limited to one hour regardless of these timeouts.
+	// +upjet:crd:field:TFTag=-
+	// +kubebuilder:validation:Optional
+	Timeouts []ClusterInstanceTimeoutsParameters `json:"timeouts,omitempty" tf:"-"`
+}
+
+type ClusterInstanceTimeoutsObservation struct {
+}
+
+type ClusterInstanceTimeoutsParameters struct {
+
+	// (Default 90 minutes) Used for Creating Instances, Replicas, and
+	// restoring from Snapshots
+	// Timeout of the create operation.
+	// +kubebuilder:validation:Optional
+	Create *string `json:"create,omitempty" tf:"create,omitempty"`
+
+	// (Default 90 minutes) Used for destroying databases. This includes
+	// the time required to take snapshots
+	// Timeout of the delete operation.
+	// +kubebuilder:validation:Optional
+	Delete *string `json:"delete,omitempty" tf:"delete,omitempty"`
+
+	// (Default 90 minutes) Used for Database modifications
+	// Timeout of the update operation.
+	// +kubebuilder:validation:Optional
+	Update *string `json:"update,omitempty" tf:"update,omitempty"`
 }
 
 // ClusterInstanceSpec defines the desired state of ClusterInstance
@@ -197,6 +226,16 @@ type ClusterInstanceSpec struct {
 type ClusterInstanceStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        ClusterInstanceObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -232,6 +271,26 @@ var (
 	ClusterInstance_GroupVersionKind = CRDGroupVersion.WithKind(ClusterInstance_Kind)
 )
 
+// GetPlannedChanges of this ClusterInstance.
+func (mg *ClusterInstance) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this ClusterInstance.
+func (mg *ClusterInstance) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this ClusterInstance.
+func (mg *ClusterInstance) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this ClusterInstance.
+func (mg *ClusterInstance) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&ClusterInstance{}, &ClusterInstanceList{})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
 )
 
 type BucketObjectLockConfigurationObservation struct {
@@ -95,6 +96,16 @@ type BucketObjectLockConfigurationSpec struct {
 type BucketObjectLockConfigurationStatus struct {
 	v1.ResourceStatus `json:",inline"`
 	AtProvider        BucketObjectLockConfigurationObservation `json:"atProvider,omitempty"`
+
+	// PlannedChanges are the changes that are planned but not applied to
+	// the external resource because of the dry-run mode.
+	// +optional
+	PlannedChanges *apisv1beta1.ChangeSummary `json:"plannedChanges,omitempty"`
+
+	// Drift reports the differences of the external resource from the
+	// desired state that were not made by the provider.
+	// +optional
+	Drift *apisv1beta1.DriftReport `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -130,6 +141,26 @@ var (
 	BucketObjectLockConfiguration_GroupVersionKind = CRDGroupVersion.WithKind(BucketObjectLockConfiguration_Kind)
 )
 
+// GetPlannedChanges of this BucketObjectLockConfiguration.
+func (mg *BucketObjectLockConfiguration) GetPlannedChanges() *apisv1beta1.ChangeSummary {
+	return mg.Status.PlannedChanges
+}
+
+// SetPlannedChanges of this BucketObjectLockConfiguration.
+func (mg *BucketObjectLockConfiguration) SetPlannedChanges(c *apisv1beta1.ChangeSummary) {
+	mg.Status.PlannedChanges = c
+}
+
+// GetDrift of this BucketObjectLockConfiguration.
+func (mg *BucketObjectLockConfiguration) GetDrift() *apisv1beta1.DriftReport {
+	return mg.Status.Drift
+}
+
+// SetDrift of this BucketObjectLockConfiguration.
+func (mg *BucketObjectLockConfiguration) SetDrift(r *apisv1beta1.DriftReport) {
+	mg.Status.Drift = r
+}
+
 func init() {
 	SchemeBuilder.Register(&BucketObjectLockConfiguration{}, &BucketObjectLockConfigurationList{})
 }
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A PlannedAction is an action that Terraform plans to take on an external
+// resource.
+type PlannedAction string
+
+// Planned actions.
+const (
+	PlannedActionCreate  PlannedAction = "Create"
+	PlannedActionUpdate  PlannedAction = "Update"
+	PlannedActionReplace PlannedAction = "Replace"
+	PlannedActionDelete  PlannedAction = "Delete"
+)
+
+// A ChangeSummary summarizes the changes that Terraform plans to make to an
+// external resource.
+type ChangeSummary struct {
+	// Action is the planned action on the external resource.
+	// +kubebuilder:validation:Enum=Create;Update;Replace;Delete
+	Action PlannedAction `json:"action"`
+
+	// Changes are the planned changes of the attributes of the external
+	// resource.
+	// +optional
+	Changes []AttributeChange `json:"changes,omitempty"`
+
+	// Generation of the managed resource the changes were planned for.
+	Generation int64 `json:"generation"`
+
+	// PlannedAt is the time the changes were planned.
+	PlannedAt metav1.Time `json:"plannedAt"`
+}
+
+// An AttributeChange is a planned change of an attribute of an external
+// resource.
+type AttributeChange struct {
+	// Path of the attribute, such as engine_version.
+	Path string `json:"path"`
+
+	// Before is the JSON encoded current value of the attribute. The values
+	// of sensitive attributes are redacted.
+	// +optional
+	Before *string `json:"before,omitempty"`
+
+	// After is the JSON encoded planned value of the attribute. The values
+	// of sensitive attributes are redacted and the values that are only known
+	// after the changes are applied are omitted.
+	// +optional
+	After *string `json:"after,omitempty"`
+
+	// ForcesReplacement is true if the change of the attribute forces the
+	// replacement of the external resource.
+	// +optional
+	ForcesReplacement bool `json:"forcesReplacement,omitempty"`
+}
+
+// A DriftReport reports the differences of an external resource from the
+// desired state of its managed resource that were not made by the provider.
+type DriftReport struct {
+	// SyncedGeneration is the last generation of the managed resource that
+	// the external resource was observed to be in sync with. Differences
+	// observed for later generations are caused by changes of the managed
+	// resource and are not reported as drift.
+	// +optional
+	SyncedGeneration int64 `json:"syncedGeneration,omitempty"`
+
+	// DetectedAt is the time the drift was detected.
+	// +optional
+	DetectedAt *metav1.Time `json:"detectedAt,omitempty"`
+
+	// Fields are the drifted attributes, whose Before values are the ones of
+	// the external resource and After values are the desired ones.
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	Fields []AttributeChange `json:"fields,omitempty"`
+
+	// Truncated is true if more attributes drifted than are listed in
+	// Fields.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A FinalSnapshotState is the state of a final snapshot.
+type FinalSnapshotState string
+
+// Final snapshot states.
+const (
+	FinalSnapshotStateCreating  FinalSnapshotState = "Creating"
+	FinalSnapshotStateAvailable FinalSnapshotState = "Available"
+)
+
+// FinalSnapshotSpec identifies a final snapshot and the managed resource it
+// was taken of.
+type FinalSnapshotSpec struct {
+	// ResourceRef is the managed resource whose external resource the
+	// snapshot was taken of.
+	ResourceRef xpv1.TypedReference `json:"resourceRef"`
+
+	// ExternalName of the managed resource, which identifies its external
+	// resource.
+	ExternalName string `json:"externalName"`
+
+	// Region of the snapshot.
+	Region string `json:"region"`
+
+	// SnapshotType is the type of the snapshot, such as DBSnapshot or
+	// DBClusterSnapshot.
+	SnapshotType string `json:"snapshotType"`
+
+	// SnapshotIdentifier is the identifier of the snapshot.
+	SnapshotIdentifier string `json:"snapshotIdentifier"`
+}
+
+// FinalSnapshotStatus is the observed state of a final snapshot.
+type FinalSnapshotStatus struct {
+	// State of the snapshot.
+	// +optional
+	// +kubebuilder:validation:Enum=Creating;Available
+	State FinalSnapshotState `json:"state,omitempty"`
+
+	// AvailableAt is the time the snapshot was observed to be available.
+	// +optional
+	AvailableAt *metav1.Time `json:"availableAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A FinalSnapshot records the snapshot taken by the provider before the
+// deletion of the external resource of a database managed resource. It is not
+// owned by the managed resource, so it is kept after the managed resource is
+// deleted.
+// +kubebuilder:printcolumn:name="RESOURCE-KIND",type="string",JSONPath=".spec.resourceRef.kind"
+// +kubebuilder:printcolumn:name="RESOURCE-NAME",type="string",JSONPath=".spec.resourceRef.name"
+// +kubebuilder:printcolumn:name="SNAPSHOT",type="string",JSONPath=".spec.snapshotIdentifier"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,aws}
+type FinalSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FinalSnapshotSpec   `json:"spec"`
+	Status FinalSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FinalSnapshotList contains a list of FinalSnapshot
+type FinalSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FinalSnapshot `json:"items"`
+}
@@ -46,7 +46,16 @@ var (
 	ProviderConfigUsageListGroupVersionKind = SchemeGroupVersion.WithKind(ProviderConfigUsageListKind)
 )
 
+// FinalSnapshot type metadata.
+var (
+	FinalSnapshotKind             = reflect.TypeOf(FinalSnapshot{}).Name()
+	FinalSnapshotGroupKind        = schema.GroupKind{Group: Group, Kind: FinalSnapshotKind}.String()
+	FinalSnapshotKindAPIVersion   = FinalSnapshotKind + "." + SchemeGroupVersion.String()
+	FinalSnapshotGroupVersionKind = SchemeGroupVersion.WithKind(FinalSnapshotKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ProviderConfig{}, &ProviderConfigList{})
 	SchemeBuilder.Register(&ProviderConfigUsage{}, &ProviderConfigUsageList{})
+	SchemeBuilder.Register(&FinalSnapshot{}, &FinalSnapshotList{})
 }
@@ -21,8 +21,68 @@ type ProviderConfigSpec struct {
 	// of AWS calls made by the provider.
 	// +optional
 	Endpoint *EndpointConfig `json:"endpoint,omitempty"`
+
+	// DryRun makes the managed resources that use this ProviderConfig only
+	// plan their changes, which are reported in their status and events,
+	// instead of applying them. It can be overridden per managed resource
+	// with the aws.upbound.io/dry-run annotation.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// AdoptionPolicy determines whether the managed resources that use this
+	// ProviderConfig take over existing external resources they did not
+	// create. Adopt takes them over, AdoptIfTagged only takes over the ones
+	// tagged with the kind and name of the managed resource, and Fail
+	// refuses to take them over. It can be overridden per managed resource
+	// with the aws.upbound.io/adoption-policy annotation.
+	// +kubebuilder:validation:Enum=Adopt;AdoptIfTagged;Fail
+	// +kubebuilder:default=Adopt
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// ReadOnly makes the managed resources that use this ProviderConfig only
+	// observe their external resources, which are never created, updated or
+	// deleted. Their differences from the desired state are reported with
+	// the Drifted condition and events.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// FreezeWindows are the recurring periods during which the external
+	// resources of the managed resources that use this ProviderConfig are
+	// not created, updated or deleted. They are still observed and their
+	// changes are deferred until no window is active. A managed resource can
+	// bypass the freeze with the aws.upbound.io/bypass-freeze annotation.
+	// +optional
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
 }
 
+// A FreezeWindow is a recurring period during which external resources are
+// not changed.
+type FreezeWindow struct {
+	// Schedule is the cron expression of the starts of the window, such as
+	// "0 18 * * FRI" for every Friday at 18:00.
+	Schedule string `json:"schedule"`
+
+	// Duration of the window, such as 60h.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is evaluated in, such as
+	// America/New_York. It defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// An AdoptionPolicy determines whether managed resources take over existing
+// external resources they did not create.
+type AdoptionPolicy string
+
+// Adoption policies.
+const (
+	AdoptionPolicyAdopt         AdoptionPolicy = "Adopt"
+	AdoptionPolicyAdoptIfTagged AdoptionPolicy = "AdoptIfTagged"
+	AdoptionPolicyFail          AdoptionPolicy = "Fail"
+)
+
 // AssumeRoleOptions define the options for assuming an IAM Role
 // Fields are similar to the STS AssumeRoleOptions in the AWS SDK
 type AssumeRoleOptions struct {
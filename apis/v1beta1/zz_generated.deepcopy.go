@@ -70,6 +70,80 @@ func (in *AssumeRoleWithWebIdentityOptions) DeepCopy() *AssumeRoleWithWebIdentit
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttributeChange) DeepCopyInto(out *AttributeChange) {
+	*out = *in
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = new(string)
+		**out = **in
+	}
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttributeChange.
+func (in *AttributeChange) DeepCopy() *AttributeChange {
+	if in == nil {
+		return nil
+	}
+	out := new(AttributeChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeSummary) DeepCopyInto(out *ChangeSummary) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]AttributeChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.PlannedAt.DeepCopyInto(&out.PlannedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeSummary.
+func (in *ChangeSummary) DeepCopy() *ChangeSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftReport) DeepCopyInto(out *DriftReport) {
+	*out = *in
+	if in.DetectedAt != nil {
+		in, out := &in.DetectedAt, &out.DetectedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]AttributeChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftReport.
+func (in *DriftReport) DeepCopy() *DriftReport {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicURLConfig) DeepCopyInto(out *DynamicURLConfig) {
 	*out = *in
@@ -131,6 +205,116 @@ func (in *EndpointConfig) DeepCopy() *EndpointConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshot) DeepCopyInto(out *FinalSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshot.
+func (in *FinalSnapshot) DeepCopy() *FinalSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FinalSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshotList) DeepCopyInto(out *FinalSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FinalSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshotList.
+func (in *FinalSnapshotList) DeepCopy() *FinalSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FinalSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshotSpec) DeepCopyInto(out *FinalSnapshotSpec) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshotSpec.
+func (in *FinalSnapshotSpec) DeepCopy() *FinalSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshotStatus) DeepCopyInto(out *FinalSnapshotStatus) {
+	*out = *in
+	if in.AvailableAt != nil {
+		in, out := &in.AvailableAt, &out.AvailableAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshotStatus.
+func (in *FinalSnapshotStatus) DeepCopy() *FinalSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeWindow) DeepCopyInto(out *FreezeWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeWindow.
+func (in *FreezeWindow) DeepCopy() *FreezeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -206,6 +390,11 @@ func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 		*out = new(EndpointConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FreezeWindows != nil {
+		in, out := &in.FreezeWindows, &out.FreezeWindows
+		*out = make([]FreezeWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
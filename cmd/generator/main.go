@@ -15,17 +15,32 @@
 package main
 
 import (
+	// Note: we are importing this to embed the controller and CRD types templates
+	_ "embed"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/upbound/upjet/pkg/pipeline"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/upbound/provider-aws/config"
 )
 
+// controllerTemplate is used instead of the upjet controller template so that
+// the generated controllers use the provider's connector.
+//
+//go:embed templates/controller.go.tmpl
+var controllerTemplate string
+
+// crdTypesTemplate is used instead of the upjet CRD types template so that the
+// generated managed resources report the changes planned in dry-run mode.
+//
+//go:embed templates/crd_types.go.tmpl
+var crdTypesTemplate string
+
 func main() {
 	var (
 		app                 = kingpin.New("generator", "Run Upjet code generation pipelines for provider-aws").DefaultEnvars()
@@ -38,8 +53,22 @@ func main() {
 	if err != nil {
 		panic(fmt.Sprintf("cannot calculate the absolute path with %s", *repoRoot))
 	}
-	p := config.GetProvider()
+	configDir := filepath.Join(absRootDir, "config")
+	schema, err := os.ReadFile(filepath.Join(configDir, config.SchemaFile))
+	if err != nil {
+		panic(fmt.Sprintf("cannot read the provider schema: %s", err.Error()))
+	}
+	metadata, err := os.ReadFile(filepath.Join(configDir, config.MetadataFile))
+	if err != nil {
+		panic(fmt.Sprintf("cannot read the provider metadata: %s", err.Error()))
+	}
+	p := config.GetProvider(schema, metadata)
+	templates.ControllerTemplate = controllerTemplate
+	templates.CRDTypesTemplate = crdTypesTemplate
 	pipeline.Run(p, absRootDir)
+	if err := config.WriteTrimmed(p, schema, metadata, configDir); err != nil {
+		panic(fmt.Sprintf("cannot write the trimmed provider configuration: %s", err.Error()))
+	}
 	if len(*skippedResourcesCSV) != 0 {
 		skippedCount := len(p.GetSkippedResourceNames())
 		totalCount := skippedCount + len(p.Resources)
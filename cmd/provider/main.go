@@ -6,31 +6,50 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	xpcontroller "github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/go-logr/logr"
 	tjcontroller "github.com/upbound/upjet/pkg/controller"
 	"github.com/upbound/upjet/pkg/terraform"
 	"gopkg.in/alecthomas/kingpin.v2"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/upbound/provider-aws/apis"
 	"github.com/upbound/provider-aws/apis/v1alpha1"
 	"github.com/upbound/provider-aws/config"
 	"github.com/upbound/provider-aws/internal/clients"
+	"github.com/upbound/provider-aws/internal/connector"
 	"github.com/upbound/provider-aws/internal/controller"
+	"github.com/upbound/provider-aws/internal/encryption"
 	"github.com/upbound/provider-aws/internal/features"
+	"github.com/upbound/provider-aws/internal/logger"
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/native"
+	"github.com/upbound/provider-aws/internal/persistence"
+	"github.com/upbound/provider-aws/internal/plugin"
+	"github.com/upbound/provider-aws/internal/poll"
+	"github.com/upbound/provider-aws/internal/sharedprovider"
+	"github.com/upbound/provider-aws/internal/tracing"
+	"github.com/upbound/provider-aws/internal/workspace"
 )
 
 func main() {
@@ -39,6 +58,8 @@ func main() {
 		debug              = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncInterval       = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
 		pollInterval       = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("10m").Duration()
+		pollIntervals      = app.Flag("poll-intervals", "Comma separated poll interval overrides for groups or kinds, such as organizations=1h,ec2.SecurityGroupRule=1m. Individual resources can override it with the "+poll.AnnotationKeyPollInterval+" annotation.").Default("").Envar("POLL_INTERVALS").String()
+		reconcileTimeouts  = app.Flag("reconcile-timeouts", "Comma separated reconcile timeout overrides for groups or kinds, such as rds=5m,cloudfront.Distribution=10m.").Default("").Envar("RECONCILE_TIMEOUTS").String()
 		leaderElection     = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
 		maxReconcileRate   = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may be checked for drift from the desired state.").Default("10").Int()
 		terraformVersion   = app.Flag("terraform-version", "Terraform version.").Required().Envar("TERRAFORM_VERSION").String()
@@ -46,13 +67,68 @@ func main() {
 		providerVersion    = app.Flag("terraform-provider-version", "Terraform provider version.").Required().Envar("TERRAFORM_PROVIDER_VERSION").String()
 		nativeProviderPath = app.Flag("terraform-native-provider-path", "Terraform native provider path for shared execution.").Default("").Envar("TERRAFORM_NATIVE_PROVIDER_PATH").String()
 
+		providerMirrorDir    = app.Flag("terraform-provider-mirror-dir", "Directory of a Terraform provider filesystem mirror in the unpacked layout to install the Terraform provider from instead of the Terraform registry.").Default("").Envar("TERRAFORM_PROVIDER_MIRROR_DIR").String()
+		providerMirrorURL    = app.Flag("terraform-provider-mirror-url", "Base URL of a Terraform provider network mirror to install the Terraform provider from instead of the Terraform registry.").Default("").Envar("TERRAFORM_PROVIDER_MIRROR_URL").String()
+		providerChecksum     = app.Flag("terraform-provider-checksum", "Hex encoded SHA-256 checksum of the Terraform provider binary of the version set with --terraform-provider-version. The provider binary installed from the mirror and the native provider binary are verified against it at startup, which fails if it is not set while a mirror is configured.").Default("").Envar("TERRAFORM_PROVIDER_CHECKSUM").String()
+		providerChecksumFile = app.Flag("terraform-provider-checksum-file", "File holding the checksum of --terraform-provider-checksum, such as the output of sha256sum.").Default("").Envar("TERRAFORM_PROVIDER_CHECKSUM_FILE").String()
+		providerSkipChecksum = app.Flag("terraform-provider-skip-checksum", "Do not verify the checksum of the Terraform provider binary while a mirror is configured. The archives served by a network mirror are still verified against the hashes the mirror publishes.").Default("false").Envar("TERRAFORM_PROVIDER_SKIP_CHECKSUM").Bool()
+
+		nativeProviderProcesses     = app.Flag("terraform-native-provider-processes", "Number of shared native provider processes the Terraform operations are balanced across.").Default("1").Envar("TERRAFORM_NATIVE_PROVIDER_PROCESSES").Int()
+		nativeProviderMaxOperations = app.Flag("terraform-native-provider-max-operations", "Number of Terraform operations after which a shared native provider process is recycled. Never recycled if zero.").Default("0").Envar("TERRAFORM_NATIVE_PROVIDER_MAX_OPERATIONS").Int()
+		nativeProviderMaxMemory     = app.Flag("terraform-native-provider-max-memory", "Resident memory above which a shared native provider process is recycled, such as 2Gi. Never recycled if empty.").Default("").Envar("TERRAFORM_NATIVE_PROVIDER_MAX_MEMORY").String()
+		nativeProviderHealth        = app.Flag("terraform-native-provider-health-interval", "Interval at which the shared native provider processes are health checked.").Default("30s").Envar("TERRAFORM_NATIVE_PROVIDER_HEALTH_INTERVAL").Duration()
+		nativeProviderDrainTimeout  = app.Flag("terraform-native-provider-drain-timeout", "Time a recycled shared native provider process keeps running after it was last used, so that the operations started with it can finish.").Default("1h").Envar("TERRAFORM_NATIVE_PROVIDER_DRAIN_TIMEOUT").Duration()
+
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+		enableNativeClients        = app.Flag("enable-native-clients", "Enable the external clients that manage the SecurityGroupRule, Record, RolePolicyAttachment and BucketPolicy resources with the AWS SDK instead of Terraform.").Default("false").Envar("ENABLE_NATIVE_CLIENTS").Bool()
+		nativeBatchWindow          = app.Flag("native-clients-batch-window", "Window in which the SecurityGroupRule and Record native clients serve their observations from a single list call per account, region and kind. Every resource is observed on its own if zero.").Default("1m").Envar("NATIVE_CLIENTS_BATCH_WINDOW").Duration()
+		maxRefreshStaleness        = app.Flag("max-refresh-staleness", "Maximum time the Terraform refresh and plan of the S3 objects, IAM policies and Lambda functions is skipped for while neither their parameters nor the fingerprints of their external resources, such as their ETags or revisions, change. Refreshes are never skipped if zero.").Default("0").Envar("MAX_REFRESH_STALENESS").Duration()
+
+		logFormat    = app.Flag("log-format", "Format of the log lines, either json or console.").Default(logger.FormatJSON).Envar("LOG_FORMAT").Enum(logger.FormatJSON, logger.FormatConsole)
+		logLevel     = app.Flag("log-level", "Default log level, either info or debug.").Default(logger.LevelInfo).Envar("LOG_LEVEL").Enum(logger.LevelInfo, logger.LevelDebug)
+		logLevels    = app.Flag("log-levels", "Comma separated log level overrides for groups or kinds, such as rds=debug,ec2.SecurityGroup=debug.").Default("").Envar("LOG_LEVELS").String()
+		logConfigMap = app.Flag("log-config-map", "Name of the ConfigMap in the provider namespace that overrides the logging configuration at runtime. Disabled if empty.").Default("").Envar("LOG_CONFIG_MAP").String()
+
+		tracingEndpoint    = app.Flag("tracing-endpoint", "The host:port of the OTLP gRPC collector to export traces to. Tracing is disabled if empty.").Default("").Envar("TRACING_ENDPOINT").String()
+		tracingInsecure    = app.Flag("tracing-insecure", "Connect to the OTLP collector without transport security.").Default("false").Envar("TRACING_INSECURE").Bool()
+		tracingSampleRatio = app.Flag("tracing-sample-ratio", "The ratio of the reconciles that are traced, between 0 and 1.").Default("1").Envar("TRACING_SAMPLE_RATIO").Float64()
+
+		stateEncryptionSecret = app.Flag("state-encryption-key-secret", "Name of the Secret in the provider namespace whose "+encryption.SecretKey+" key holds the 32 bytes long AES-256 key the Terraform state of the workspaces is encrypted with at rest. The state is decrypted into the workspace directory while Terraform runs, including the asynchronous operations, so the provider refuses to start unless its temporary directory is on a memory-backed volume, such as an emptyDir volume with the Memory medium, which keeps the plaintext state off persistent disk.").Default("").Envar("STATE_ENCRYPTION_KEY_SECRET").String()
+		stateEncryptionKMSKey = app.Flag("state-encryption-kms-key", "ID, ARN or alias of the AWS KMS key the Terraform state of the workspaces is encrypted with at rest, using the credentials of the provider pod. The state is decrypted like with --state-encryption-key-secret.").Default("").Envar("STATE_ENCRYPTION_KMS_KEY").String()
+
+		workspaceStateDir     = app.Flag("workspace-state-dir", "Directory to persist the Terraform state of the workspaces in, such as the mount path of a PersistentVolume, so that it is reused after the provider restarts.").Default("").Envar("WORKSPACE_STATE_DIR").String()
+		workspaceStateSecrets = app.Flag("workspace-state-secrets", "Persist the Terraform state of the workspaces in Secrets in the provider namespace, so that it is reused after the provider restarts.").Default("false").Envar("WORKSPACE_STATE_SECRETS").Bool()
+		workspaceStateMaxAge  = app.Flag("workspace-state-max-age", "Maximum age of the persisted Terraform state of a workspace to be reused after the provider restarts. Persisted states of any age are reused if zero.").Default("24h").Envar("WORKSPACE_STATE_MAX_AGE").Duration()
+
+		workspaceGCInterval = app.Flag("workspace-gc-interval", "Interval at which the Terraform workspaces and the persisted states of deleted managed resources are removed and the disk budget of the workspaces is enforced. Workspaces are not collected if zero.").Default("10m").Envar("WORKSPACE_GC_INTERVAL").Duration()
+		workspaceDiskBudget = app.Flag("workspace-disk-budget", "Maximum disk space the Terraform workspaces may use, such as 10Gi. The least recently used workspaces are evicted while they use more. Unlimited if empty.").Default("").Envar("WORKSPACE_DISK_BUDGET").String()
 	)
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	zl := zap.New(zap.UseDevMode(*debug))
+	levels, err := logger.ParseLevels(*logLevels)
+	kingpin.FatalIfError(err, "Cannot parse log levels")
+	logConfig := logger.Config{
+		Format:      *logFormat,
+		Level:       *logLevel,
+		Levels:      levels,
+		Development: *debug,
+	}
+	if *debug {
+		logConfig.Level = logger.LevelDebug
+	}
+
+	pollOverrides, err := config.ParseDurations(*pollIntervals)
+	kingpin.FatalIfError(err, "Cannot parse poll intervals")
+	config.PollIntervals = config.PollIntervals.Merge(pollOverrides)
+	timeoutOverrides, err := config.ParseDurations(*reconcileTimeouts)
+	kingpin.FatalIfError(err, "Cannot parse reconcile timeouts")
+	config.ReconcileTimeouts = config.ReconcileTimeouts.Merge(timeoutOverrides)
+
+	sink, err := logger.NewSink(logConfig)
+	kingpin.FatalIfError(err, "Cannot configure logging")
+	zl := logr.New(sink)
 	log := logging.NewLogrLogger(zl.WithName("provider-aws"))
 	if *debug {
 		// The controller-runtime runs with a no-op logger by default. It is
@@ -64,6 +140,18 @@ func main() {
 	log.Debug("Starting", "sync-interval", syncInterval.String(),
 		"poll-interval", pollInterval.String(), "max-reconcile-rate", *maxReconcileRate)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    *tracingEndpoint,
+		Insecure:    *tracingInsecure,
+		SampleRatio: *tracingSampleRatio,
+	})
+	kingpin.FatalIfError(err, "Cannot set up tracing")
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Info("Cannot shut down tracing", "error", err)
+		}
+	}()
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -78,6 +166,112 @@ func main() {
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add AWS APIs to scheme")
 
+	if *logConfigMap != "" {
+		cs, err := kubernetes.NewForConfig(cfg)
+		kingpin.FatalIfError(err, "Cannot create Kubernetes clientset")
+		kingpin.FatalIfError(mgr.Add(logger.NewConfigMapWatcher(sink, logConfig, cs, *namespace, *logConfigMap, log)), "Cannot add logging ConfigMap watcher")
+	}
+
+	// The state is decrypted only while Terraform runs, which needs it in
+	// the workspace directory, and only into a memory-backed temporary
+	// directory, so that the plaintext never reaches persistent disk. The
+	// plaintext states a stopped provider left behind are encrypted before
+	// the controllers start.
+	var kw encryption.KeyWrapper
+	switch {
+	case *stateEncryptionSecret != "" && *stateEncryptionKMSKey != "":
+		kingpin.Fatalf("Only one of --state-encryption-key-secret and --state-encryption-kms-key can be set")
+	case *stateEncryptionSecret != "":
+		cs, err := kubernetes.NewForConfig(cfg)
+		kingpin.FatalIfError(err, "Cannot create Kubernetes clientset")
+		s, err := cs.CoreV1().Secrets(*namespace).Get(context.Background(), *stateEncryptionSecret, metav1.GetOptions{})
+		kingpin.FatalIfError(err, "Cannot get the state encryption key Secret")
+		kw, err = encryption.NewSecretKeyWrapper(s.Data[encryption.SecretKey])
+		kingpin.FatalIfError(err, "Cannot use the state encryption key")
+	case *stateEncryptionKMSKey != "":
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		kingpin.FatalIfError(err, "Cannot load the AWS configuration of the state encryption")
+		awsCfg.APIOptions = append(awsCfg.APIOptions, metrics.AddAPICallMetrics)
+		kw = encryption.NewKMSKeyWrapper(kms.NewFromConfig(awsCfg), *stateEncryptionKMSKey)
+	}
+	if kw != nil {
+		memory, err := encryption.MemoryBacked(os.TempDir())
+		kingpin.FatalIfError(err, "Cannot check the temporary directory of the provider")
+		if !memory {
+			kingpin.Fatalf("The temporary directory %s of the provider must be on a memory-backed volume to encrypt the Terraform state, since the state is decrypted into it while Terraform runs", os.TempDir())
+		}
+		enc := encryption.New(kw)
+		kingpin.FatalIfError(connector.SealWorkspaces(context.Background(), enc, os.TempDir()), "Cannot encrypt the Terraform state left in the workspaces")
+		connector.SetStateEncrypter(enc)
+	}
+
+	var states persistence.Store
+	switch {
+	case *workspaceStateDir != "" && *workspaceStateSecrets:
+		kingpin.Fatalf("Only one of --workspace-state-dir and --workspace-state-secrets can be set")
+	case *workspaceStateDir != "":
+		ds, err := persistence.NewDirectoryStore(*workspaceStateDir)
+		kingpin.FatalIfError(err, "Cannot use the workspace state directory")
+		states = ds
+	case *workspaceStateSecrets:
+		// The Secrets are read with an uncached client so that the
+		// provider does not cache all the Secrets of its namespace.
+		kube, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+		kingpin.FatalIfError(err, "Cannot create the client of the workspace state Secrets")
+		states = persistence.NewSecretStore(kube, *namespace)
+	}
+	if states != nil {
+		connector.SetStateStore(states, *workspaceStateMaxAge)
+	}
+
+	if *workspaceGCInterval > 0 {
+		var budget int64
+		if *workspaceDiskBudget != "" {
+			q, err := apiresource.ParseQuantity(*workspaceDiskBudget)
+			kingpin.FatalIfError(err, "Cannot parse the workspace disk budget")
+			budget = q.Value()
+		}
+		kingpin.FatalIfError(mgr.Add(workspace.NewGC(mgr.GetClient(), mgr.GetScheme(), os.TempDir(), log,
+			workspace.WithInterval(*workspaceGCInterval),
+			workspace.WithDiskBudget(budget),
+			workspace.WithInUse(connector.WorkspaceInUse),
+			workspace.WithStateStore(states))), "Cannot add the workspace garbage collector")
+	}
+
+	if *providerMirrorDir != "" && *providerMirrorURL != "" {
+		kingpin.Fatalf("Only one of --terraform-provider-mirror-dir and --terraform-provider-mirror-url can be set")
+	}
+	if *providerMirrorDir != "" || *providerMirrorURL != "" {
+		// The Terraform provider is never installed from the Terraform
+		// registry, so a missing artifact fails the startup rather than
+		// every reconcile.
+		if *providerChecksumFile != "" {
+			if *providerChecksum != "" {
+				kingpin.Fatalf("Only one of --terraform-provider-checksum and --terraform-provider-checksum-file can be set")
+			}
+			b, err := os.ReadFile(filepath.Clean(*providerChecksumFile))
+			kingpin.FatalIfError(err, "Cannot read the checksum of the Terraform provider")
+			if f := strings.Fields(string(b)); len(f) > 0 {
+				*providerChecksum = f[0]
+			}
+		}
+		mirror := plugin.Mirror{Dir: *providerMirrorDir, URL: *providerMirrorURL}
+		vctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		err := mirror.Verify(vctx, http.DefaultClient, plugin.Requirement{
+			Source:       *providerSource,
+			Version:      *providerVersion,
+			Platform:     runtime.GOOS + "_" + runtime.GOARCH,
+			Checksum:     *providerChecksum,
+			SkipChecksum: *providerSkipChecksum,
+			NativePath:   *nativeProviderPath,
+		})
+		cancel()
+		kingpin.FatalIfError(err, "Cannot install the Terraform provider without the Terraform registry")
+		cliConfig := filepath.Join(os.TempDir(), "terraformrc")
+		kingpin.FatalIfError(mirror.WriteCLIConfig(cliConfig), "Cannot configure the Terraform provider mirror")
+		kingpin.FatalIfError(os.Setenv("TF_CLI_CONFIG_FILE", cliConfig), "Cannot configure the Terraform provider mirror")
+	}
+
 	// if the native Terraform provider plugin's path is not configured via
 	// the env. variable TERRAFORM_NATIVE_PROVIDER_PATH or
 	// the `--terraform-native-provider-path` command-line option,
@@ -86,8 +280,24 @@ func main() {
 	// This removes some complexity for setting up development environments.
 	var runner terraform.ProviderRunner = terraform.NewNoOpProviderRunner()
 	if len(*nativeProviderPath) != 0 {
-		runner = terraform.NewSharedProvider(log, *nativeProviderPath, "registry.terraform.io/"+*providerSource)
+		var maxMemory int64
+		if *nativeProviderMaxMemory != "" {
+			q, err := apiresource.ParseQuantity(*nativeProviderMaxMemory)
+			kingpin.FatalIfError(err, "Cannot parse the maximum memory of the native provider")
+			maxMemory = q.Value()
+		}
+		pool := sharedprovider.NewPool(log, *nativeProviderPath, "registry.terraform.io/"+*providerSource,
+			sharedprovider.WithProcesses(*nativeProviderProcesses),
+			sharedprovider.WithMaxOperations(*nativeProviderMaxOperations),
+			sharedprovider.WithMaxMemory(maxMemory),
+			sharedprovider.WithHealthInterval(*nativeProviderHealth),
+			sharedprovider.WithDrainTimeout(*nativeProviderDrainTimeout))
+		kingpin.FatalIfError(mgr.Add(sharedprovider.NewSupervisor(pool)), "Cannot add the native provider supervisor")
+		runner = metrics.NewProviderRunner(pool)
 	}
+	// The plans of the dry-run mode are run by the connectors and use the
+	// same shared provider as the workspaces.
+	connector.SetProviderRunner(runner)
 
 	o := tjcontroller.Options{
 		Options: xpcontroller.Options{
@@ -97,7 +307,7 @@ func main() {
 			MaxConcurrentReconciles: *maxReconcileRate,
 			Features:                &feature.Flags{},
 		},
-		Provider:       config.GetProvider(),
+		Provider:       config.GetTrimmedProvider(),
 		WorkspaceStore: terraform.NewWorkspaceStore(log, terraform.WithProviderRunner(runner)),
 		SetupFn:        clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion),
 	}
@@ -122,6 +332,14 @@ func main() {
 		})), "cannot create default store config")
 	}
 
+	connector.SetMaxRefreshStaleness(*maxRefreshStaleness)
+
+	if *enableNativeClients {
+		o.Features.Enable(features.EnableAlphaNativeClients)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaNativeClients)
+		connector.SetNativeConnecters(native.Connecters(mgr.GetClient(), native.WithBatchedObservation(*nativeBatchWindow)))
+	}
+
 	kingpin.FatalIfError(controller.Setup(mgr, o), "Cannot setup AWS controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
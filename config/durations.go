@@ -0,0 +1,70 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Durations are per group or per kind durations of the controllers. They are
+// keyed by short group, such as "rds", or by short group and kind, such as
+// "ec2.SecurityGroupRule". The keys are stored in lowercase so that they are
+// case-insensitive, and a kind takes precedence over its group.
+type Durations map[string]time.Duration
+
+// ParseDurations parses a comma separated list of
+// <group or group.Kind>=<duration> pairs, such as
+// "organizations=1h,ec2.SecurityGroupRule=1m".
+func ParseDurations(s string) (Durations, error) {
+	d := Durations{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid duration override %q, expected <group or group.Kind>=<duration>", p)
+		}
+		v, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid duration override %q", p)
+		}
+		if v <= 0 {
+			return nil, errors.Errorf("invalid duration override %q, duration must be positive", p)
+		}
+		d[strings.ToLower(strings.TrimSpace(kv[0]))] = v
+	}
+	return d, nil
+}
+
+// Merge returns a copy of these durations overridden with the given ones.
+func (d Durations) Merge(o Durations) Durations {
+	out := make(Durations, len(d)+len(o))
+	for k, v := range d {
+		out[strings.ToLower(k)] = v
+	}
+	for k, v := range o {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// Get returns the duration of the given kind, or the given default if
+// neither the kind nor its group has one.
+func (d Durations) Get(gvk schema.GroupVersionKind, def time.Duration) time.Duration {
+	group := strings.ToLower(strings.SplitN(gvk.Group, ".", 2)[0])
+	if v, ok := d[group+"."+strings.ToLower(gvk.Kind)]; ok {
+		return v
+	}
+	if v, ok := d[group]; ok {
+		return v
+	}
+	return def
+}
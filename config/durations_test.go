@@ -0,0 +1,112 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseDurations(t *testing.T) {
+	type want struct {
+		d   Durations
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty list should have no durations.",
+			want:   want{d: Durations{}},
+		},
+		"Pairs": {
+			reason: "The groups and kinds should be parsed in lowercase along with their durations.",
+			s:      " organizations=1h, ec2.SecurityGroupRule = 1m ,",
+			want:   want{d: Durations{"organizations": time.Hour, "ec2.securitygrouprule": time.Minute}},
+		},
+		"NoDuration": {
+			reason: "A pair without a duration should be an error.",
+			s:      "organizations",
+			want:   want{err: errors.New(`invalid duration override "organizations", expected <group or group.Kind>=<duration>`)},
+		},
+		"InvalidDuration": {
+			reason: "A duration that cannot be parsed should be an error.",
+			s:      "organizations=hourly",
+			want:   want{err: errors.Wrap(errors.New(`time: invalid duration "hourly"`), `invalid duration override "organizations=hourly"`)},
+		},
+		"NotPositive": {
+			reason: "A duration that is not positive should be an error.",
+			s:      "organizations=0s",
+			want:   want{err: errors.New(`invalid duration override "organizations=0s", duration must be positive`)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := ParseDurations(tc.s)
+			if diff := cmp.Diff(tc.want.d, d); diff != "" {
+				t.Errorf("\n%s\nParseDurations(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseDurations(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDurationsMerge(t *testing.T) {
+	d := Durations{"organizations": time.Hour, "ec2.securitygrouprule": time.Minute}
+	got := d.Merge(Durations{"EC2.SecurityGroupRule": time.Second, "rds": time.Minute})
+	want := Durations{"organizations": time.Hour, "ec2.securitygrouprule": time.Second, "rds": time.Minute}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe given durations should override these ones in lowercase.\nMerge(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDurationsGet(t *testing.T) {
+	d := Durations{
+		"ec2":                   time.Hour,
+		"ec2.securitygrouprule": time.Minute,
+	}
+	cases := map[string]struct {
+		reason string
+		gvk    schema.GroupVersionKind
+		want   time.Duration
+	}{
+		"Kind": {
+			reason: "The duration of a kind should take precedence over the one of its group.",
+			gvk:    schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "SecurityGroupRule"},
+			want:   time.Minute,
+		},
+		"Group": {
+			reason: "The duration of the group should be used for a kind without one.",
+			gvk:    schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance"},
+			want:   time.Hour,
+		},
+		"CaseInsensitive": {
+			reason: "The group and the kind should be case-insensitive.",
+			gvk:    schema.GroupVersionKind{Group: "EC2.aws.upbound.io", Version: "v1beta1", Kind: "securityGROUPrule"},
+			want:   time.Minute,
+		},
+		"Default": {
+			reason: "The default should be used for a kind whose group has no duration.",
+			gvk:    schema.GroupVersionKind{Group: "rds.aws.upbound.io", Version: "v1beta1", Kind: "Cluster"},
+			want:   time.Second,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, d.Get(tc.gvk, time.Second)); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import "time"
+
+// PollIntervals are the poll intervals of the controllers that override the
+// default poll interval of the provider. Slow changing and expensive to
+// observe kinds are polled rarely, and security critical ones are polled
+// often so that drift is corrected quickly. They can be further overridden
+// with the --poll-intervals flag of the provider.
+var PollIntervals = Durations{
+	"organizations":           time.Hour,
+	"cloudfront.distribution": time.Hour,
+	"ec2.securitygroup":       time.Minute,
+	"ec2.securitygrouprule":   time.Minute,
+	"ec2.networkacl":          time.Minute,
+	"ec2.networkaclrule":      time.Minute,
+}
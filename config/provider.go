@@ -5,9 +5,6 @@ Copyright 2021 Upbound Inc.
 package config
 
 import (
-	// Note(ezgidemirel): we are importing this to embed provider schema document
-	_ "embed"
-
 	"github.com/upbound/upjet/pkg/config"
 	"github.com/upbound/upjet/pkg/registry/reference"
 
@@ -76,14 +73,6 @@ import (
 	"github.com/upbound/provider-aws/config/transfer"
 )
 
-var (
-	//go:embed schema.json
-	providerSchema string
-
-	//go:embed provider-metadata.yaml
-	providerMetadata []byte
-)
-
 var (
 	BasePackages = config.BasePackages{
 		APIVersion: []string{
@@ -116,22 +105,31 @@ var skipList = []string{
 	"aws_location_map$",                // failure with unknown reason.
 }
 
-// GetProvider returns provider configuration
-func GetProvider() *config.Provider {
+// GetProvider returns the provider configuration of the Terraform resources
+// in the given full Terraform provider schema and provider metadata that are
+// included and not skipped, which are the generated resources.
+func GetProvider(schema, metadata []byte) *config.Provider {
+	return newProvider(schema, metadata,
+		config.WithIncludeList(ResourcesWithExternalNameConfig()),
+		config.WithSkipList(skipList),
+	)
+}
+
+// newProvider returns the provider configuration built from the given
+// Terraform provider schema and provider metadata.
+func newProvider(schema, metadata []byte, opts ...config.ProviderOption) *config.Provider {
 	modulePath := "github.com/upbound/provider-aws"
-	pc := config.NewProvider([]byte(providerSchema), "aws",
-		modulePath, providerMetadata,
+	opts = append([]config.ProviderOption{
 		config.WithShortName("aws"),
 		config.WithRootGroup("aws.upbound.io"),
 		config.WithReferenceInjectors([]config.ReferenceInjector{reference.NewInjector("github.com/upbound/provider-aws")}),
-		config.WithIncludeList(ResourcesWithExternalNameConfig()),
 		config.WithReferenceInjectors([]config.ReferenceInjector{reference.NewInjector(modulePath)}),
-		config.WithSkipList(skipList),
 		config.WithBasePackages(BasePackages),
 		config.WithDefaultResourceOptions(
 			GroupKindOverrides(),
 			KindOverrides(),
 			RegionAddition(),
+			OperationTimeoutsAddition(),
 			TagsAllRemoval(),
 			IdentifierAssignedByAWS(),
 			KnownReferencers(),
@@ -140,7 +138,8 @@ func GetProvider() *config.Provider {
 			NamePrefixRemoval(),
 			DocumentationForTags(),
 		),
-	)
+	}, opts...)
+	pc := config.NewProvider(schema, "aws", modulePath, metadata, opts...)
 
 	for _, configure := range []func(provider *config.Provider){
 		acm.Configure,
@@ -0,0 +1,37 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+// statefulResources are the Terraform resources that hold data which is lost
+// when they are replaced. The changes of their managed resources that force a
+// replacement are not applied unless they are approved.
+var statefulResources = map[string]struct{}{
+	"aws_db_instance":                   {},
+	"aws_rds_cluster":                   {},
+	"aws_rds_cluster_instance":          {},
+	"aws_rds_global_cluster":            {},
+	"aws_docdb_cluster":                 {},
+	"aws_docdb_cluster_instance":        {},
+	"aws_docdb_global_cluster":          {},
+	"aws_neptune_cluster":               {},
+	"aws_neptune_cluster_instance":      {},
+	"aws_elasticache_cluster":           {},
+	"aws_elasticache_replication_group": {},
+	"aws_s3_bucket":                     {},
+	"aws_dynamodb_table":                {},
+	"aws_dynamodb_global_table":         {},
+	"aws_efs_file_system":               {},
+	"aws_kms_key":                       {},
+	"aws_kms_external_key":              {},
+	"aws_kms_replica_key":               {},
+	"aws_kms_replica_external_key":      {},
+}
+
+// IsStateful returns true if the given Terraform resource holds data which is
+// lost when it is replaced.
+func IsStateful(resource string) bool {
+	_, ok := statefulResources[resource]
+	return ok
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/types/comments"
+)
+
+// DefaultReconcileTimeout is the reconcile timeout of the controllers that do
+// not have one in ReconcileTimeouts.
+const DefaultReconcileTimeout = 3 * time.Minute
+
+// ReconcileTimeouts are the reconcile timeouts of the controllers that
+// override DefaultReconcileTimeout. Refreshing and planning the kinds with
+// large configurations or slow read APIs can take longer than the default.
+// They can be further overridden with the --reconcile-timeouts flag of the
+// provider.
+var ReconcileTimeouts = Durations{
+	"cloudfront.distribution": 10 * time.Minute,
+	"opensearch.domain":       10 * time.Minute,
+	"rds.cluster":             5 * time.Minute,
+	"rds.instance":            5 * time.Minute,
+	"eks.cluster":             5 * time.Minute,
+}
+
+// timeoutsResources are the Terraform resources that support configuring
+// operation timeouts, whose async managed resources expose them with the
+// timeouts spec field.
+var timeoutsResources = map[string]struct{}{
+	"aws_cloudsearch_domain":            {},
+	"aws_dax_cluster":                   {},
+	"aws_db_instance":                   {},
+	"aws_db_proxy":                      {},
+	"aws_docdb_cluster":                 {},
+	"aws_docdb_cluster_instance":        {},
+	"aws_docdb_global_cluster":          {},
+	"aws_dynamodb_table":                {},
+	"aws_eks_addon":                     {},
+	"aws_eks_cluster":                   {},
+	"aws_eks_fargate_profile":           {},
+	"aws_eks_node_group":                {},
+	"aws_elasticache_replication_group": {},
+	"aws_instance":                      {},
+	"aws_mq_broker":                     {},
+	"aws_msk_cluster":                   {},
+	"aws_neptune_cluster":               {},
+	"aws_neptune_cluster_instance":      {},
+	"aws_opensearch_domain":             {},
+	"aws_rds_cluster":                   {},
+	"aws_rds_cluster_instance":          {},
+	"aws_rds_global_cluster":            {},
+	"aws_redshift_cluster":              {},
+	"aws_route":                         {},
+	"aws_vpc_endpoint":                  {},
+}
+
+// OperationTimeoutsAddition adds the timeouts field to the spec of the async
+// resources that support Terraform operation timeouts. It is not passed to
+// Terraform as a parameter but overrides the operation timeouts of the
+// resource configuration.
+func OperationTimeoutsAddition() config.ResourceOption {
+	return func(r *config.Resource) {
+		if _, ok := timeoutsResources[r.Name]; !ok || !r.UseAsync {
+			return
+		}
+		c := "Timeouts of the create, update and delete operations of this resource, such as 60m. " +
+			"The operations are limited to one hour regardless of these timeouts.\n"
+		comment, err := comments.New(c, comments.WithTFTag("-"))
+		if err != nil {
+			panic(errors.Wrap(err, "cannot build comment for timeouts"))
+		}
+		r.TerraformResource.Schema["timeouts"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: comment.String(),
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"create": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Timeout of the create operation.",
+					},
+					"update": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Timeout of the update operation.",
+					},
+					"delete": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Timeout of the delete operation.",
+					},
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/upbound/upjet/pkg/config"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReconcileTimeouts(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		gvk    k8sschema.GroupVersionKind
+		want   time.Duration
+	}{
+		"Overridden": {
+			reason: "A kind with a slow read API should have a longer reconcile timeout.",
+			gvk:    k8sschema.GroupVersionKind{Group: "cloudfront.aws.upbound.io", Version: "v1beta1", Kind: "Distribution"},
+			want:   10 * time.Minute,
+		},
+		"Default": {
+			reason: "A kind without a reconcile timeout should have the default one.",
+			gvk:    k8sschema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+			want:   DefaultReconcileTimeout,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ReconcileTimeouts.Get(tc.gvk, DefaultReconcileTimeout)); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOperationTimeoutsAddition(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		name   string
+		async  bool
+		want   bool
+	}{
+		"Async": {
+			reason: "An async resource that supports operation timeouts should have the timeouts field.",
+			name:   "aws_rds_cluster",
+			async:  true,
+			want:   true,
+		},
+		"Sync": {
+			reason: "A sync resource should not have the timeouts field.",
+			name:   "aws_rds_cluster",
+		},
+		"Unsupported": {
+			reason: "A resource that does not support operation timeouts should not have the timeouts field.",
+			name:   "aws_s3_bucket",
+			async:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &config.Resource{Name: tc.name, UseAsync: tc.async, TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{}}}
+			OperationTimeoutsAddition()(r)
+			_, got := r.TerraformResource.Schema["timeouts"]
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nOperationTimeoutsAddition(): -want timeouts field, +got timeouts field:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"bytes"
+	// Note: we are importing this to embed the trimmed provider schema
+	// document
+	_ "embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/upbound/upjet/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SchemaFile is the name of the file of the Terraform provider schema.
+	SchemaFile = "schema.json"
+	// MetadataFile is the name of the file of the provider metadata.
+	MetadataFile = "provider-metadata.yaml"
+	// TrimmedSchemaFile is the name of the file the Terraform provider schema
+	// trimmed to the generated resources is written to.
+	TrimmedSchemaFile = "schema.trimmed.json"
+	// TrimmedMetadataFile is the name of the file the provider metadata
+	// trimmed to the generated resources is written to.
+	TrimmedMetadataFile = "provider-metadata.trimmed.yaml"
+
+	errUnmarshalSchema   = "cannot unmarshal provider schema"
+	errMarshalSchema     = "cannot marshal trimmed provider schema"
+	errUnmarshalMetadata = "cannot unmarshal provider metadata"
+	errMarshalMetadata   = "cannot marshal trimmed provider metadata"
+	errNoResources       = "cannot find the resources in the provider metadata"
+	errWriteTrimmed      = "cannot write trimmed provider configuration"
+)
+
+// Only the trimmed schema and metadata are embedded. The full ones, which
+// also hold the data sources and the resources that are not generated, are
+// read from the repository by the code generator.
+var (
+	//go:embed schema.trimmed.json
+	trimmedSchema string
+
+	//go:embed provider-metadata.trimmed.yaml
+	trimmedMetadata []byte
+)
+
+// GetTrimmedProvider returns the provider configuration built from the
+// Terraform provider schema and the provider metadata trimmed to the
+// generated resources, which are written by WriteTrimmed at code generation
+// time. It is the same configuration GetProvider returns for the generated
+// resources. It is still built at startup, that is, the schema and the
+// metadata are parsed and the configuration functions run, but only for the
+// generated resources.
+func GetTrimmedProvider() *config.Provider {
+	return newProvider([]byte(trimmedSchema), trimmedMetadata)
+}
+
+// WriteTrimmed writes the given Terraform provider schema and provider
+// metadata trimmed to the resources of the given provider configuration,
+// which is expected to be returned by GetProvider for them, to the given
+// directory to be loaded by GetTrimmedProvider.
+func WriteTrimmed(pc *config.Provider, schema, metadata []byte, dir string) error {
+	schema, err := trimSchema(schema, pc.Resources)
+	if err != nil {
+		return err
+	}
+	metadata, err = trimMetadata(metadata, pc.Resources)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, TrimmedSchemaFile), schema, 0o600); err != nil {
+		return errors.Wrap(err, errWriteTrimmed)
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(dir, TrimmedMetadataFile), metadata, 0o600), errWriteTrimmed)
+}
+
+// trimSchema returns the given Terraform provider schema with the schemas of
+// the given resources only. The data sources are dropped.
+func trimSchema(schema []byte, resources map[string]*config.Resource) ([]byte, error) {
+	ps := map[string]json.RawMessage{}
+	if err := json.Unmarshal(schema, &ps); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalSchema)
+	}
+	providers := map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal(ps["provider_schemas"], &providers); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalSchema)
+	}
+	for _, p := range providers {
+		rs := map[string]json.RawMessage{}
+		if err := json.Unmarshal(p["resource_schemas"], &rs); err != nil {
+			return nil, errors.Wrap(err, errUnmarshalSchema)
+		}
+		for name := range rs {
+			if _, ok := resources[name]; !ok {
+				delete(rs, name)
+			}
+		}
+		b, err := json.Marshal(rs)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalSchema)
+		}
+		p["resource_schemas"] = b
+		delete(p, "data_source_schemas")
+	}
+	b, err := json.Marshal(providers)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalSchema)
+	}
+	ps["provider_schemas"] = b
+	b, err = json.Marshal(ps)
+	return b, errors.Wrap(err, errMarshalSchema)
+}
+
+// trimMetadata returns the given provider metadata with the metadata of the
+// given resources only.
+func trimMetadata(metadata []byte, resources map[string]*config.Resource) ([]byte, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(metadata, doc); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalMetadata)
+	}
+	rs := mappingValue(doc, "resources")
+	if rs == nil {
+		return nil, errors.New(errNoResources)
+	}
+	content := make([]*yaml.Node, 0, 2*len(resources))
+	for i := 0; i+1 < len(rs.Content); i += 2 {
+		if _, ok := resources[rs.Content[i].Value]; ok {
+			content = append(content, rs.Content[i], rs.Content[i+1])
+		}
+	}
+	rs.Content = content
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(doc); err != nil {
+		return nil, errors.Wrap(err, errMarshalMetadata)
+	}
+	return buf.Bytes(), errors.Wrap(enc.Close(), errMarshalMetadata)
+}
+
+// mappingValue returns the value of the given key of the mapping in the given
+// YAML document.
+func mappingValue(doc *yaml.Node, key string) *yaml.Node {
+	m := doc
+	if m.Kind == yaml.DocumentNode && len(m.Content) == 1 {
+		m = m.Content[0]
+	}
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
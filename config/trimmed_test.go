@@ -0,0 +1,80 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/upbound/upjet/pkg/config"
+)
+
+// fullProvider returns the provider configuration built from the full
+// provider schema and metadata in the repository.
+func fullProvider(t testing.TB) *config.Provider {
+	t.Helper()
+	schema, err := os.ReadFile(SchemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := os.ReadFile(MetadataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return GetProvider(schema, metadata)
+}
+
+func TestGetTrimmedProvider(t *testing.T) {
+	type resource struct {
+		ShortGroup string
+		Kind       string
+		Version    string
+		Fields     int
+		Examples   int
+		References config.References
+	}
+	resources := func(pc *config.Provider) map[string]resource {
+		rs := make(map[string]resource, len(pc.Resources))
+		for name, r := range pc.Resources {
+			examples := 0
+			if r.MetaResource != nil {
+				examples = len(r.MetaResource.Examples)
+			}
+			rs[name] = resource{
+				ShortGroup: r.ShortGroup,
+				Kind:       r.Kind,
+				Version:    r.Version,
+				Fields:     len(r.TerraformResource.Schema),
+				Examples:   examples,
+				References: r.References,
+			}
+		}
+		return rs
+	}
+	want := resources(fullProvider(t))
+	got := resources(GetTrimmedProvider())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe trimmed provider configuration should be the configuration of the generated resources. Run the code generation if it is stale.\nGetTrimmedProvider(): -want, +got:\n%s", diff)
+	}
+}
+
+// BenchmarkProvider measures the time and the memory the provider
+// configuration takes to build at startup, from the trimmed schema and
+// metadata the provider embeds, compared to the full ones.
+func BenchmarkProvider(b *testing.B) {
+	b.Run("Full", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fullProvider(b)
+		}
+	})
+	b.Run("Trimmed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GetTrimmedProvider()
+		}
+	})
+}
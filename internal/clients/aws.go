@@ -12,6 +12,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/internal/tracing"
 )
 
 const (
@@ -35,7 +37,9 @@ func TerraformSetupBuilder(version, providerSource, providerVersion string) terr
 		if cfg.Region == "" && mg.GetObjectKind().GroupVersionKind().Group == "iam.aws.upbound.io" {
 			cfg.Region = "us-east-1"
 		}
-		creds, err := cfg.Credentials.Retrieve(ctx)
+		rctx, span := tracing.Start(ctx, "RetrieveCredentials")
+		creds, err := cfg.Credentials.Retrieve(rctx)
+		tracing.End(span, err)
 		if err != nil {
 			return terraform.Setup{}, errors.Wrap(err, "failed to retrieve aws credentials from aws config")
 		}
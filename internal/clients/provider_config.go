@@ -28,6 +28,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/upbound/provider-aws/apis/v1beta1"
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/tracing"
 	"github.com/upbound/provider-aws/internal/version"
 )
 
@@ -61,7 +63,16 @@ var userAgentV2 = config.WithAPIOptions([]func(*middleware.Stack) error{
 	awsmiddleware.AddUserAgentKeyValue("crossplane-provider-aws", version.Version),
 })
 
-func getRegion(obj runtime.Object) (string, error) {
+// apiCallInstrumentationV2 records metrics and opens spans for the AWS API calls
+// made with v2 clients
+var apiCallInstrumentationV2 = config.WithAPIOptions([]func(*middleware.Stack) error{
+	metrics.AddAPICallMetrics,
+	tracing.AddAPICallSpans,
+})
+
+// GetRegion returns the region of the given managed resource, which is empty
+// for the resources that do not have one.
+func GetRegion(obj runtime.Object) (string, error) {
 	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
 		return "", errors.Wrap(err, "cannot convert to unstructured")
@@ -76,11 +87,18 @@ func getRegion(obj runtime.Object) (string, error) {
 }
 
 // GetAWSConfig to produce a config that can be used to authenticate to AWS.
-func GetAWSConfig(ctx context.Context, c client.Client, mg resource.Managed) (*aws.Config, error) { // nolint:gocyclo
+func GetAWSConfig(ctx context.Context, c client.Client, mg resource.Managed) (*aws.Config, error) {
+	ctx, span := tracing.Start(ctx, "GetAWSConfig")
+	cfg, err := getAWSConfig(ctx, c, mg)
+	tracing.End(span, err)
+	return cfg, err
+}
+
+func getAWSConfig(ctx context.Context, c client.Client, mg resource.Managed) (*aws.Config, error) { // nolint:gocyclo
 	if mg.GetProviderConfigReference() == nil {
 		return nil, errors.New("no providerConfigRef provided")
 	}
-	region, err := getRegion(mg)
+	region, err := GetRegion(mg)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get region")
 	}
@@ -237,6 +255,7 @@ func UseProviderSecret(ctx context.Context, data []byte, profile, region string)
 	awsConfig, err := config.LoadDefaultConfig(
 		ctx,
 		userAgentV2,
+		apiCallInstrumentationV2,
 		config.WithRegion(region),
 		config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
 			Value: creds,
@@ -261,8 +280,9 @@ func GetRoleChainConfig(ctx context.Context, pcs *v1beta1.ProviderConfigSpec, cf
 		cfgWithAssumeRole, err := config.LoadDefaultConfig(
 			ctx,
 			userAgentV2,
+			apiCallInstrumentationV2,
 			config.WithRegion(cfg.Region),
-			config.WithCredentialsProvider(aws.NewCredentialsCache(stsAssume)),
+			config.WithCredentialsProvider(aws.NewCredentialsCache(metrics.NewCredentialsProvider(metrics.OperationAssumeRole, stsAssume))),
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, errRoleChainConfig)
@@ -282,14 +302,15 @@ func GetAssumeRoleWithWebIdentityConfig(ctx context.Context, cfg *aws.Config, pc
 	awsConfig, err := config.LoadDefaultConfig(
 		ctx,
 		userAgentV2,
+		apiCallInstrumentationV2,
 		config.WithRegion(cfg.Region),
 		config.WithCredentialsProvider(aws.NewCredentialsCache(
-			stscreds.NewWebIdentityRoleProvider(
+			metrics.NewCredentialsProvider(metrics.OperationAssumeRoleWithWebIdentity, stscreds.NewWebIdentityRoleProvider(
 				stsclient,
 				aws.ToString(pcs.Credentials.WebIdentity.RoleARN),
 				stscreds.IdentityTokenFile(os.Getenv(envWebIdentityTokenFile)),
 				SetWebIdentityRoleOptions(*pcs.Credentials.WebIdentity),
-			)),
+			))),
 		),
 	)
 	if err != nil {
@@ -305,12 +326,14 @@ func UsePodServiceAccount(ctx context.Context, region string) (*aws.Config, erro
 		cfg, err := config.LoadDefaultConfig(
 			ctx,
 			userAgentV2,
+			apiCallInstrumentationV2,
 		)
 		return &cfg, errors.Wrap(err, "failed to load default AWS config")
 	}
 	cfg, err := config.LoadDefaultConfig(
 		ctx,
 		userAgentV2,
+		apiCallInstrumentationV2,
 		config.WithRegion(region),
 	)
 	if err != nil {
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	// AnnotationKeyAdoptionPolicy is the annotation that overrides the
+	// adoption policy of the ProviderConfig of a managed resource. It is one
+	// of Adopt, AdoptIfTagged or Fail.
+	AnnotationKeyAdoptionPolicy = "aws.upbound.io/adoption-policy"
+
+	// AnnotationKeyAdopted is the annotation that records the time an
+	// existing external resource was adopted at, so that it stays adopted
+	// if the adoption policy changes afterwards.
+	AnnotationKeyAdopted = "aws.upbound.io/adopted"
+
+	// TypeAlreadyExists is the type of the condition that reports whether an
+	// external resource that the managed resource did not create already
+	// exists and is not adopted because of the adoption policy.
+	TypeAlreadyExists xpv1.ConditionType = "AlreadyExists"
+
+	// ReasonAdoptionRefused is the reason of the AlreadyExists condition when
+	// the adoption of an existing external resource is refused.
+	ReasonAdoptionRefused xpv1.ConditionReason = "AdoptionRefused"
+	// ReasonAdopted is the reason of the AlreadyExists condition when an
+	// existing external resource whose adoption was refused is adopted.
+	ReasonAdopted xpv1.ConditionReason = "Adopted"
+
+	errInvalidAdoptionPolicy = "invalid adoption policy %q in the " + AnnotationKeyAdoptionPolicy + " annotation"
+	errAlreadyExists         = "the external resource already exists and is not adopted because of the %s adoption policy"
+)
+
+// adoptionPolicy returns the adoption policy of the given managed resource.
+// The annotation of the resource takes precedence over its ProviderConfig.
+func adoptionPolicy(mg xpresource.Managed, pc *v1beta1.ProviderConfig) (v1beta1.AdoptionPolicy, error) {
+	if v, ok := mg.GetAnnotations()[AnnotationKeyAdoptionPolicy]; ok {
+		switch p := v1beta1.AdoptionPolicy(v); p {
+		case v1beta1.AdoptionPolicyAdopt, v1beta1.AdoptionPolicyAdoptIfTagged, v1beta1.AdoptionPolicyFail:
+			return p, nil
+		default:
+			return "", errors.Errorf(errInvalidAdoptionPolicy, v)
+		}
+	}
+	if pc == nil || pc.Spec.AdoptionPolicy == "" {
+		return v1beta1.AdoptionPolicyAdopt, nil
+	}
+	return pc.Spec.AdoptionPolicy, nil
+}
+
+// created returns true if the external resource of the given managed resource
+// was created by the managed resource, rather than found to exist before it
+// was created.
+func created(mg xpresource.Managed) bool {
+	return !meta.GetExternalCreatePending(mg).IsZero() || !meta.GetExternalCreateSucceeded(mg).IsZero()
+}
+
+// adoptionAllowed returns true if the existing external resource of the given
+// managed resource with the given kind and Terraform state can be adopted
+// with the given policy.
+func adoptionAllowed(mg xpresource.Managed, gk schema.GroupKind, tfstate map[string]any, p v1beta1.AdoptionPolicy) bool {
+	switch p {
+	case v1beta1.AdoptionPolicyFail:
+		return false
+	case v1beta1.AdoptionPolicyAdoptIfTagged:
+		tags := externalTags(tfstate)
+		return tags[xpresource.ExternalResourceTagKeyKind] == strings.ToLower(gk.String()) &&
+			tags[xpresource.ExternalResourceTagKeyName] == mg.GetName()
+	default:
+		return true
+	}
+}
+
+// externalTags returns the tags of an external resource with the given
+// Terraform state. Most resources have a tags map, while some, such as the
+// autoscaling groups, have tag blocks or a list of tags with key and value
+// attributes instead.
+func externalTags(tfstate map[string]any) map[string]any {
+	tags := map[string]any{}
+	for _, attr := range []string{"tags", "tag"} {
+		switch v := tfstate[attr].(type) {
+		case map[string]any:
+			for k, val := range v {
+				tags[k] = val
+			}
+		case []any:
+			for _, e := range v {
+				t, _ := e.(map[string]any)
+				if k, ok := t["key"].(string); ok {
+					tags[k] = t["value"]
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// adoptionRecorded returns true if the adoption of the existing external
+// resource of the given managed resource was recorded.
+func adoptionRecorded(mg xpresource.Managed) bool {
+	_, ok := mg.GetAnnotations()[AnnotationKeyAdopted]
+	return ok
+}
+
+// recordAdoption records the adoption of the existing external resource of
+// the given managed resource. It returns true if it was not recorded before,
+// in which case the annotation is to be persisted.
+func recordAdoption(mg xpresource.Managed) bool {
+	if adoptionRecorded(mg) {
+		return false
+	}
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyAdopted: time.Now().UTC().Format(time.RFC3339)})
+	return true
+}
+
+// adoptionRefused returns a condition that indicates the existing external
+// resource is not adopted because of the given policy.
+func adoptionRefused(p v1beta1.AdoptionPolicy) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAlreadyExists,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAdoptionRefused,
+		Message:            fmt.Sprintf(errAlreadyExists, p),
+	}
+}
+
+// adopted marks the AlreadyExists condition of the given managed resource as
+// false if the adoption of its external resource was refused before.
+func adopted(mg xpresource.Managed) {
+	if c := mg.GetCondition(TypeAlreadyExists); c.Status != corev1.ConditionTrue {
+		return
+	}
+	mg.SetConditions(xpv1.Condition{
+		Type:               TypeAlreadyExists,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAdopted,
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	autoscalingv1beta1 "github.com/upbound/provider-aws/apis/autoscaling/v1beta1"
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+// autoscalingGroupState returns the Terraform state of an autoscaling group
+// with the given parameters, which is shaped by its Terraform schema.
+func autoscalingGroupState(t *testing.T, p autoscalingv1beta1.AutoscalingGroupParameters) map[string]any {
+	t.Helper()
+	asg := &autoscalingv1beta1.AutoscalingGroup{Spec: autoscalingv1beta1.AutoscalingGroupSpec{ForProvider: p}}
+	tfstate, err := asg.GetParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tfstate
+}
+
+func TestAdoptionAllowed(t *testing.T) {
+	gk := schema.GroupKind{Group: "autoscaling.aws.upbound.io", Kind: "AutoscalingGroup"}
+	tagged := map[string]any{
+		"tags": map[string]any{
+			"crossplane-kind": "autoscalinggroup.autoscaling.aws.upbound.io",
+			"crossplane-name": "web",
+		},
+	}
+	cases := map[string]struct {
+		reason  string
+		policy  v1beta1.AdoptionPolicy
+		tfstate map[string]any
+		want    bool
+	}{
+		"Adopt": {
+			reason: "Any existing resource should be adopted with the Adopt policy.",
+			policy: v1beta1.AdoptionPolicyAdopt,
+			want:   true,
+		},
+		"Fail": {
+			reason:  "No existing resource should be adopted with the Fail policy.",
+			policy:  v1beta1.AdoptionPolicyFail,
+			tfstate: tagged,
+			want:    false,
+		},
+		"AdoptIfTagged": {
+			reason:  "A resource tagged with the kind and name of the managed resource should be adopted with the AdoptIfTagged policy.",
+			policy:  v1beta1.AdoptionPolicyAdoptIfTagged,
+			tfstate: tagged,
+			want:    true,
+		},
+		"AdoptIfTaggedBlocks": {
+			reason: "An autoscaling group tagged with tag blocks of the kind and name of the managed resource should be adopted with the AdoptIfTagged policy.",
+			policy: v1beta1.AdoptionPolicyAdoptIfTagged,
+			tfstate: autoscalingGroupState(t, autoscalingv1beta1.AutoscalingGroupParameters{Tag: []autoscalingv1beta1.TagParameters{
+				{Key: aws.String("crossplane-kind"), Value: aws.String("autoscalinggroup.autoscaling.aws.upbound.io"), PropagateAtLaunch: aws.Bool(true)},
+				{Key: aws.String("crossplane-name"), Value: aws.String("web"), PropagateAtLaunch: aws.Bool(false)},
+			}}),
+			want: true,
+		},
+		"AdoptIfTaggedList": {
+			reason: "An autoscaling group tagged with a list of tags of the kind and name of the managed resource should be adopted with the AdoptIfTagged policy.",
+			policy: v1beta1.AdoptionPolicyAdoptIfTagged,
+			tfstate: autoscalingGroupState(t, autoscalingv1beta1.AutoscalingGroupParameters{Tags: []map[string]*string{
+				{"key": aws.String("crossplane-kind"), "value": aws.String("autoscalinggroup.autoscaling.aws.upbound.io"), "propagate_at_launch": aws.String("true")},
+				{"key": aws.String("crossplane-name"), "value": aws.String("web"), "propagate_at_launch": aws.String("true")},
+			}}),
+			want: true,
+		},
+		"AdoptIfTaggedBlocksOtherName": {
+			reason: "An autoscaling group tagged with tag blocks of another managed resource should not be adopted with the AdoptIfTagged policy.",
+			policy: v1beta1.AdoptionPolicyAdoptIfTagged,
+			tfstate: autoscalingGroupState(t, autoscalingv1beta1.AutoscalingGroupParameters{Tag: []autoscalingv1beta1.TagParameters{
+				{Key: aws.String("crossplane-kind"), Value: aws.String("autoscalinggroup.autoscaling.aws.upbound.io"), PropagateAtLaunch: aws.Bool(true)},
+				{Key: aws.String("crossplane-name"), Value: aws.String("api"), PropagateAtLaunch: aws.Bool(true)},
+			}}),
+			want: false,
+		},
+		"AdoptIfTaggedUntagged": {
+			reason: "A resource that is not tagged should not be adopted with the AdoptIfTagged policy.",
+			policy: v1beta1.AdoptionPolicyAdoptIfTagged,
+			tfstate: map[string]any{
+				"tags": map[string]any{"crossplane-name": "web"},
+			},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetName("web")
+			got := adoptionAllowed(mg, gk, tc.tfstate, tc.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nadoptionAllowed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		policy       v1beta1.AdoptionPolicy
+		adopted      bool
+		want         bool
+		wantRecorded bool
+	}{
+		"Adopted": {
+			reason:       "An adopted external resource should be recorded as adopted.",
+			policy:       v1beta1.AdoptionPolicyAdopt,
+			want:         true,
+			wantRecorded: true,
+		},
+		"Refused": {
+			reason: "An external resource whose adoption is refused should not be recorded as adopted.",
+			policy: v1beta1.AdoptionPolicyFail,
+		},
+		"AdoptedBefore": {
+			reason:  "An external resource that was adopted before should stay adopted after the adoption policy changes.",
+			policy:  v1beta1.AdoptionPolicyFail,
+			adopted: true,
+			want:    true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetName("web")
+			if tc.adopted {
+				meta.AddAnnotations(mg, map[string]string{AnnotationKeyAdopted: "2022-09-12T13:14:15Z"})
+			}
+			e := &external{adoption: tc.policy}
+			got, recorded, err := e.adopt(mg, map[string]any{})
+			if err != nil {
+				t.Fatalf("\n%s\nadopt(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nadopt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantRecorded, recorded); diff != "" {
+				t.Errorf("\n%s\nadopt(...): -want recorded, +got recorded:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, adoptionRecorded(mg)); diff != "" {
+				t.Errorf("\n%s\nadopt(...): -want adoption annotation, +got adoption annotation:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,643 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package connector contains the managed.ExternalConnecter used by the
+// Terraform based controllers of the provider. It follows the upjet
+// controller.Connector and lets the provider hook into the Terraform
+// operations run for every managed resource.
+package connector
+
+import (
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sexec "k8s.io/utils/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/upbound/upjet/pkg/config"
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/resource"
+	"github.com/upbound/upjet/pkg/resource/json"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+	awsconfig "github.com/upbound/provider-aws/config"
+	"github.com/upbound/provider-aws/internal/fingerprint"
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/snapshot"
+	"github.com/upbound/provider-aws/internal/tracing"
+)
+
+const (
+	errUnexpectedObject  = "the custom resource is not a Terraformed resource"
+	errGetTerraformSetup = "cannot get terraform setup"
+	errGetWorkspace      = "cannot get a terraform workspace for resource"
+	errRefresh           = "cannot run refresh"
+	errPlan              = "cannot run plan"
+	errStartAsyncApply   = "cannot start async apply"
+	errStartAsyncDestroy = "cannot start async destroy"
+	errApply             = "cannot apply"
+	errDestroy           = "cannot destroy"
+)
+
+// Option allows you to configure Connector.
+type Option func(*Connector)
+
+// WithCallbackProvider configures the controller to use async variant of the
+// functions of the Terraform client and run given callbacks once those
+// operations are completed.
+func WithCallbackProvider(ac tjcontroller.CallbackProvider) Option {
+	return func(c *Connector) {
+		c.callback = ac
+	}
+}
+
+// WithEventRecorder configures the recorder of the events of the changes
+// planned in dry-run mode.
+func WithEventRecorder(r event.Recorder) Option {
+	return func(c *Connector) {
+		c.recorder = r
+	}
+}
+
+// NewConnector returns a new Connector object.
+func NewConnector(kube client.Client, ws tjcontroller.Store, sf terraform.SetupFn, cfg *config.Resource, opts ...Option) *Connector {
+	c := &Connector{
+		kube:              kube,
+		getTerraformSetup: sf,
+		store:             ws,
+		config:            cfg,
+		recorder:          event.NewNopRecorder(),
+		executor:          k8sexec.New(),
+	}
+	for _, f := range opts {
+		f(c)
+	}
+	return c
+}
+
+// Connector initializes the external client with credentials and other
+// configuration parameters.
+type Connector struct {
+	kube              client.Client
+	store             tjcontroller.Store
+	getTerraformSetup terraform.SetupFn
+	config            *config.Resource
+	callback          tjcontroller.CallbackProvider
+	recorder          event.Recorder
+	executor          k8sexec.Interface
+}
+
+// Connect makes sure the underlying client is ready to issue requests to the
+// provider API.
+func (c *Connector) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	tr, ok := mg.(resource.Terraformed)
+	if !ok {
+		return nil, errors.New(errUnexpectedObject)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttrExternalName.String(meta.GetExternalName(mg)))
+
+	pc, err := providerConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	dry, err := dryRun(mg, pc)
+	if err != nil {
+		return nil, err
+	}
+	adoption, err := adoptionPolicy(mg, pc)
+	if err != nil {
+		return nil, err
+	}
+	drift, grace, err := driftPolicy(mg)
+	if err != nil {
+		return nil, err
+	}
+	fws, err := freezeWindows(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := withOperationTimeouts(mg, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignored []string
+	if cfg.TerraformResource != nil {
+		if ignored, err = ignoreChanges(tr, cfg.TerraformResource.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	// The managed resources of the kinds with native external clients are
+	// managed by them with the same policies, unless they use policies
+	// that rely on Terraform plans.
+	if nc := getNativeConnecter(c.config.Name); nc != nil && nativeSupported(dry, ignored, drift) {
+		ec, err := nc.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &nativeExternal{ExternalClient: ec, policy: &external{
+			config:        c.config,
+			kube:          c.kube,
+			recorder:      c.recorder,
+			readOnly:      pc != nil && pc.Spec.ReadOnly,
+			adoption:      adoption,
+			freezeWindows: fws,
+		}}, nil
+	}
+
+	ts, err := c.terraformSetup(ctx, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetTerraformSetup)
+	}
+
+	state := newWorkspaceState(mg, workspaceDir(tr))
+	// The workspace store reads and writes the state.
+	if err := state.open(ctx); err != nil {
+		return nil, err
+	}
+	wctx, span := tracing.Start(ctx, "terraform workspace")
+	tf, err := c.store.Workspace(wctx, &apiSecretClient{kube: c.kube}, tr, ts, cfg)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetWorkspace)
+	}
+	if err := touchWorkspace(workspaceDir(tr), tr.GetUID(), tf.LastOperation.IsRunning); err != nil {
+		return nil, err
+	}
+	// The workspace files must not be changed while an async operation is
+	// running.
+	if len(ignored) > 0 && !tf.LastOperation.IsRunning() {
+		if err := writeIgnoreChanges(workspaceDir(tr), tr, ignored); err != nil {
+			return nil, err
+		}
+	}
+	// The changes are planned once per observation. The planned change is
+	// read from a saved plan only if the observation needs it.
+	planned := &planWorkspace{Workspace: tf, dir: workspaceDir(tr), executor: c.executor}
+	var w tjcontroller.Workspace = newInstrumentedWorkspace(ctx, planned, tr.GetUID(), c.config)
+	if state.active() {
+		if !tf.LastOperation.IsRunning() {
+			if err := state.close(ctx); err != nil {
+				return nil, err
+			}
+		}
+		w = &stateWorkspace{Workspace: w, state: state, running: tf.LastOperation.IsRunning}
+	}
+
+	var fp func(ctx context.Context) (string, error)
+	staleness := getMaxRefreshStaleness()
+	if staleness > 0 && fingerprint.Supported(c.config.Name) {
+		fp = c.fingerprinter(tr, pc)
+	}
+
+	return &external{
+		workspace:     w,
+		config:        c.config,
+		callback:      c.callback,
+		kube:          c.kube,
+		recorder:      c.recorder,
+		planned:       planned,
+		state:         state,
+		dryRun:        dry,
+		readOnly:      pc != nil && pc.Spec.ReadOnly,
+		adoption:      adoption,
+		drift:         drift,
+		grace:         grace,
+		freezeWindows: fws,
+		fingerprint:   fp,
+		maxStaleness:  staleness,
+	}, nil
+}
+
+func (c *Connector) terraformSetup(ctx context.Context, mg xpresource.Managed) (terraform.Setup, error) {
+	ctx, span := tracing.Start(ctx, "terraform setup")
+	ts, err := c.getTerraformSetup(ctx, c.kube, mg)
+	tracing.End(span, err)
+	return ts, err
+}
+
+type external struct {
+	workspace     tjcontroller.Workspace
+	config        *config.Resource
+	callback      tjcontroller.CallbackProvider
+	kube          client.Client
+	recorder      event.Recorder
+	planned       *planWorkspace
+	dryRun        bool
+	readOnly      bool
+	adoption      v1beta1.AdoptionPolicy
+	drift         DriftPolicy
+	grace         time.Duration
+	freezeWindows []freezeWindow
+	state         *workspaceState
+	// fingerprint takes the fingerprint of the external resource if its
+	// refreshes are skipped while it does not change.
+	fingerprint  func(ctx context.Context) (string, error)
+	maxStaleness time.Duration
+}
+
+func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) { //nolint:gocyclo
+	// We skip the gocyclo check because most of the operations are
+	// straight-forward and serial.
+	tr, ok := mg.(resource.Terraformed)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+	}
+	allowDeletion(mg)
+	if frozenUntil(e.freezeWindows, time.Now()).IsZero() {
+		unfreeze(mg)
+	}
+	if e.readOnly && meta.WasDeleted(mg) {
+		// The external resource of a read-only managed resource is left
+		// as it is, like with the Orphan deletion policy. Its persisted
+		// state is deleted by the Finalizer.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	last, key := e.skipRefresh(ctx, tr, time.Now())
+	if last != nil {
+		metrics.TerraformRefreshesSkipped.WithLabelValues(e.config.ShortGroup, e.config.Kind).Inc()
+		return *last, nil
+	}
+	start := time.Now()
+	res, err := e.workspace.Refresh(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRefresh)
+	}
+	switch {
+	case res.IsApplying, res.IsDestroying:
+		mg.SetConditions(resource.AsyncOperationOngoingCondition())
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	case !res.Exists && e.readOnly:
+		return managed.ExternalObservation{}, errors.New(errReadOnlyNotFound)
+	case !res.Exists && e.dryRun && !meta.WasDeleted(mg):
+		// The creation is reported as planned and the resource as up to
+		// date so that it is not created.
+		e.planned.detailed = true
+		if _, err := e.workspace.Plan(ctx); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errPlan)
+		}
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, e.planChanges(mg)
+	case !res.Exists:
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+	// There might be a case where async operation is finished and the status
+	// update marking it as finished didn't go through. At this point, we are
+	// sure that there is no ongoing operation.
+	if e.config.UseAsync {
+		tr.SetConditions(resource.AsyncOperationFinishedCondition())
+	}
+
+	// No operation was in progress, our observation completed successfully,
+	// and we have an observation to consume.
+	tfstate := map[string]any{}
+	if err := json.JSParser.Unmarshal(res.State.GetAttributes(), &tfstate); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot unmarshal state attributes")
+	}
+	// Read-only managed resources never create their external resources, so
+	// they always observe existing ones.
+	newlyAdopted := false
+	if !created(mg) && !e.readOnly {
+		ok, recorded, err := e.adopt(mg, tfstate)
+		newlyAdopted = recorded
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		switch {
+		case !ok && meta.WasDeleted(mg):
+			// The external resource that is not adopted is not destroyed
+			// either.
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		case !ok:
+			return managed.ExternalObservation{}, errors.Errorf(errAlreadyExists, e.adoption)
+		}
+	}
+	if err := tr.SetObservation(tfstate); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot set observation")
+	}
+
+	annotationsUpdated, err := resource.SetCriticalAnnotations(tr, e.config, tfstate, string(res.State.GetPrivateRaw()))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot set critical annotations")
+	}
+	// The recorded adoption is persisted along with the critical
+	// annotations.
+	annotationsUpdated = annotationsUpdated || newlyAdopted
+	conn, err := resource.GetConnectionDetails(tfstate, tr, e.config)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
+	}
+
+	lateInitedParams, err := tr.LateInitialize(res.State.GetAttributes())
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot late initialize parameters")
+	}
+	markedAvailable := tr.GetCondition(xpv1.TypeReady).Equal(xpv1.Available())
+
+	// Before running a relatively costly Terraform plan, we first update the
+	// critical annotations, then the status and then the spec with
+	// late-initialized fields. See the upjet external client for the
+	// reasoning behind this order.
+	switch {
+	case annotationsUpdated:
+		return managed.ExternalObservation{
+			ResourceExists:          true,
+			ResourceUpToDate:        true,
+			ConnectionDetails:       conn,
+			ResourceLateInitialized: true,
+		}, nil
+	case !markedAvailable:
+		tr.SetConditions(xpv1.Available())
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: conn,
+		}, nil
+	case lateInitedParams:
+		return managed.ExternalObservation{
+			ResourceExists:          true,
+			ResourceUpToDate:        true,
+			ConnectionDetails:       conn,
+			ResourceLateInitialized: true,
+		}, nil
+	default:
+		e.planned.detailed = e.changeNeeded(mg)
+		plan, err := e.workspace.Plan(ctx)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errPlan)
+		}
+
+		if e.readOnly && !plan.UpToDate {
+			return managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: conn,
+			}, e.reportDrift(mg)
+		}
+		if e.dryRun && !plan.UpToDate {
+			return managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: conn,
+			}, e.planChanges(mg)
+		}
+		if !plan.UpToDate && driftPossible(mg) {
+			s := e.detectDrift(mg)
+			if s != nil && !correctDrift(mg, e.drift, e.grace, time.Now()) {
+				// The drift is only reported and the resource is reported
+				// as up to date so that it is not corrected.
+				reportDrift(mg, s)
+				return managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: conn,
+				}, nil
+			}
+		}
+		if plan.UpToDate {
+			markSynced(mg)
+			clearDrift(mg)
+			clearPlannedChanges(mg)
+			unblockReplacement(mg)
+			recordRefresh(mg.GetUID(), key, start, conn)
+		}
+		if !plan.UpToDate && awsconfig.IsStateful(e.config.Name) && !replacementApproved(mg) {
+			if e.blockReplacement(mg) {
+				// The resource is reported as up to date so that the
+				// changes are not applied.
+				return managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: conn,
+				}, nil
+			}
+		}
+		resource.SetUpToDateCondition(mg, plan.UpToDate)
+
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  plan.UpToDate,
+			ConnectionDetails: conn,
+		}, nil
+	}
+}
+
+func (e *external) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	forgetRefresh(mg.GetUID())
+	if e.readOnly {
+		return managed.ExternalCreation{}, errors.New(errReadOnly)
+	}
+	if err := e.freeze(mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if e.config.UseAsync {
+		return managed.ExternalCreation{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(mg.GetName())), errStartAsyncApply)
+	}
+	tr, ok := mg.(resource.Terraformed)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errUnexpectedObject)
+	}
+	res, err := e.workspace.Apply(ctx)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errApply)
+	}
+	tfstate := map[string]any{}
+	if err := json.JSParser.Unmarshal(res.State.GetAttributes(), &tfstate); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "cannot unmarshal state attributes")
+	}
+
+	conn, err := resource.GetConnectionDetails(tfstate, tr, e.config)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "cannot get connection details")
+	}
+
+	// NOTE(muvaf): Only spec and metadata changes are saved after Create call.
+	_, err = resource.SetCriticalAnnotations(tr, e.config, tfstate, string(res.State.GetPrivateRaw()))
+	return managed.ExternalCreation{ConnectionDetails: conn}, errors.Wrap(err, "cannot set critical annotations")
+}
+
+func (e *external) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	forgetRefresh(mg.GetUID())
+	if e.readOnly {
+		return managed.ExternalUpdate{}, errors.New(errReadOnly)
+	}
+	if err := e.freeze(mg); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if e.config.UseAsync {
+		return managed.ExternalUpdate{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(mg.GetName())), errStartAsyncApply)
+	}
+	tr, ok := mg.(resource.Terraformed)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errUnexpectedObject)
+	}
+	res, err := e.workspace.Apply(ctx)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errApply)
+	}
+	attr := map[string]any{}
+	if err := json.JSParser.Unmarshal(res.State.GetAttributes(), &attr); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot unmarshal state attributes")
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(tr.SetObservation(attr), "cannot set observation")
+}
+
+func (e *external) Delete(ctx context.Context, mg xpresource.Managed) error {
+	if e.readOnly {
+		return errors.New(errReadOnly)
+	}
+	protected, err := deletionProtected(mg)
+	if err != nil {
+		return err
+	}
+	if protected {
+		// The condition is persisted by the managed reconciler along with
+		// the error, which keeps the managed resource in deletion.
+		mg.SetConditions(deletionRefused())
+		return errors.New(errDeletionProtected)
+	}
+	if e.dryRun {
+		reportPlannedChanges(mg, &v1beta1.ChangeSummary{
+			Action:     v1beta1.PlannedActionDelete,
+			Generation: mg.GetGeneration(),
+			PlannedAt:  metav1.Now(),
+		}, e.recorder)
+		return errors.New(errDryRunDelete)
+	}
+	if err := e.freeze(mg); err != nil {
+		return err
+	}
+	if snapshot.Supported(e.config.Name) {
+		available, err := e.takeFinalSnapshot(ctx, mg)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return errors.New(errFinalSnapshotNotAvailable)
+		}
+	}
+	if e.config.UseAsync {
+		return errors.Wrap(e.workspace.DestroyAsync(e.callback.Destroy(mg.GetName())), errStartAsyncDestroy)
+	}
+	return errors.Wrap(e.workspace.Destroy(ctx), errDestroy)
+}
+
+// changeNeeded returns true if the change planned for the given managed
+// resource is to be read from the plan, which is the case if it is reported
+// as planned, as drift or as a blocked replacement.
+func (e *external) changeNeeded(mg xpresource.Managed) bool {
+	return e.readOnly || e.dryRun || driftPossible(mg) ||
+		(awsconfig.IsStateful(e.config.Name) && !replacementApproved(mg))
+}
+
+// planChanges reports the changes that are planned for the given managed
+// resource in dry-run mode by the last plan.
+func (e *external) planChanges(mg xpresource.Managed) error {
+	c := e.planned.change
+	if c == nil {
+		clearPlannedChanges(mg)
+		return nil
+	}
+	reportPlannedChanges(mg, summarize(c, mg.GetGeneration()), e.recorder)
+	return nil
+}
+
+// blockReplacement returns true if the changes of the given managed resource
+// planned by the last plan force the replacement of its external resource,
+// in which case they are reported as blocked.
+func (e *external) blockReplacement(mg xpresource.Managed) bool {
+	c := e.planned.change
+	if c == nil || !c.Actions.Replace() {
+		unblockReplacement(mg)
+		return false
+	}
+	blockReplacement(mg, summarize(c, mg.GetGeneration()), e.recorder)
+	return true
+}
+
+// adopt returns true if the existing external resource with the given
+// Terraform state, which the given managed resource did not create, is
+// adopted according to the adoption policy, or was adopted before. The
+// adoption is recorded on the managed resource, and recorded is true if it
+// was not before, in which case the managed resource is to be updated.
+func (e *external) adopt(mg xpresource.Managed, tfstate map[string]any) (ok, recorded bool, err error) {
+	if adoptionRecorded(mg) {
+		return true, false, nil
+	}
+	var gk schema.GroupKind
+	if e.adoption == v1beta1.AdoptionPolicyAdoptIfTagged {
+		gvk, err := apiutil.GVKForObject(mg, e.kube.Scheme())
+		if err != nil {
+			return false, false, errors.Wrap(err, errGetGVK)
+		}
+		gk = gvk.GroupKind()
+	}
+	if !adoptionAllowed(mg, gk, tfstate, e.adoption) {
+		mg.SetConditions(adoptionRefused(e.adoption))
+		return false, false, nil
+	}
+	adopted(mg)
+	return true, recordAdoption(mg), nil
+}
+
+// reportDrift reports the differences of the external resource of the given
+// managed resource from its desired state planned by the last plan, which
+// are not corrected.
+func (e *external) reportDrift(mg xpresource.Managed) error {
+	c := e.planned.change
+	if c == nil {
+		clearDrift(mg)
+		return nil
+	}
+	s := summarize(c, mg.GetGeneration())
+	reportDrift(mg, s)
+	e.recordDrift(mg, s)
+	return nil
+}
+
+// detectDrift records the differences of the external resource of the given
+// managed resource from its desired state planned by the last plan as drift
+// and returns them.
+func (e *external) detectDrift(mg xpresource.Managed) *v1beta1.ChangeSummary {
+	c := e.planned.change
+	if c == nil {
+		return nil
+	}
+	s := summarize(c, mg.GetGeneration())
+	e.recordDrift(mg, s)
+	return s
+}
+
+// recordDrift records the differences in the given change summary in the
+// drift report of the given managed resource. An event is recorded and the
+// drift is counted only when the differences are different from the ones
+// already reported.
+func (e *external) recordDrift(mg xpresource.Managed, s *v1beta1.ChangeSummary) {
+	if !recordDrift(mg, s) {
+		return
+	}
+	e.recorder.Event(mg, event.Warning(reasonDriftDetected, errors.New(driftMessage(s))))
+	metrics.DriftDetections.WithLabelValues(e.config.ShortGroup, e.config.Kind).Inc()
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/json"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+// Workspace operations recorded by the fakeWorkspace.
+const (
+	opApply        = "Apply"
+	opApplyAsync   = "ApplyAsync"
+	opDestroy      = "Destroy"
+	opDestroyAsync = "DestroyAsync"
+	opRefresh      = "Refresh"
+	opPlan         = "Plan"
+)
+
+// fakeWorkspace is a workspace that returns the given results and records
+// the operations run with it.
+type fakeWorkspace struct {
+	refresh terraform.RefreshResult
+	plan    terraform.PlanResult
+	apply   terraform.ApplyResult
+	err     error
+	ops     []string
+}
+
+func (w *fakeWorkspace) ApplyAsync(terraform.CallbackFn) error {
+	w.ops = append(w.ops, opApplyAsync)
+	return w.err
+}
+
+func (w *fakeWorkspace) Apply(context.Context) (terraform.ApplyResult, error) {
+	w.ops = append(w.ops, opApply)
+	return w.apply, w.err
+}
+
+func (w *fakeWorkspace) DestroyAsync(terraform.CallbackFn) error {
+	w.ops = append(w.ops, opDestroyAsync)
+	return w.err
+}
+
+func (w *fakeWorkspace) Destroy(context.Context) error {
+	w.ops = append(w.ops, opDestroy)
+	return w.err
+}
+
+func (w *fakeWorkspace) Refresh(context.Context) (terraform.RefreshResult, error) {
+	w.ops = append(w.ops, opRefresh)
+	return w.refresh, w.err
+}
+
+func (w *fakeWorkspace) Plan(context.Context) (terraform.PlanResult, error) {
+	w.ops = append(w.ops, opPlan)
+	return w.plan, w.err
+}
+
+// changed returns true if the external resource was changed with the
+// workspace.
+func (w *fakeWorkspace) changed() bool {
+	for _, op := range w.ops {
+		if op != opRefresh && op != opPlan {
+			return true
+		}
+	}
+	return false
+}
+
+// nopCallbacks are the callbacks of the async operations, which do nothing.
+type nopCallbacks struct{}
+
+func (nopCallbacks) Apply(string) terraform.CallbackFn {
+	return func(error, context.Context) error { return nil }
+}
+
+func (nopCallbacks) Destroy(string) terraform.CallbackFn {
+	return func(error, context.Context) error { return nil }
+}
+
+// objectConfig returns the configuration of the aws_s3_object resource that
+// the tests of the external client use.
+func objectConfig(async bool) *config.Resource {
+	return &config.Resource{
+		Name:         "aws_s3_object",
+		ShortGroup:   "s3",
+		Kind:         "Object",
+		UseAsync:     async,
+		ExternalName: config.IdentifierFromProvider,
+		Sensitive:    config.Sensitive{AdditionalConnectionDetailsFn: config.NopAdditionalConnectionDetails},
+	}
+}
+
+// objectState returns the Terraform state of the object with the given key.
+func objectState(key string) *json.StateV4 {
+	return &json.StateV4{Resources: []json.ResourceStateV4{{
+		Instances: []json.InstanceObjectStateV4{{AttributesRaw: []byte(`{"id":"bucket/` + key + `"}`)}},
+	}}}
+}
+
+func TestExternalObserve(t *testing.T) {
+	str := func(s string) *string { return &s }
+	object := func(opts ...func(o *v1beta1.Object)) *v1beta1.Object {
+		o := &v1beta1.Object{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID("observe"), Annotations: map[string]string{meta.AnnotationKeyExternalName: "bucket/key"}},
+			Spec:       v1beta1.ObjectSpec{ForProvider: v1beta1.ObjectParameters{Bucket: str("bucket"), Key: str("key")}},
+		}
+		meta.SetExternalCreateSucceeded(o, time.Unix(0, 0))
+		o.SetConditions(xpv1.Available())
+		for _, f := range opts {
+			f(o)
+		}
+		return o
+	}
+	deleted := func(o *v1beta1.Object) { o.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)}) }
+	notCreated := func(o *v1beta1.Object) { meta.SetExternalCreateSucceeded(o, time.Time{}) }
+	noExternalName := func(o *v1beta1.Object) { meta.RemoveAnnotations(o, meta.AnnotationKeyExternalName) }
+	creating := func(o *v1beta1.Object) { o.SetConditions(xpv1.Creating()) }
+	exists := terraform.RefreshResult{Exists: true, State: objectState("key")}
+
+	type want struct {
+		obs          managed.ExternalObservation
+		err          error
+		ops          []string
+		externalName string
+		ready        xpv1.Condition
+	}
+	cases := map[string]struct {
+		reason   string
+		w        *fakeWorkspace
+		adoption apisv1beta1.AdoptionPolicy
+		mg       *v1beta1.Object
+		want     want
+	}{
+		"RefreshError": {
+			reason: "An error refreshing the external resource should be returned.",
+			w:      &fakeWorkspace{err: errors.New("boom")},
+			mg:     object(),
+			want: want{
+				err:          errors.Wrap(errors.New("boom"), errRefresh),
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"NotFound": {
+			reason: "A missing external resource should be reported as not existing so that it is created.",
+			w:      &fakeWorkspace{},
+			mg:     object(),
+			want:   want{ops: []string{opRefresh}, externalName: "bucket/key", ready: xpv1.Available()},
+		},
+		"Deleted": {
+			reason: "The external resource of a deleted managed resource should be reported as not existing once it is gone.",
+			w:      &fakeWorkspace{},
+			mg:     object(deleted),
+			want:   want{ops: []string{opRefresh}, externalName: "bucket/key", ready: xpv1.Available()},
+		},
+		"Applying": {
+			reason: "An external resource that is being applied should be reported as up to date until the operation finishes.",
+			w:      &fakeWorkspace{refresh: terraform.RefreshResult{Exists: true, IsApplying: true}},
+			mg:     object(),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"CriticalAnnotations": {
+			reason: "The critical annotations should be updated before the external resource is planned.",
+			w:      &fakeWorkspace{refresh: exists},
+			mg:     object(noExternalName),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"Available": {
+			reason: "The managed resource should be marked available before the external resource is planned.",
+			w:      &fakeWorkspace{refresh: exists},
+			mg:     object(creating),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"UpToDate": {
+			reason: "An external resource without planned changes should be up to date.",
+			w:      &fakeWorkspace{refresh: exists, plan: terraform.PlanResult{Exists: true, UpToDate: true}},
+			mg:     object(),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				ops:          []string{opRefresh, opPlan},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"NotUpToDate": {
+			reason: "An external resource with planned changes should not be up to date so that it is updated.",
+			w:      &fakeWorkspace{refresh: exists, plan: terraform.PlanResult{Exists: true}},
+			mg:     object(),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true},
+				ops:          []string{opRefresh, opPlan},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"AdoptionRefused": {
+			reason:   "An existing external resource that the managed resource did not create should not be adopted with the Fail adoption policy.",
+			w:        &fakeWorkspace{refresh: exists},
+			adoption: apisv1beta1.AdoptionPolicyFail,
+			mg:       object(notCreated),
+			want: want{
+				err:          errors.Errorf(errAlreadyExists, apisv1beta1.AdoptionPolicyFail),
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+		"Adopted": {
+			reason:   "An existing external resource that the managed resource did not create should be adopted, and the adoption persisted, with the Adopt adoption policy.",
+			w:        &fakeWorkspace{refresh: exists},
+			adoption: apisv1beta1.AdoptionPolicyAdopt,
+			mg:       object(notCreated),
+			want: want{
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				ops:          []string{opRefresh},
+				externalName: "bucket/key",
+				ready:        xpv1.Available(),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			forgetRefresh(tc.mg.GetUID())
+			e := &external{
+				workspace: tc.w,
+				config:    objectConfig(false),
+				recorder:  event.NewNopRecorder(),
+				planned:   &planWorkspace{},
+				adoption:  tc.adoption,
+				drift:     DriftPolicyCorrect,
+				state:     &workspaceState{},
+			}
+			obs, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ops, tc.w.ops); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want workspace operations, +got workspace operations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want external name, +got external name:\n%s", tc.reason, diff)
+			}
+			if !tc.want.ready.Equal(tc.mg.GetCondition(xpv1.TypeReady)) {
+				t.Errorf("\n%s\nObserve(...): want %s condition, got %s", tc.reason, tc.want.ready.Reason, tc.mg.GetCondition(xpv1.TypeReady).Reason)
+			}
+		})
+	}
+}
+
+func TestExternalCreate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	object := func() *v1beta1.Object {
+		return &v1beta1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: "object", UID: types.UID("create")},
+			Spec:       v1beta1.ObjectSpec{ForProvider: v1beta1.ObjectParameters{Bucket: str("bucket"), Key: str("key")}},
+		}
+	}
+
+	type want struct {
+		cre          managed.ExternalCreation
+		err          error
+		ops          []string
+		externalName string
+	}
+	cases := map[string]struct {
+		reason string
+		w      *fakeWorkspace
+		async  bool
+		want   want
+	}{
+		"Created": {
+			reason: "The external resource should be applied, and its external name recorded.",
+			w:      &fakeWorkspace{apply: terraform.ApplyResult{State: objectState("key")}},
+			want: want{
+				ops:          []string{opApply},
+				externalName: "bucket/key",
+			},
+		},
+		"ApplyError": {
+			reason: "An error applying the external resource should be returned.",
+			w:      &fakeWorkspace{err: errors.New("boom")},
+			want:   want{err: errors.Wrap(errors.New("boom"), errApply), ops: []string{opApply}},
+		},
+		"Async": {
+			reason: "The external resource of an async kind should be applied in the background.",
+			w:      &fakeWorkspace{},
+			async:  true,
+			want:   want{ops: []string{opApplyAsync}},
+		},
+		"AsyncError": {
+			reason: "An error starting to apply the external resource of an async kind should be returned.",
+			w:      &fakeWorkspace{err: errors.New("boom")},
+			async:  true,
+			want:   want{err: errors.Wrap(errors.New("boom"), errStartAsyncApply), ops: []string{opApplyAsync}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := object()
+			e := &external{
+				workspace: tc.w,
+				config:    objectConfig(tc.async),
+				callback:  nopCallbacks{},
+				recorder:  event.NewNopRecorder(),
+				planned:   &planWorkspace{},
+				state:     &workspaceState{},
+			}
+			cre, err := e.Create(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.cre, cre); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want creation, +got creation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ops, tc.w.ops); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want workspace operations, +got workspace operations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(mg)); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want external name, +got external name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalDelete(t *testing.T) {
+	object := func(annotations map[string]string) *v1beta1.Object {
+		o := &v1beta1.Object{ObjectMeta: metav1.ObjectMeta{Name: "object", UID: types.UID("delete"), Annotations: annotations}}
+		o.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)})
+		return o
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      *fakeWorkspace
+		async  bool
+		dryRun bool
+		mg     *v1beta1.Object
+		want   error
+		ops    []string
+	}{
+		"Deleted": {
+			reason: "The external resource should be destroyed.",
+			w:      &fakeWorkspace{},
+			mg:     object(nil),
+			ops:    []string{opDestroy},
+		},
+		"DestroyError": {
+			reason: "An error destroying the external resource should be returned.",
+			w:      &fakeWorkspace{err: errors.New("boom")},
+			mg:     object(nil),
+			want:   errors.Wrap(errors.New("boom"), errDestroy),
+			ops:    []string{opDestroy},
+		},
+		"Async": {
+			reason: "The external resource of an async kind should be destroyed in the background.",
+			w:      &fakeWorkspace{},
+			async:  true,
+			mg:     object(nil),
+			ops:    []string{opDestroyAsync},
+		},
+		"Protected": {
+			reason: "A protected external resource should not be destroyed.",
+			w:      &fakeWorkspace{},
+			mg:     object(map[string]string{AnnotationKeyDeletionProtection: "true"}),
+			want:   errors.New(errDeletionProtected),
+		},
+		"DryRun": {
+			reason: "The external resource of a managed resource in dry-run mode should not be destroyed.",
+			w:      &fakeWorkspace{},
+			dryRun: true,
+			mg:     object(nil),
+			want:   errors.New(errDryRunDelete),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				workspace: tc.w,
+				config:    objectConfig(tc.async),
+				callback:  nopCallbacks{},
+				recorder:  event.NewNopRecorder(),
+				planned:   &planWorkspace{},
+				dryRun:    tc.dryRun,
+				state:     &workspaceState{},
+			}
+			err := e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.ops, tc.w.ops); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want workspace operations, +got workspace operations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
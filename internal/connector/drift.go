@@ -0,0 +1,171 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+// A DriftPolicy determines whether the drift of an external resource is
+// corrected.
+type DriftPolicy string
+
+// Drift policies.
+const (
+	// DriftPolicyCorrect corrects the drift as soon as it is detected.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+	// DriftPolicyReport only reports the drift, which is never corrected.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyCorrectAfter reports the drift and corrects it once the
+	// grace period has passed since it was detected.
+	DriftPolicyCorrectAfter DriftPolicy = "CorrectAfter"
+)
+
+const (
+	// AnnotationKeyDriftPolicy is the annotation that sets the drift policy
+	// of a managed resource. It is one of Correct, Report or CorrectAfter and
+	// defaults to Correct.
+	AnnotationKeyDriftPolicy = "aws.upbound.io/drift-policy"
+	// AnnotationKeyDriftGracePeriod is the annotation that sets how long the
+	// drift of a managed resource with the CorrectAfter drift policy is left
+	// uncorrected, such as 30m or 4h.
+	AnnotationKeyDriftGracePeriod = "aws.upbound.io/drift-grace-period"
+
+	// maxDriftFields is the maximum number of drifted attributes listed in
+	// the drift report of a managed resource.
+	maxDriftFields = 32
+
+	reasonDriftDetected event.Reason = "DriftDetected"
+
+	errInvalidDriftPolicy    = "invalid drift policy %q in the " + AnnotationKeyDriftPolicy + " annotation"
+	errParseDriftGracePeriod = "cannot parse the " + AnnotationKeyDriftGracePeriod + " annotation"
+	errNoDriftGracePeriod    = "the " + AnnotationKeyDriftGracePeriod + " annotation is required with the CorrectAfter drift policy"
+)
+
+// driftPolicy returns the drift policy of the given managed resource and the
+// grace period of the CorrectAfter policy.
+func driftPolicy(mg xpresource.Managed) (DriftPolicy, time.Duration, error) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyDriftPolicy]
+	if !ok {
+		return DriftPolicyCorrect, 0, nil
+	}
+	switch p := DriftPolicy(v); p {
+	case DriftPolicyCorrect, DriftPolicyReport:
+		return p, 0, nil
+	case DriftPolicyCorrectAfter:
+		g, ok := mg.GetAnnotations()[AnnotationKeyDriftGracePeriod]
+		if !ok {
+			return "", 0, errors.New(errNoDriftGracePeriod)
+		}
+		d, err := time.ParseDuration(g)
+		if err != nil {
+			return "", 0, errors.Wrap(err, errParseDriftGracePeriod)
+		}
+		return p, d, nil
+	default:
+		return "", 0, errors.Errorf(errInvalidDriftPolicy, v)
+	}
+}
+
+// correctDrift returns true if the drift reported for the given managed
+// resource is to be corrected at the given time according to the given
+// policy and grace period.
+func correctDrift(mg xpresource.Managed, p DriftPolicy, grace time.Duration, now time.Time) bool {
+	switch p {
+	case DriftPolicyReport:
+		return false
+	case DriftPolicyCorrectAfter:
+		dr, ok := mg.(driftReporter)
+		if !ok {
+			return true
+		}
+		d := dr.GetDrift()
+		return d == nil || d.DetectedAt == nil || now.Sub(d.DetectedAt.Time) >= grace
+	default:
+		return true
+	}
+}
+
+// driftReporter is a managed resource that reports the drift of its external
+// resource.
+type driftReporter interface {
+	GetDrift() *v1beta1.DriftReport
+	SetDrift(r *v1beta1.DriftReport)
+}
+
+// markSynced records that the external resource of the given managed resource
+// is in sync with its current generation, which clears any reported drift.
+func markSynced(mg xpresource.Managed) {
+	dr, ok := mg.(driftReporter)
+	if !ok {
+		return
+	}
+	if d := dr.GetDrift(); d != nil && d.SyncedGeneration == mg.GetGeneration() && d.DetectedAt == nil && len(d.Fields) == 0 {
+		return
+	}
+	dr.SetDrift(&v1beta1.DriftReport{SyncedGeneration: mg.GetGeneration()})
+}
+
+// driftPossible returns true if the differences of the external resource of
+// the given managed resource from its desired state are caused by changes
+// made outside of the provider, that is, the external resource was observed
+// to be in sync with the current generation of the managed resource before.
+func driftPossible(mg xpresource.Managed) bool {
+	dr, ok := mg.(driftReporter)
+	if !ok {
+		return false
+	}
+	d := dr.GetDrift()
+	return d != nil && d.SyncedGeneration == mg.GetGeneration()
+}
+
+// recordDrift records the attributes changed in the given change summary as
+// the drift of the external resource of the given managed resource and
+// returns true if they are different from the ones already recorded.
+func recordDrift(mg xpresource.Managed, s *v1beta1.ChangeSummary) bool {
+	dr, ok := mg.(driftReporter)
+	if !ok {
+		return false
+	}
+	last := dr.GetDrift()
+	d := &v1beta1.DriftReport{Fields: s.Changes}
+	if last != nil {
+		d.SyncedGeneration = last.SyncedGeneration
+	}
+	if len(d.Fields) > maxDriftFields {
+		d.Fields, d.Truncated = d.Fields[:maxDriftFields], true
+	}
+	if last != nil && last.DetectedAt != nil && last.Truncated == d.Truncated && reflect.DeepEqual(last.Fields, d.Fields) {
+		// The same drift is not reported as newly detected on every poll.
+		return false
+	}
+	now := metav1.NewTime(time.Now())
+	d.DetectedAt = &now
+	dr.SetDrift(d)
+	return true
+}
+
+// driftMessage returns a message that describes the drift in the given
+// change summary.
+func driftMessage(s *v1beta1.ChangeSummary) string {
+	if len(s.Changes) == 0 {
+		return "Drift detected"
+	}
+	attrs := make([]string, 0, len(s.Changes))
+	for _, c := range s.Changes {
+		attrs = append(attrs, c.Path)
+	}
+	return fmt.Sprintf("Drift detected in %d attribute(s): %s", len(attrs), strings.Join(attrs, ", "))
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+type driftManaged struct {
+	fake.Managed
+	drift *v1beta1.DriftReport
+}
+
+func (m *driftManaged) GetDrift() *v1beta1.DriftReport {
+	return m.drift
+}
+
+func (m *driftManaged) SetDrift(r *v1beta1.DriftReport) {
+	m.drift = r
+}
+
+func TestRecordDrift(t *testing.T) {
+	str := func(s string) *string { return &s }
+	detectedAt := metav1.Now()
+	changes := []v1beta1.AttributeChange{{Path: "instance_class", Before: str(`"db.t3.large"`), After: str(`"db.t3.micro"`)}}
+	var many []v1beta1.AttributeChange
+	for i := 0; i < maxDriftFields+1; i++ {
+		many = append(many, v1beta1.AttributeChange{Path: fmt.Sprintf("attr_%02d", i)})
+	}
+	type want struct {
+		recorded bool
+		drift    *v1beta1.DriftReport
+	}
+	cases := map[string]struct {
+		reason  string
+		last    *v1beta1.DriftReport
+		changes []v1beta1.AttributeChange
+		want    want
+	}{
+		"NewDrift": {
+			reason:  "A drift of a resource in sync should be recorded with its synced generation.",
+			last:    &v1beta1.DriftReport{SyncedGeneration: 3},
+			changes: changes,
+			want: want{
+				recorded: true,
+				drift:    &v1beta1.DriftReport{SyncedGeneration: 3, Fields: changes},
+			},
+		},
+		"SameDrift": {
+			reason:  "A drift that is already recorded should not be recorded again.",
+			last:    &v1beta1.DriftReport{SyncedGeneration: 3, DetectedAt: &detectedAt, Fields: changes},
+			changes: changes,
+			want: want{
+				recorded: false,
+				drift:    &v1beta1.DriftReport{SyncedGeneration: 3, DetectedAt: &detectedAt, Fields: changes},
+			},
+		},
+		"Truncated": {
+			reason:  "The drifted attributes beyond the maximum should be truncated.",
+			last:    &v1beta1.DriftReport{SyncedGeneration: 3},
+			changes: many,
+			want: want{
+				recorded: true,
+				drift:    &v1beta1.DriftReport{SyncedGeneration: 3, Fields: many[:maxDriftFields], Truncated: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &driftManaged{drift: tc.last}
+			got := recordDrift(mg, &v1beta1.ChangeSummary{Action: v1beta1.PlannedActionUpdate, Changes: tc.changes})
+			if diff := cmp.Diff(tc.want.recorded, got); diff != "" {
+				t.Errorf("\n%s\nrecordDrift(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drift, mg.drift, cmpopts.IgnoreFields(v1beta1.DriftReport{}, "DetectedAt")); diff != "" {
+				t.Errorf("\n%s\nrecordDrift(...): -want drift, +got drift:\n%s", tc.reason, diff)
+			}
+			if mg.drift.DetectedAt == nil {
+				t.Errorf("\n%s\nrecordDrift(...): DetectedAt is not set", tc.reason)
+			}
+		})
+	}
+}
+
+func TestDriftPossible(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		generation int64
+		drift      *v1beta1.DriftReport
+		want       bool
+	}{
+		"NeverSynced": {
+			reason:     "Differences of a resource that was never in sync should not be drift.",
+			generation: 1,
+			want:       false,
+		},
+		"SpecChanged": {
+			reason:     "Differences caused by a new generation should not be drift.",
+			generation: 2,
+			drift:      &v1beta1.DriftReport{SyncedGeneration: 1},
+			want:       false,
+		},
+		"Synced": {
+			reason:     "Differences of a resource that was in sync with its current generation should be drift.",
+			generation: 2,
+			drift:      &v1beta1.DriftReport{SyncedGeneration: 2},
+			want:       true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &driftManaged{drift: tc.drift}
+			mg.SetGeneration(tc.generation)
+			if diff := cmp.Diff(tc.want, driftPossible(mg)); diff != "" {
+				t.Errorf("\n%s\ndriftPossible(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDriftPolicy(t *testing.T) {
+	type want struct {
+		policy DriftPolicy
+		grace  time.Duration
+		err    error
+	}
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        want
+	}{
+		"Default": {
+			reason: "The drift should be corrected when no drift policy is set.",
+			want:   want{policy: DriftPolicyCorrect},
+		},
+		"Report": {
+			reason:      "The Report drift policy should be returned.",
+			annotations: map[string]string{AnnotationKeyDriftPolicy: "Report"},
+			want:        want{policy: DriftPolicyReport},
+		},
+		"CorrectAfter": {
+			reason: "The CorrectAfter drift policy should be returned with its grace period.",
+			annotations: map[string]string{
+				AnnotationKeyDriftPolicy:      "CorrectAfter",
+				AnnotationKeyDriftGracePeriod: "30m",
+			},
+			want: want{policy: DriftPolicyCorrectAfter, grace: 30 * time.Minute},
+		},
+		"NoGracePeriod": {
+			reason:      "The CorrectAfter drift policy should require a grace period.",
+			annotations: map[string]string{AnnotationKeyDriftPolicy: "CorrectAfter"},
+			want:        want{err: errors.New(errNoDriftGracePeriod)},
+		},
+		"Invalid": {
+			reason:      "An unknown drift policy should be an error.",
+			annotations: map[string]string{AnnotationKeyDriftPolicy: "Ignore"},
+			want:        want{err: errors.Errorf(errInvalidDriftPolicy, "Ignore")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			policy, grace, err := driftPolicy(mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndriftPolicy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.policy, policy); diff != "" {
+				t.Errorf("\n%s\ndriftPolicy(...): -want policy, +got policy:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.grace, grace); diff != "" {
+				t.Errorf("\n%s\ndriftPolicy(...): -want grace period, +got grace period:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCorrectDrift(t *testing.T) {
+	now := time.Now()
+	detectedAt := metav1.NewTime(now.Add(-10 * time.Minute))
+	cases := map[string]struct {
+		reason string
+		policy DriftPolicy
+		grace  time.Duration
+		want   bool
+	}{
+		"Correct": {
+			reason: "The drift should be corrected with the Correct policy.",
+			policy: DriftPolicyCorrect,
+			want:   true,
+		},
+		"Report": {
+			reason: "The drift should never be corrected with the Report policy.",
+			policy: DriftPolicyReport,
+			want:   false,
+		},
+		"WithinGracePeriod": {
+			reason: "The drift should not be corrected within the grace period of the CorrectAfter policy.",
+			policy: DriftPolicyCorrectAfter,
+			grace:  time.Hour,
+			want:   false,
+		},
+		"GracePeriodPassed": {
+			reason: "The drift should be corrected once the grace period of the CorrectAfter policy has passed.",
+			policy: DriftPolicyCorrectAfter,
+			grace:  5 * time.Minute,
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &driftManaged{drift: &v1beta1.DriftReport{DetectedAt: &detectedAt}}
+			if diff := cmp.Diff(tc.want, correctDrift(mg, tc.policy, tc.grace, now)); diff != "" {
+				t.Errorf("\n%s\ncorrectDrift(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	// AnnotationKeyDryRun is the annotation that overrides the dry-run mode
+	// of the ProviderConfig of a managed resource. When it is "true", the
+	// changes of the resource are only planned and reported in its
+	// status.plannedChanges field and events, and never applied.
+	AnnotationKeyDryRun = "aws.upbound.io/dry-run"
+
+	// TypeDryRun is the type of the condition that reports whether the
+	// changes of a managed resource are planned but not applied because of
+	// the dry-run mode.
+	TypeDryRun xpv1.ConditionType = "DryRun"
+
+	// ReasonChangesPlanned is the reason of the DryRun condition when there
+	// are changes that are not applied.
+	ReasonChangesPlanned xpv1.ConditionReason = "ChangesPlanned"
+	// ReasonNoChangesPlanned is the reason of the DryRun condition when the
+	// external resource is up to date.
+	ReasonNoChangesPlanned xpv1.ConditionReason = "NoChangesPlanned"
+
+	reasonChangesPlanned event.Reason = "ChangesPlanned"
+
+	errGetProviderConfig = "cannot get referenced ProviderConfig"
+	errParseDryRun       = "cannot parse the " + AnnotationKeyDryRun + " annotation"
+	errDryRunDelete      = "deletion is not applied in dry-run mode"
+)
+
+// changesReporter is a managed resource that reports the changes planned in
+// dry-run mode.
+type changesReporter interface {
+	GetPlannedChanges() *v1beta1.ChangeSummary
+	SetPlannedChanges(c *v1beta1.ChangeSummary)
+}
+
+// providerConfig returns the ProviderConfig referenced by the given managed
+// resource, or nil if it does not reference one.
+func providerConfig(ctx context.Context, kube client.Client, mg xpresource.Managed) (*v1beta1.ProviderConfig, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return nil, nil
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfig)
+	}
+	return pc, nil
+}
+
+// dryRun returns whether the changes of the given managed resource are only
+// planned. The annotation of the resource takes precedence over its
+// ProviderConfig.
+func dryRun(mg xpresource.Managed, pc *v1beta1.ProviderConfig) (bool, error) {
+	if v, ok := mg.GetAnnotations()[AnnotationKeyDryRun]; ok {
+		b, err := strconv.ParseBool(v)
+		return b, errors.Wrap(err, errParseDryRun)
+	}
+	return pc != nil && pc.Spec.DryRun, nil
+}
+
+// changesPlanned returns a condition that indicates the changes described
+// by the given message are planned but not applied.
+func changesPlanned(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChangesPlanned,
+		Message:            msg,
+	}
+}
+
+// noChangesPlanned returns a condition that indicates there are no changes
+// that are not applied.
+func noChangesPlanned() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoChangesPlanned,
+	}
+}
+
+// reportPlannedChanges publishes the given change summary in the status of
+// the given managed resource and records an event if they have changed
+// since the last report.
+func reportPlannedChanges(mg xpresource.Managed, s *v1beta1.ChangeSummary, r event.Recorder) {
+	msg := describe(s)
+	if cr, ok := mg.(changesReporter); ok {
+		last := cr.GetPlannedChanges()
+		switch {
+		case last == nil || last.Generation != s.Generation || describe(last) != msg:
+			r.Event(mg, event.Normal(reasonChangesPlanned, msg))
+		default:
+			// The same changes are not reported as newly planned on every
+			// poll.
+			s.PlannedAt = last.PlannedAt
+		}
+		cr.SetPlannedChanges(s)
+	}
+	if c := mg.GetCondition(TypeDryRun); c.Status != corev1.ConditionTrue || c.Message != msg {
+		mg.SetConditions(changesPlanned(msg))
+	}
+}
+
+// clearPlannedChanges removes the planned changes from the status of the
+// given managed resource.
+func clearPlannedChanges(mg xpresource.Managed) {
+	if cr, ok := mg.(changesReporter); ok {
+		cr.SetPlannedChanges(nil)
+	}
+	if c := mg.GetCondition(TypeDryRun); c.Status == corev1.ConditionTrue {
+		mg.SetConditions(noChangesPlanned())
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/provider-aws/internal/encryption"
+)
+
+const (
+	errSealState      = "cannot encrypt the Terraform state"
+	errUnsealState    = "cannot decrypt the Terraform state"
+	errReadWorkspaces = "cannot read the workspaces"
+	errRemovePlan     = "cannot remove the Terraform plan"
+)
+
+// stateFiles are the files of the workspaces that hold the Terraform state.
+var stateFiles = []string{"terraform.tfstate", "terraform.tfstate.backup"}
+
+var (
+	stateEncrypterMu sync.RWMutex
+	stateEncrypter   *encryption.Encrypter
+)
+
+// SetStateEncrypter sets the Encrypter that encrypts the Terraform state of
+// the workspaces at rest. The state is decrypted into the workspace only
+// while Terraform runs, which includes the async operations. The state is not
+// encrypted if it is not set.
+func SetStateEncrypter(e *encryption.Encrypter) {
+	stateEncrypterMu.Lock()
+	defer stateEncrypterMu.Unlock()
+	stateEncrypter = e
+}
+
+func getStateEncrypter() *encryption.Encrypter {
+	stateEncrypterMu.RLock()
+	defer stateEncrypterMu.RUnlock()
+	return stateEncrypter
+}
+
+// sealState encrypts the state files of the workspace in the given directory
+// with the given Encrypter, if any.
+func sealState(ctx context.Context, enc *encryption.Encrypter, dir string) error {
+	if enc == nil {
+		return nil
+	}
+	for _, f := range stateFiles {
+		if err := enc.Seal(ctx, filepath.Join(dir, f)); err != nil {
+			return errors.Wrap(err, errSealState)
+		}
+	}
+	return nil
+}
+
+// unsealState decrypts the state files of the workspace in the given
+// directory with the given Encrypter, if any.
+func unsealState(ctx context.Context, enc *encryption.Encrypter, dir string) error {
+	if enc == nil {
+		return nil
+	}
+	for _, f := range stateFiles {
+		if err := enc.Unseal(ctx, filepath.Join(dir, f)); err != nil {
+			return errors.Wrap(err, errUnsealState)
+		}
+	}
+	return nil
+}
+
+// SealWorkspaces encrypts the plaintext state files the workspaces in the
+// given directory were left with when the provider stopped, such as while an
+// async operation was running, with the given Encrypter, and removes their
+// plan files, which hold the state too. It is expected to be called before
+// the controllers start.
+func SealWorkspaces(ctx context.Context, enc *encryption.Encrypter, root string) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return errors.Wrap(err, errReadWorkspaces)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if err := os.Remove(filepath.Join(dir, filePlan)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return errors.Wrap(err, errRemovePlan)
+		}
+		if err := sealState(ctx, enc, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
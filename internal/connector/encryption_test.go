@@ -0,0 +1,129 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/internal/encryption"
+)
+
+// stateReader is a workspace whose refreshes read the state file.
+type stateReader struct {
+	tjcontroller.Workspace
+	dir  string
+	read []byte
+}
+
+func (w *stateReader) Refresh(_ context.Context) (terraform.RefreshResult, error) {
+	var err error
+	w.read, err = os.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	return terraform.RefreshResult{}, err
+}
+
+func TestStateWorkspaceEncryption(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	state := []byte(`{"master_password":"secret"}`)
+	if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), state, 0600); err != nil {
+		t.Fatal(err)
+	}
+	kw, err := encryption.NewSecretKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := encryption.New(kw)
+	if err := sealState(ctx, enc, dir); err != nil {
+		t.Fatalf("sealState(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		running bool
+		want    bool
+	}{
+		"Finished": {
+			reason: "The state should be decrypted during the operation and encrypted again afterwards.",
+			want:   false,
+		},
+		"Running": {
+			reason:  "The state should be left decrypted while an async operation is running.",
+			running: true,
+			want:    true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := &stateReader{dir: dir}
+			w := &stateWorkspace{Workspace: sr, state: &workspaceState{enc: enc, dir: dir}, running: func() bool { return tc.running }}
+			if _, err := w.Refresh(ctx); err != nil {
+				t.Fatalf("\n%s\nRefresh(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(string(state), string(sr.read)); diff != "" {
+				t.Errorf("\n%s\nRefresh(...): -want state, +got state:\n%s", tc.reason, diff)
+			}
+			_, err := os.Stat(filepath.Join(dir, "terraform.tfstate"))
+			if diff := cmp.Diff(tc.want, err == nil); diff != "" {
+				t.Errorf("\n%s\nRefresh(...): -want plaintext state, +got plaintext state:\n%s", tc.reason, diff)
+			}
+			if err := sealState(ctx, enc, dir); err != nil {
+				t.Fatalf("sealState(...): %v", err)
+			}
+		})
+	}
+}
+
+func TestSealWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	kw, err := encryption.NewSecretKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := encryption.New(kw)
+	root := t.TempDir()
+	dir := filepath.Join(root, "0c3bd1a4-8a1e-4b8e-a0a4-3c0e5e1f9d2a")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// The provider stopped while an async operation was running.
+	for _, f := range []string{"terraform.tfstate", filePlan} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(`{"master_password":"secret"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "terraformrc"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SealWorkspaces(ctx, enc, root); err != nil {
+		t.Fatalf("SealWorkspaces(...): %v", err)
+	}
+	for f, want := range map[string]bool{
+		"terraform.tfstate":                        false,
+		"terraform.tfstate" + encryption.Extension: true,
+		filePlan: false,
+	} {
+		_, err := os.Stat(filepath.Join(dir, f))
+		if diff := cmp.Diff(want, err == nil); diff != "" {
+			t.Errorf("SealWorkspaces(...): the plaintext state and plans should be removed and the state encrypted: -want %s, +got %s:\n%s", f, f, diff)
+		}
+	}
+	if err := unsealState(ctx, enc, dir); err != nil {
+		t.Fatalf("unsealState(...): %v", err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "terraform.tfstate"))
+	if diff := cmp.Diff(`{"master_password":"secret"}`, string(got)); diff != "" {
+		t.Errorf("SealWorkspaces(...): -want state, +got state:\n%s", diff)
+	}
+}
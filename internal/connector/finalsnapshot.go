@@ -0,0 +1,144 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+	"github.com/upbound/provider-aws/internal/snapshot"
+)
+
+const (
+	// TagKeyExternalName is the tag of the final snapshots that records the
+	// external name of the managed resource they were taken of.
+	TagKeyExternalName = "crossplane-external-name"
+
+	reasonFinalSnapshotCreating  event.Reason = "FinalSnapshotCreating"
+	reasonFinalSnapshotAvailable event.Reason = "FinalSnapshotAvailable"
+
+	errGetFinalSnapshot          = "cannot get the FinalSnapshot"
+	errCreateFinalSnapshot       = "cannot create the FinalSnapshot"
+	errUpdateFinalSnapshot       = "cannot update the status of the FinalSnapshot"
+	errGetAWSConfig              = "cannot get the AWS configuration"
+	errGetGVK                    = "cannot get the kind of the managed resource"
+	errTakeFinalSnapshot         = "cannot take the final snapshot"
+	errObserveFinalSnapshot      = "cannot observe the final snapshot"
+	errFinalSnapshotUnsupported  = "final snapshots are not supported for %s"
+	errFinalSnapshotNotAvailable = "waiting for the final snapshot to be available before deletion"
+)
+
+// takeFinalSnapshot takes a final snapshot of the external resource of the
+// given managed resource, which is recorded with a FinalSnapshot object, and
+// returns true once the snapshot is available. Only one snapshot is taken
+// per managed resource no matter how many times it is called.
+func (e *external) takeFinalSnapshot(ctx context.Context, mg xpresource.Managed) (bool, error) {
+	fs := &v1beta1.FinalSnapshot{}
+	getErr := e.kube.Get(ctx, types.NamespacedName{Name: e.finalSnapshotName(mg)}, fs)
+	if xpresource.IgnoreNotFound(getErr) != nil {
+		return false, errors.Wrap(getErr, errGetFinalSnapshot)
+	}
+	if fs.Status.State == v1beta1.FinalSnapshotStateAvailable {
+		return true, nil
+	}
+	cfg, err := clients.GetAWSConfig(ctx, e.kube, mg)
+	if err != nil {
+		return false, errors.Wrap(err, errGetAWSConfig)
+	}
+	s, ok := snapshot.New(e.config.Name, *cfg)
+	if !ok {
+		return false, errors.Errorf(errFinalSnapshotUnsupported, e.config.Name)
+	}
+	if kerrors.IsNotFound(getErr) {
+		if fs, err = e.newFinalSnapshot(ctx, mg, s, cfg.Region); err != nil {
+			return false, err
+		}
+	}
+	id := fs.Spec.SnapshotIdentifier
+	if fs.Status.State == "" {
+		if err := s.Create(ctx, id, fs.Spec.ExternalName, finalSnapshotTags(mg, fs)); err != nil {
+			return false, errors.Wrap(err, errTakeFinalSnapshot)
+		}
+		fs.Status.State = v1beta1.FinalSnapshotStateCreating
+		if err := e.kube.Status().Update(ctx, fs); err != nil {
+			return false, errors.Wrap(err, errUpdateFinalSnapshot)
+		}
+		e.recorder.Event(mg, event.Normal(reasonFinalSnapshotCreating,
+			fmt.Sprintf("Taking final %s %s before deletion, recorded in FinalSnapshot %s", fs.Spec.SnapshotType, id, fs.GetName())))
+	}
+
+	available, err := s.Available(ctx, id)
+	if err != nil {
+		return false, errors.Wrap(err, errObserveFinalSnapshot)
+	}
+	if !available {
+		return false, nil
+	}
+	now := metav1.Now()
+	fs.Status.State = v1beta1.FinalSnapshotStateAvailable
+	fs.Status.AvailableAt = &now
+	if err := e.kube.Status().Update(ctx, fs); err != nil {
+		return false, errors.Wrap(err, errUpdateFinalSnapshot)
+	}
+	e.recorder.Event(mg, event.Normal(reasonFinalSnapshotAvailable,
+		fmt.Sprintf("Final %s %s is available", fs.Spec.SnapshotType, id)))
+	return true, nil
+}
+
+// newFinalSnapshot creates the FinalSnapshot object that records the final
+// snapshot of the given managed resource before the snapshot is taken, so
+// that the identifier of the snapshot does not change if taking it fails.
+func (e *external) newFinalSnapshot(ctx context.Context, mg xpresource.Managed, s snapshot.Snapshotter, region string) (*v1beta1.FinalSnapshot, error) {
+	gvk, err := apiutil.GVKForObject(mg, e.kube.Scheme())
+	if err != nil {
+		return nil, errors.Wrap(err, errGetGVK)
+	}
+	fs := &v1beta1.FinalSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: e.finalSnapshotName(mg),
+		},
+		Spec: v1beta1.FinalSnapshotSpec{
+			ResourceRef:        *meta.TypedReferenceTo(mg, gvk),
+			ExternalName:       meta.GetExternalName(mg),
+			Region:             region,
+			SnapshotType:       s.Type(),
+			SnapshotIdentifier: snapshot.Identifier(meta.GetExternalName(mg), time.Now()),
+		},
+	}
+	return fs, errors.Wrap(e.kube.Create(ctx, fs), errCreateFinalSnapshot)
+}
+
+// finalSnapshotName returns the name of the FinalSnapshot object of the
+// given managed resource, which is unique because it contains its UID.
+func (e *external) finalSnapshotName(mg xpresource.Managed) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(e.config.Kind), mg.GetUID())
+}
+
+// finalSnapshotTags returns the tags of the given final snapshot of the given
+// managed resource, which identify the managed resource.
+func finalSnapshotTags(mg xpresource.Managed, fs *v1beta1.FinalSnapshot) map[string]string {
+	tags := map[string]string{
+		xpresource.ExternalResourceTagKeyKind: strings.ToLower(fs.Spec.ResourceRef.GroupVersionKind().GroupKind().String()),
+		xpresource.ExternalResourceTagKeyName: mg.GetName(),
+		TagKeyExternalName:                    fs.Spec.ExternalName,
+	}
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		tags[xpresource.ExternalResourceTagKeyProvider] = ref.Name
+	}
+	return tags
+}
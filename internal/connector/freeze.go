@@ -0,0 +1,176 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	// The time zones of the freeze windows must be available in the
+	// provider image, which has no time zone database installed.
+	_ "time/tzdata"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	// AnnotationKeyBypassFreeze is the annotation that lets the external
+	// resource of a managed resource be changed during an active freeze
+	// window when it is "true", such as for emergency changes.
+	AnnotationKeyBypassFreeze = "aws.upbound.io/bypass-freeze"
+
+	// TypeFrozen is the type of the condition that reports whether the
+	// changes of an external resource are deferred because of an active
+	// freeze window.
+	TypeFrozen xpv1.ConditionType = "Frozen"
+
+	// ReasonFreezeWindowActive is the reason of the Frozen condition when a
+	// freeze window is active.
+	ReasonFreezeWindowActive xpv1.ConditionReason = "FreezeWindowActive"
+	// ReasonNoFreezeWindowActive is the reason of the Frozen condition when
+	// no freeze window is active anymore.
+	ReasonNoFreezeWindowActive xpv1.ConditionReason = "NoFreezeWindowActive"
+
+	errParseFreezeSchedule = "cannot parse the schedule %q of the freeze window"
+	errLoadFreezeTimeZone  = "cannot load the time zone %q of the freeze window"
+	errParseBypassFreeze   = "cannot parse the " + AnnotationKeyBypassFreeze + " annotation"
+	errFrozen              = "the changes of the external resource are deferred until %s because of an active freeze window"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// freezeWindow is a parsed v1beta1.FreezeWindow.
+type freezeWindow struct {
+	schedule cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// freezeWindows returns the parsed freeze windows of the given ProviderConfig.
+func freezeWindows(pc *v1beta1.ProviderConfig) ([]freezeWindow, error) {
+	if pc == nil {
+		return nil, nil
+	}
+	fws := make([]freezeWindow, 0, len(pc.Spec.FreezeWindows))
+	for _, w := range pc.Spec.FreezeWindows {
+		s, err := cronParser.Parse(w.Schedule)
+		if err != nil {
+			return nil, errors.Wrapf(err, errParseFreezeSchedule, w.Schedule)
+		}
+		loc, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, errLoadFreezeTimeZone, w.TimeZone)
+		}
+		fws = append(fws, freezeWindow{schedule: s, duration: w.Duration.Duration, location: loc})
+	}
+	return fws, nil
+}
+
+// frozenUntil returns the end of the latest ending window of the given freeze
+// windows that is active at the given time, or the zero time if none is.
+func frozenUntil(fws []freezeWindow, now time.Time) time.Time {
+	var until time.Time
+	for _, w := range fws {
+		// An occurrence of the window is active if it starts after the time
+		// it would have to start at to end now, but not after now. The last
+		// of them ends the latest.
+		var start time.Time
+		for s := w.schedule.Next(now.Add(-w.duration).In(w.location)); !s.After(now); s = w.schedule.Next(s) {
+			start = s
+		}
+		if start.IsZero() {
+			continue
+		}
+		if end := start.Add(w.duration); end.After(until) {
+			until = end
+		}
+	}
+	return until
+}
+
+// FrozenUntil returns until when the changes of the external resource of the
+// given managed resource are deferred according to its Frozen condition, or
+// the zero time if they are not.
+func FrozenUntil(mg xpresource.Conditioned) time.Time {
+	c := mg.GetCondition(TypeFrozen)
+	if c.Status != corev1.ConditionTrue {
+		return time.Time{}
+	}
+	var v string
+	if _, err := fmt.Sscanf(c.Message, errFrozen, &v); err != nil {
+		return time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}
+	}
+	return until
+}
+
+// bypassFreeze returns true if the external resource of the given managed
+// resource can be changed during an active freeze window.
+func bypassFreeze(mg xpresource.Managed) (bool, error) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyBypassFreeze]
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	return b, errors.Wrap(err, errParseBypassFreeze)
+}
+
+// frozen returns a condition that indicates the changes of the external
+// resource are deferred until the given time.
+func frozen(until time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFrozen,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFreezeWindowActive,
+		Message:            fmt.Sprintf(errFrozen, until.UTC().Format(time.RFC3339)),
+	}
+}
+
+// unfreeze marks the Frozen condition of the given managed resource as false
+// if its changes were deferred because of a freeze window.
+func unfreeze(mg xpresource.Managed) {
+	if c := mg.GetCondition(TypeFrozen); c.Status != corev1.ConditionTrue {
+		return
+	}
+	mg.SetConditions(xpv1.Condition{
+		Type:               TypeFrozen,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoFreezeWindowActive,
+	})
+}
+
+// freeze returns an error if the external resource of the given managed
+// resource must not be changed now because of an active freeze window, in
+// which case the Frozen condition reports until when its changes are
+// deferred.
+func (e *external) freeze(mg xpresource.Managed) error {
+	until := frozenUntil(e.freezeWindows, time.Now())
+	bypass, err := bypassFreeze(mg)
+	if err != nil {
+		return err
+	}
+	if until.IsZero() || bypass {
+		unfreeze(mg)
+		return nil
+	}
+	// The condition is persisted by the managed reconciler along with the
+	// error, and the poll reconciler requeues the resource when the window
+	// ends.
+	c := frozen(until)
+	mg.SetConditions(c)
+	return errors.New(c.Message)
+}
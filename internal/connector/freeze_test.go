@@ -0,0 +1,141 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+func TestFrozenUntil(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every Friday at 18:00 in New York for 60 hours, which is until Monday
+	// at 06:00.
+	weekend := v1beta1.FreezeWindow{
+		Schedule: "0 18 * * FRI",
+		Duration: metav1.Duration{Duration: 60 * time.Hour},
+		TimeZone: "America/New_York",
+	}
+	cases := map[string]struct {
+		reason  string
+		windows []v1beta1.FreezeWindow
+		now     time.Time
+		want    time.Time
+	}{
+		"NoWindows": {
+			reason: "Nothing should be frozen without freeze windows.",
+			now:    time.Date(2022, 11, 19, 12, 0, 0, 0, ny),
+		},
+		"Active": {
+			reason:  "Changes should be frozen until the end of an active window.",
+			windows: []v1beta1.FreezeWindow{weekend},
+			now:     time.Date(2022, 11, 19, 12, 0, 0, 0, ny),
+			want:    time.Date(2022, 11, 21, 6, 0, 0, 0, ny),
+		},
+		"Inactive": {
+			reason:  "Nothing should be frozen outside of the windows.",
+			windows: []v1beta1.FreezeWindow{weekend},
+			now:     time.Date(2022, 11, 21, 6, 0, 0, 0, ny),
+		},
+		"TimeZone": {
+			reason:  "The schedule should be evaluated in the time zone of the window.",
+			windows: []v1beta1.FreezeWindow{weekend},
+			now:     time.Date(2022, 11, 18, 22, 30, 0, 0, time.UTC),
+		},
+		"Overlapping": {
+			reason: "Changes should be frozen until the end of the latest ending active window.",
+			windows: []v1beta1.FreezeWindow{weekend, {
+				Schedule: "0 0 19 11 *",
+				Duration: metav1.Duration{Duration: 7 * 24 * time.Hour},
+			}},
+			now:  time.Date(2022, 11, 20, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 11, 26, 0, 0, 0, 0, time.UTC),
+		},
+		"OverlappingOccurrences": {
+			reason: "Changes should be frozen until the end of the latest starting active occurrence of a window.",
+			windows: []v1beta1.FreezeWindow{{
+				Schedule: "0 * * * *",
+				Duration: metav1.Duration{Duration: 3 * time.Hour},
+			}},
+			now:  time.Date(2022, 11, 20, 12, 30, 0, 0, time.UTC),
+			want: time.Date(2022, 11, 20, 15, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fws, err := freezeWindows(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{FreezeWindows: tc.windows}})
+			if err != nil {
+				t.Fatalf("\n%s\nfreezeWindows(...): unexpected error: %v", tc.reason, err)
+			}
+			got := frozenUntil(fws, tc.now)
+			if !got.Equal(tc.want) {
+				t.Errorf("\n%s\nfrozenUntil(...): -want, +got:\n%s", tc.reason, cmp.Diff(tc.want.UTC(), got.UTC()))
+			}
+		})
+	}
+}
+
+func TestFrozenUntilCondition(t *testing.T) {
+	until := time.Date(2022, 11, 21, 11, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		reason string
+		c      xpv1.Condition
+		want   time.Time
+	}{
+		"Frozen": {
+			reason: "The end of the freeze window should be read from the Frozen condition.",
+			c:      frozen(until),
+			want:   until,
+		},
+		"NotFrozen": {
+			reason: "Nothing should be frozen without a true Frozen condition.",
+			c:      xpv1.Available(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetConditions(tc.c)
+			got := FrozenUntil(mg)
+			if !got.Equal(tc.want) {
+				t.Errorf("\n%s\nFrozenUntil(...): -want, +got:\n%s", tc.reason, cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestFreezeWindowsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		window v1beta1.FreezeWindow
+	}{
+		"Schedule": {
+			reason: "An invalid cron schedule should be an error.",
+			window: v1beta1.FreezeWindow{Schedule: "every friday"},
+		},
+		"TimeZone": {
+			reason: "An unknown time zone should be an error.",
+			window: v1beta1.FreezeWindow{Schedule: "0 18 * * FRI", TimeZone: "Mars/Olympus_Mons"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := freezeWindows(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{FreezeWindows: []v1beta1.FreezeWindow{tc.window}}})
+			if err == nil {
+				t.Errorf("\n%s\nfreezeWindows(...): expected an error", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/resource"
+	"github.com/upbound/upjet/pkg/types/name"
+)
+
+const (
+	// AnnotationKeyIgnoreChanges is the annotation that lists the comma
+	// separated paths of the spec.forProvider fields whose changes are
+	// ignored, such as "desiredCapacity" or "tags.team". They are set on
+	// creation but never updated afterwards, like the fields listed in the
+	// ignore_changes lifecycle argument of Terraform.
+	AnnotationKeyIgnoreChanges = "aws.upbound.io/ignore-changes"
+
+	prefixForProvider = "spec.forProvider."
+	fileMainTF        = "main.tf.json"
+
+	errParseIgnoreChanges = "cannot parse the " + AnnotationKeyIgnoreChanges + " annotation"
+	errReadMainTF         = "cannot read main tf file"
+	errWriteMainTF        = "cannot write main tf file"
+	errUnknownField       = "unknown field %q"
+)
+
+// ignoreChanges returns the Terraform attribute references of the fields
+// listed in the ignore changes annotation of the given resource.
+func ignoreChanges(tr resource.Terraformed, sch map[string]*schema.Schema) ([]string, error) {
+	v := tr.GetAnnotations()[AnnotationKeyIgnoreChanges]
+	if v == "" {
+		return nil, nil
+	}
+	var refs []string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimPrefix(strings.TrimSpace(p), prefixForProvider)
+		if p == "" {
+			continue
+		}
+		ref, err := attributeReference(p, sch)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseIgnoreChanges)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// attributeReference converts the given spec.forProvider field path to a
+// Terraform attribute reference of the given schema, such as
+// tags["team"] for tags.team.
+func attributeReference(path string, sch map[string]*schema.Schema) (string, error) {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	var ref strings.Builder
+	var s *schema.Schema
+	for _, seg := range segments {
+		switch {
+		case seg.Type == fieldpath.SegmentIndex:
+			fmt.Fprintf(&ref, "[%d]", seg.Index)
+			continue
+		case s != nil && s.Type == schema.TypeMap:
+			fmt.Fprintf(&ref, "[%q]", seg.Field)
+			s = nil
+			continue
+		case s != nil:
+			r, ok := s.Elem.(*schema.Resource)
+			if !ok {
+				return "", errors.Errorf(errUnknownField, path)
+			}
+			sch = r.Schema
+		}
+		k, ok := tfName(seg.Field, sch)
+		if !ok {
+			return "", errors.Errorf(errUnknownField, path)
+		}
+		if ref.Len() > 0 {
+			ref.WriteString(".")
+		}
+		ref.WriteString(k)
+		s = sch[k]
+	}
+	return ref.String(), nil
+}
+
+// tfName returns the Terraform name of the given CRD field name.
+func tfName(field string, sch map[string]*schema.Schema) (string, bool) {
+	for k := range sch {
+		if name.NewFromSnake(k).LowerCamelComputed == field {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// writeIgnoreChanges adds the given attribute references to the
+// ignore_changes lifecycle argument of the resource in the main Terraform
+// configuration file in the given directory.
+func writeIgnoreChanges(dir string, tr resource.Terraformed, refs []string) error {
+	f := filepath.Join(dir, fileMainTF)
+	raw, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return errors.Wrap(err, errReadMainTF)
+	}
+	// Numbers are kept as they are so that large integers are not rounded.
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	m := map[string]any{}
+	if err := d.Decode(&m); err != nil {
+		return errors.Wrap(err, errReadMainTF)
+	}
+	types, _ := m["resource"].(map[string]any)
+	resources, _ := types[tr.GetTerraformResourceType()].(map[string]any)
+	p, ok := resources[tr.GetName()].(map[string]any)
+	if !ok {
+		return errors.New(errReadMainTF)
+	}
+	lc, _ := p["lifecycle"].(map[string]any)
+	if lc == nil {
+		lc = map[string]any{}
+	}
+	lc["ignore_changes"] = refs
+	p["lifecycle"] = lc
+	raw, err = json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, errWriteMainTF)
+	}
+	return errors.Wrap(os.WriteFile(f, raw, 0600), errWriteMainTF)
+}
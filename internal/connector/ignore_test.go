@@ -0,0 +1,62 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAttributeReference(t *testing.T) {
+	sch := map[string]*schema.Schema{
+		"desired_capacity": {Type: schema.TypeInt},
+		"tags":             {Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeString}},
+		"ebs_block_device": {Type: schema.TypeList, Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"volume_size": {Type: schema.TypeInt},
+			},
+		}},
+	}
+	type want struct {
+		ref string
+		err bool
+	}
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   want
+	}{
+		"Field": {
+			reason: "A top level field should be converted to its Terraform name.",
+			path:   "desiredCapacity",
+			want:   want{ref: "desired_capacity"},
+		},
+		"MapKey": {
+			reason: "A map key should be converted to an index.",
+			path:   "tags.team",
+			want:   want{ref: `tags["team"]`},
+		},
+		"NestedField": {
+			reason: "A field of a block should be converted to its Terraform name.",
+			path:   "ebsBlockDevice[0].volumeSize",
+			want:   want{ref: "ebs_block_device[0].volume_size"},
+		},
+		"UnknownField": {
+			reason: "An unknown field should return an error.",
+			path:   "desiredCount",
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, err := attributeReference(tc.path, sch)
+			if diff := cmp.Diff(tc.want, want{ref: ref, err: err != nil}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nattributeReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+)
+
+var (
+	nativeConnectersMu sync.RWMutex
+	nativeConnecters   map[string]managed.ExternalConnecter
+)
+
+// SetNativeConnecters sets the connecters of the external clients that manage
+// the external resources of some kinds with the AWS SDK instead of Terraform,
+// by the names of the Terraform resources of the kinds. The managed resources
+// of those kinds are connected to them instead of Terraform workspaces.
+func SetNativeConnecters(c map[string]managed.ExternalConnecter) {
+	nativeConnectersMu.Lock()
+	defer nativeConnectersMu.Unlock()
+	nativeConnecters = c
+}
+
+func getNativeConnecter(name string) managed.ExternalConnecter {
+	nativeConnectersMu.RLock()
+	defer nativeConnectersMu.RUnlock()
+	return nativeConnecters[name]
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/upjet/pkg/resource"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const errGetObservation = "cannot get observation"
+
+// nativeSupported returns true if the managed resources of a kind with a
+// native external client can be managed by it with the given policies. The
+// dry-run mode, the ignored changes and the drift policies other than
+// Correct rely on Terraform plans, so the managed resources using them are
+// managed with Terraform.
+func nativeSupported(dryRun bool, ignored []string, drift DriftPolicy) bool {
+	return !dryRun && len(ignored) == 0 && drift == DriftPolicyCorrect
+}
+
+// nativeExternal enforces the read-only mode, the deletion protection, the
+// freeze windows and the adoption policy for a native external client, the
+// same way the Terraform based external client does.
+type nativeExternal struct {
+	managed.ExternalClient
+	policy *external
+}
+
+func (n *nativeExternal) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	allowDeletion(mg)
+	if frozenUntil(n.policy.freezeWindows, time.Now()).IsZero() {
+		unfreeze(mg)
+	}
+	if n.policy.readOnly && meta.WasDeleted(mg) {
+		// The external resource of a read-only managed resource is left
+		// as it is, like with the Orphan deletion policy.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	obs, err := n.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	if !obs.ResourceExists {
+		if n.policy.readOnly {
+			return managed.ExternalObservation{}, errors.New(errReadOnlyNotFound)
+		}
+		return obs, nil
+	}
+	if !created(mg) && !n.policy.readOnly {
+		tr, ok := mg.(resource.Terraformed)
+		if !ok {
+			return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+		}
+		tfstate, err := tr.GetObservation()
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetObservation)
+		}
+		ok, recorded, err := n.policy.adopt(mg, tfstate)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		// The managed resource is updated to persist the recorded
+		// adoption.
+		obs.ResourceLateInitialized = obs.ResourceLateInitialized || recorded
+		switch {
+		case !ok && meta.WasDeleted(mg):
+			// The external resource that is not adopted is not deleted
+			// either.
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		case !ok:
+			return managed.ExternalObservation{}, errors.Errorf(errAlreadyExists, n.policy.adoption)
+		}
+	}
+	if n.policy.readOnly {
+		if obs.ResourceUpToDate {
+			clearDrift(mg)
+			return obs, nil
+		}
+		// The native clients do not tell which attributes differ, so
+		// only the update that would correct the drift is reported.
+		reportDrift(mg, &v1beta1.ChangeSummary{
+			Action:     v1beta1.PlannedActionUpdate,
+			Generation: mg.GetGeneration(),
+			PlannedAt:  metav1.Now(),
+		})
+		obs.ResourceUpToDate = true
+	}
+	return obs, nil
+}
+
+func (n *nativeExternal) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	if n.policy.readOnly {
+		return managed.ExternalCreation{}, errors.New(errReadOnly)
+	}
+	if err := n.policy.freeze(mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	return n.ExternalClient.Create(ctx, mg)
+}
+
+func (n *nativeExternal) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	if n.policy.readOnly {
+		return managed.ExternalUpdate{}, errors.New(errReadOnly)
+	}
+	if err := n.policy.freeze(mg); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return n.ExternalClient.Update(ctx, mg)
+}
+
+func (n *nativeExternal) Delete(ctx context.Context, mg xpresource.Managed) error {
+	if n.policy.readOnly {
+		return errors.New(errReadOnly)
+	}
+	protected, err := deletionProtected(mg)
+	if err != nil {
+		return err
+	}
+	if protected {
+		// The condition is persisted by the managed reconciler along with
+		// the error, which keeps the managed resource in deletion.
+		mg.SetConditions(deletionRefused())
+		return errors.New(errDeletionProtected)
+	}
+	if err := n.policy.freeze(mg); err != nil {
+		return err
+	}
+	return n.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/iam/v1beta1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+func TestNativeExternal(t *testing.T) {
+	// The yearly freeze window lasting two years is always active, and ends
+	// at the start of the next year.
+	frozenAlways, err := freezeWindows(&apisv1beta1.ProviderConfig{Spec: apisv1beta1.ProviderConfigSpec{
+		FreezeWindows: []apisv1beta1.FreezeWindow{{Schedule: "0 0 1 1 *", Duration: metav1.Duration{Duration: 2 * 365 * 24 * time.Hour}, TimeZone: "UTC"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	until := frozenUntil(frozenAlways, time.Now())
+	attachment := func(opts ...func(a *v1beta1.RolePolicyAttachment)) *v1beta1.RolePolicyAttachment {
+		a := &v1beta1.RolePolicyAttachment{ObjectMeta: metav1.ObjectMeta{Name: "admin"}}
+		for _, f := range opts {
+			f(a)
+		}
+		return a
+	}
+	createdBefore := func(a *v1beta1.RolePolicyAttachment) { meta.SetExternalCreateSucceeded(a, time.Unix(0, 0)) }
+	deleted := func(a *v1beta1.RolePolicyAttachment) { a.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)}) }
+	protected := func(a *v1beta1.RolePolicyAttachment) {
+		meta.AddAnnotations(a, map[string]string{AnnotationKeyDeletionProtection: "true"})
+	}
+
+	type want struct {
+		obs    managed.ExternalObservation
+		err    error
+		called bool
+		// conditions are the types and statuses of the conditions of the
+		// managed resource.
+		conditions map[xpv1.ConditionType]corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		reason string
+		policy *external
+		obs    managed.ExternalObservation
+		mg     *v1beta1.RolePolicyAttachment
+		op     func(n *nativeExternal, mg xpresource.Managed) (managed.ExternalObservation, error)
+		want   want
+	}{
+		"ReadOnlyDeleted": {
+			reason: "The external resource of a deleted read-only managed resource should be left as it is.",
+			policy: &external{readOnly: true},
+			mg:     attachment(createdBefore, deleted),
+			op:     observe,
+			want:   want{obs: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"ReadOnlyNotFound": {
+			reason: "A missing external resource of a read-only managed resource should not be created.",
+			policy: &external{readOnly: true},
+			mg:     attachment(),
+			op:     observe,
+			want:   want{err: errors.New(errReadOnlyNotFound), called: true},
+		},
+		"ReadOnlyDrift": {
+			reason: "The drift of the external resource of a read-only managed resource should be reported and not corrected.",
+			policy: &external{readOnly: true},
+			obs:    managed.ExternalObservation{ResourceExists: true},
+			mg:     attachment(),
+			op:     observe,
+			want: want{
+				obs:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				called:     true,
+				conditions: map[xpv1.ConditionType]corev1.ConditionStatus{TypeDrifted: corev1.ConditionTrue},
+			},
+		},
+		"AdoptionRefused": {
+			reason: "An existing external resource should not be adopted with the Fail adoption policy.",
+			policy: &external{adoption: apisv1beta1.AdoptionPolicyFail},
+			obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			mg:     attachment(),
+			op:     observe,
+			want: want{
+				err:        errors.Errorf(errAlreadyExists, apisv1beta1.AdoptionPolicyFail),
+				called:     true,
+				conditions: map[xpv1.ConditionType]corev1.ConditionStatus{TypeAlreadyExists: corev1.ConditionTrue},
+			},
+		},
+		"Adopted": {
+			reason: "An existing external resource that is adopted should be recorded as adopted on the managed resource, which is updated.",
+			policy: &external{adoption: apisv1beta1.AdoptionPolicyAdopt},
+			obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			mg:     attachment(),
+			op:     observe,
+			want:   want{obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true}, called: true},
+		},
+		"Created": {
+			reason: "The external resource the managed resource created should be observed as it is.",
+			policy: &external{adoption: apisv1beta1.AdoptionPolicyFail},
+			obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			mg:     attachment(createdBefore),
+			op:     observe,
+			want:   want{obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, called: true},
+		},
+		"ReadOnlyCreate": {
+			reason: "The external resource of a read-only managed resource should not be created.",
+			policy: &external{readOnly: true},
+			mg:     attachment(),
+			op:     create,
+			want:   want{err: errors.New(errReadOnly)},
+		},
+		"FrozenUpdate": {
+			reason: "The external resource should not be updated during an active freeze window.",
+			policy: &external{freezeWindows: frozenAlways},
+			mg:     attachment(createdBefore),
+			op:     update,
+			want: want{
+				err:        errors.New(frozen(until).Message),
+				conditions: map[xpv1.ConditionType]corev1.ConditionStatus{TypeFrozen: corev1.ConditionTrue},
+			},
+		},
+		"ProtectedDelete": {
+			reason: "A protected external resource should not be deleted.",
+			policy: &external{},
+			mg:     attachment(createdBefore, deleted, protected),
+			op:     remove,
+			want: want{
+				err:        errors.New(errDeletionProtected),
+				conditions: map[xpv1.ConditionType]corev1.ConditionStatus{TypeDeletionProtected: corev1.ConditionTrue},
+			},
+		},
+		"Delete": {
+			reason: "An unprotected external resource should be deleted.",
+			policy: &external{},
+			mg:     attachment(createdBefore, deleted),
+			op:     remove,
+			want:   want{called: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			n := &nativeExternal{policy: tc.policy, ExternalClient: managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalObservation, error) {
+					called = true
+					return tc.obs, nil
+				},
+				CreateFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalCreation, error) {
+					called = true
+					return managed.ExternalCreation{}, nil
+				},
+				UpdateFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalUpdate, error) {
+					called = true
+					return managed.ExternalUpdate{}, nil
+				},
+				DeleteFn: func(_ context.Context, _ xpresource.Managed) error {
+					called = true
+					return nil
+				},
+			}}
+			obs, err := tc.op(n, tc.mg)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\n-want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("\n%s\n-want native client called, +got native client called:\n%s", tc.reason, diff)
+			}
+			for ct, status := range tc.want.conditions {
+				if diff := cmp.Diff(status, tc.mg.GetCondition(ct).Status); diff != "" {
+					t.Errorf("\n%s\n-want %s condition, +got %s condition:\n%s", tc.reason, ct, ct, diff)
+				}
+			}
+		})
+	}
+}
+
+func observe(n *nativeExternal, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	return n.Observe(context.Background(), mg)
+}
+
+func create(n *nativeExternal, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	_, err := n.Create(context.Background(), mg)
+	return managed.ExternalObservation{}, err
+}
+
+func update(n *nativeExternal, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	_, err := n.Update(context.Background(), mg)
+	return managed.ExternalObservation{}, err
+}
+
+func remove(n *nativeExternal, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	return managed.ExternalObservation{}, n.Delete(context.Background(), mg)
+}
+
+func TestNativeSupported(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		dryRun  bool
+		ignored []string
+		drift   DriftPolicy
+		want    bool
+	}{
+		"Supported": {
+			reason: "Managed resources without policies that rely on Terraform plans should be managed by the native clients.",
+			drift:  DriftPolicyCorrect,
+			want:   true,
+		},
+		"DryRun": {
+			reason: "Managed resources in dry-run mode should be managed with Terraform.",
+			dryRun: true,
+			drift:  DriftPolicyCorrect,
+		},
+		"IgnoredChanges": {
+			reason:  "Managed resources with ignored changes should be managed with Terraform.",
+			ignored: []string{"policy_arn"},
+			drift:   DriftPolicyCorrect,
+		},
+		"ReportDrift": {
+			reason: "Managed resources that only report their drift should be managed with Terraform.",
+			drift:  DriftPolicyReport,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, nativeSupported(tc.dryRun, tc.ignored, tc.drift)); diff != "" {
+				t.Errorf("\n%s\nnativeSupported(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sexec "k8s.io/utils/exec"
+
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	filePlan = "connector.tfplan"
+
+	// maxValueLength is the maximum length of the values reported in a
+	// change summary.
+	maxValueLength = 256
+
+	redactedValue = `"(sensitive value)"`
+
+	envReattachProviders = "TF_REATTACH_PROVIDERS"
+
+	errStartProvider = "cannot start the shared provider"
+	errPlanOut       = "cannot save the terraform plan"
+	errShowPlan      = "cannot show the terraform plan"
+	errParsePlan     = "cannot parse the terraform plan"
+)
+
+var (
+	providerRunnerMu sync.RWMutex
+	providerRunner   terraform.ProviderRunner = terraform.NewNoOpProviderRunner()
+)
+
+// SetProviderRunner sets the runner of the shared native provider that the
+// Terraform commands run by the connectors, in addition to the ones run by
+// the upjet workspaces, attach to. It must be the runner of the workspace
+// store of the controllers.
+func SetProviderRunner(r terraform.ProviderRunner) {
+	providerRunnerMu.Lock()
+	defer providerRunnerMu.Unlock()
+	providerRunner = r
+}
+
+// plannedChange is the planned change of a resource in the JSON output of
+// terraform show, which also lists the attribute paths that force the
+// replacement of the resource.
+type plannedChange struct {
+	tfjson.Change
+	ReplacePaths []any `json:"replace_paths,omitempty"`
+}
+
+// planOutput is the subset of the JSON output of terraform show that is used to
+// summarize the planned changes.
+type planOutput struct {
+	ResourceChanges []struct {
+		Mode   tfjson.ResourceMode `json:"mode"`
+		Change *plannedChange      `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// planWorkspace plans the changes of the underlying workspace, and keeps the
+// planned change of the last plan, so that it is read without planning again.
+// The change is only read from a saved plan if it is needed, since saving and
+// showing a plan takes two Terraform commands instead of one.
+type planWorkspace struct {
+	tjcontroller.Workspace
+	dir      string
+	executor k8sexec.Interface
+
+	// detailed is true if the next plan is to read the planned change,
+	// such as to report it.
+	detailed bool
+
+	// change is the change planned by the last plan, or nil if no changes
+	// were planned or the last plan did not read them.
+	change *plannedChange
+}
+
+func (w *planWorkspace) Plan(ctx context.Context) (terraform.PlanResult, error) {
+	w.change = nil
+	if !w.detailed {
+		return w.Workspace.Plan(ctx)
+	}
+	c, err := w.showPlan(ctx)
+	if err != nil {
+		return terraform.PlanResult{}, err
+	}
+	w.change = c
+	return terraform.PlanResult{UpToDate: c == nil}, nil
+}
+
+// showPlan runs terraform plan in the workspace and returns the planned change
+// of its resource, or nil if no changes are planned. The state is not
+// refreshed, so it should be called right after a refresh. The saved plan,
+// which holds the state, is removed once it is read.
+func (w *planWorkspace) showPlan(ctx context.Context) (*plannedChange, error) {
+	providerRunnerMu.RLock()
+	attachment, err := providerRunner.Start()
+	providerRunnerMu.RUnlock()
+	if err != nil {
+		return nil, errors.Wrap(err, errStartProvider)
+	}
+	// Terraform runs with the environment of the workspace, which attaches
+	// it to the shared provider.
+	env := append(os.Environ(), fmt.Sprintf("%s=%s", envReattachProviders, attachment))
+	plan := filepath.Join(w.dir, filePlan)
+	defer os.Remove(plan) //nolint:errcheck // removed below unless the plan fails
+
+	if out, err := w.run(ctx, env, "plan", "-refresh=false", "-input=false", "-out="+filePlan); err != nil {
+		return nil, errors.Wrapf(err, "%s: %s", errPlanOut, out)
+	}
+	out, err := w.run(ctx, env, "show", "-json", filePlan)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: %s", errShowPlan, out)
+	}
+	if err := os.Remove(plan); err != nil {
+		return nil, errors.Wrap(err, errRemovePlan)
+	}
+	p := &planOutput{}
+	if err := json.Unmarshal(out, p); err != nil {
+		return nil, errors.Wrap(err, errParsePlan)
+	}
+	for _, rc := range p.ResourceChanges {
+		if rc.Mode != tfjson.ManagedResourceMode || rc.Change == nil || rc.Change.Actions.NoOp() || rc.Change.Actions.Read() {
+			continue
+		}
+		return rc.Change, nil
+	}
+	return nil, nil
+}
+
+// run runs the given Terraform command in the workspace with the executor of
+// the workspaces and returns its output, or its error output if it fails.
+func (w *planWorkspace) run(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	cmd := w.executor.CommandContext(ctx, "terraform", args...)
+	cmd.SetDir(w.dir)
+	cmd.SetEnv(env)
+	stderr := &bytes.Buffer{}
+	cmd.SetStderr(stderr)
+	out, err := cmd.Output()
+	if err != nil {
+		return stderr.Bytes(), err
+	}
+	return out, nil
+}
+
+// summarize returns the summary of the given planned change.
+func summarize(c *plannedChange, generation int64) *v1beta1.ChangeSummary {
+	s := &v1beta1.ChangeSummary{
+		Action:     plannedAction(c.Actions),
+		Generation: generation,
+		PlannedAt:  metav1.NewTime(time.Now()),
+	}
+	if s.Action == v1beta1.PlannedActionDelete {
+		return s
+	}
+	before, _ := c.Before.(map[string]any)
+	after, _ := c.After.(map[string]any)
+	unknown, _ := c.AfterUnknown.(map[string]any)
+	beforeSensitive, _ := c.BeforeSensitive.(map[string]any)
+	afterSensitive, _ := c.AfterSensitive.(map[string]any)
+	replace := replacePaths(c.ReplacePaths)
+
+	keys := map[string]struct{}{}
+	for _, m := range []map[string]any{before, after, unknown} {
+		for k := range m {
+			keys[k] = struct{}{}
+		}
+	}
+	for k := range keys {
+		u := isTrue(unknown[k])
+		if !u && reflect.DeepEqual(before[k], after[k]) {
+			continue
+		}
+		if before[k] == nil && after[k] == nil && !u {
+			continue
+		}
+		ac := v1beta1.AttributeChange{Path: k}
+		_, ac.ForcesReplacement = replace[k]
+		ac.Before = value(before[k], beforeSensitive[k])
+		if !u {
+			ac.After = value(after[k], afterSensitive[k])
+		}
+		s.Changes = append(s.Changes, ac)
+	}
+	sort.Slice(s.Changes, func(i, j int) bool {
+		return s.Changes[i].Path < s.Changes[j].Path
+	})
+	return s
+}
+
+func plannedAction(a tfjson.Actions) v1beta1.PlannedAction {
+	switch {
+	case a.Replace():
+		return v1beta1.PlannedActionReplace
+	case a.Create():
+		return v1beta1.PlannedActionCreate
+	case a.Delete():
+		return v1beta1.PlannedActionDelete
+	default:
+		return v1beta1.PlannedActionUpdate
+	}
+}
+
+// replacePaths returns the top level attributes of the given paths that
+// force the replacement of a resource.
+func replacePaths(paths []any) map[string]struct{} {
+	r := map[string]struct{}{}
+	for _, p := range paths {
+		steps, ok := p.([]any)
+		if !ok || len(steps) == 0 {
+			continue
+		}
+		if k, ok := steps[0].(string); ok {
+			r[k] = struct{}{}
+		}
+	}
+	return r
+}
+
+// value returns the JSON encoding of the given attribute value, or nil if
+// it's null. Sensitive values are redacted.
+func value(v any, sensitive any) *string {
+	if v == nil {
+		return nil
+	}
+	s := redactedValue
+	if !isSensitive(sensitive) {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		s = string(raw)
+	}
+	if len(s) > maxValueLength {
+		s = s[:maxValueLength] + "..."
+	}
+	return &s
+}
+
+// isSensitive returns true if the given sensitivity of an attribute, which
+// mirrors the structure of its value, marks any part of it as sensitive.
+func isSensitive(v any) bool {
+	switch s := v.(type) {
+	case bool:
+		return s
+	case []any:
+		for _, e := range s {
+			if isSensitive(e) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, e := range s {
+			if isSensitive(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTrue(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// describe returns a short human readable description of the given change
+// summary.
+func describe(s *v1beta1.ChangeSummary) string {
+	if len(s.Changes) == 0 {
+		return fmt.Sprintf("%s planned", s.Action)
+	}
+	var attrs, replace []string
+	for _, c := range s.Changes {
+		attrs = append(attrs, c.Path)
+		if c.ForcesReplacement {
+			replace = append(replace, c.Path)
+		}
+	}
+	msg := fmt.Sprintf("%s planned for %d attribute(s): %s", s.Action, len(attrs), strings.Join(attrs, ", "))
+	if len(replace) > 0 {
+		msg += fmt.Sprintf("; replacement forced by: %s", strings.Join(replace, ", "))
+	}
+	return msg
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	k8sexec "k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+func TestSummarize(t *testing.T) {
+	str := func(s string) *string { return &s }
+	cases := map[string]struct {
+		reason string
+		change string
+		want   *v1beta1.ChangeSummary
+	}{
+		"Update": {
+			reason: "Only the changed attributes should be reported, with their sensitive values redacted.",
+			change: `{
+				"actions": ["update"],
+				"before": {"engine_version": "13.4", "password": "old", "port": 5432},
+				"after": {"engine_version": "14.1", "password": "new", "port": 5432},
+				"after_sensitive": {"password": true}
+			}`,
+			want: &v1beta1.ChangeSummary{
+				Action:     v1beta1.PlannedActionUpdate,
+				Generation: 2,
+				Changes: []v1beta1.AttributeChange{
+					{Path: "engine_version", Before: str(`"13.4"`), After: str(`"14.1"`)},
+					{Path: "password", Before: str(`"old"`), After: str(redactedValue)},
+				},
+			},
+		},
+		"Replace": {
+			reason: "The attributes that force the replacement should be marked and unknown values omitted.",
+			change: `{
+				"actions": ["delete", "create"],
+				"before": {"arn": "arn:1", "identifier": "a"},
+				"after": {"identifier": "b"},
+				"after_unknown": {"arn": true},
+				"replace_paths": [["identifier"]]
+			}`,
+			want: &v1beta1.ChangeSummary{
+				Action:     v1beta1.PlannedActionReplace,
+				Generation: 2,
+				Changes: []v1beta1.AttributeChange{
+					{Path: "arn", Before: str(`"arn:1"`)},
+					{Path: "identifier", Before: str(`"a"`), After: str(`"b"`), ForcesReplacement: true},
+				},
+			},
+		},
+		"Create": {
+			reason: "The attributes of a resource to be created should be reported without the null ones.",
+			change: `{
+				"actions": ["create"],
+				"before": null,
+				"after": {"name": "a", "description": null}
+			}`,
+			want: &v1beta1.ChangeSummary{
+				Action:     v1beta1.PlannedActionCreate,
+				Generation: 2,
+				Changes: []v1beta1.AttributeChange{
+					{Path: "name", After: str(`"a"`)},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &plannedChange{}
+			if err := json.Unmarshal([]byte(tc.change), c); err != nil {
+				t.Fatal(err)
+			}
+			got := summarize(c, 2)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(v1beta1.ChangeSummary{}, "PlannedAt")); diff != "" {
+				t.Errorf("\n%s\nsummarize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPlanWorkspacePlan(t *testing.T) {
+	show := []byte(`{"resource_changes": [{"mode": "managed", "change": {"actions": ["update"], "before": {"acl": "public-read"}, "after": {"acl": "private"}}}]}`)
+	errBoom := errors.New("boom")
+
+	type want struct {
+		res    terraform.PlanResult
+		err    error
+		change bool
+		ops    []string
+		cmds   [][]string
+	}
+	cases := map[string]struct {
+		reason   string
+		detailed bool
+		w        *fakeWorkspace
+		outputs  []fakeexec.FakeAction
+		want     want
+	}{
+		"NotDetailed": {
+			reason: "The changes should be planned by the workspace if the planned change is not needed.",
+			w:      &fakeWorkspace{plan: terraform.PlanResult{Exists: true}},
+			want: want{
+				res: terraform.PlanResult{Exists: true},
+				ops: []string{opPlan},
+			},
+		},
+		"Detailed": {
+			reason:   "The planned change should be read from a saved plan if it is needed.",
+			detailed: true,
+			w:        &fakeWorkspace{},
+			outputs: []fakeexec.FakeAction{
+				func() ([]byte, []byte, error) { return nil, nil, nil },
+				func() ([]byte, []byte, error) { return show, nil, nil },
+			},
+			want: want{
+				change: true,
+				cmds: [][]string{
+					{"terraform", "plan", "-refresh=false", "-input=false", "-out=" + filePlan},
+					{"terraform", "show", "-json", filePlan},
+				},
+			},
+		},
+		"DetailedUpToDate": {
+			reason:   "The resource should be up to date if the saved plan plans no changes.",
+			detailed: true,
+			w:        &fakeWorkspace{},
+			outputs: []fakeexec.FakeAction{
+				func() ([]byte, []byte, error) { return nil, nil, nil },
+				func() ([]byte, []byte, error) { return []byte(`{}`), nil, nil },
+			},
+			want: want{
+				res: terraform.PlanResult{UpToDate: true},
+				cmds: [][]string{
+					{"terraform", "plan", "-refresh=false", "-input=false", "-out=" + filePlan},
+					{"terraform", "show", "-json", filePlan},
+				},
+			},
+		},
+		"PlanError": {
+			reason:   "The error of a failed plan should be returned.",
+			detailed: true,
+			w:        &fakeWorkspace{},
+			outputs: []fakeexec.FakeAction{
+				func() ([]byte, []byte, error) { return nil, nil, errBoom },
+			},
+			want: want{
+				err:  errors.Wrapf(errBoom, "%s: %s", errPlanOut, ""),
+				cmds: [][]string{{"terraform", "plan", "-refresh=false", "-input=false", "-out=" + filePlan}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			exec := &fakeexec.FakeExec{}
+			var cmds [][]string
+			for i := range tc.outputs {
+				out := tc.outputs[i]
+				exec.CommandScript = append(exec.CommandScript, func(cmd string, args ...string) k8sexec.Cmd {
+					cmds = append(cmds, append([]string{cmd}, args...))
+					// The plan is saved as terraform plan would save it.
+					if args[0] == "plan" {
+						if err := os.WriteFile(filepath.Join(dir, filePlan), []byte("plan"), 0600); err != nil {
+							t.Fatal(err)
+						}
+					}
+					return &fakeexec.FakeCmd{OutputScript: []fakeexec.FakeAction{out}}
+				})
+			}
+			w := &planWorkspace{Workspace: tc.w, dir: dir, executor: exec, detailed: tc.detailed, change: &plannedChange{}}
+			res, err := w.Plan(context.Background())
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.change, w.change != nil); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want planned change, +got planned change:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ops, tc.w.ops); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want workspace operations, +got workspace operations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cmds, cmds); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want commands, +got commands:\n%s", tc.reason, diff)
+			}
+			if _, err := os.Stat(filepath.Join(dir, filePlan)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("\n%s\nPlan(...): the saved plan was not removed: %v", tc.reason, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationKeyDeletionProtection is the annotation that protects the
+	// external resource of a managed resource from deletion. When it is
+	// "true", the external resource is not destroyed when the managed
+	// resource is deleted, which stays in deletion until the annotation is
+	// removed or set to "false".
+	AnnotationKeyDeletionProtection = "aws.upbound.io/deletion-protection"
+
+	// TypeDeletionProtected is the type of the condition that reports
+	// whether the deletion of an external resource is refused because of
+	// the deletion protection annotation.
+	TypeDeletionProtected xpv1.ConditionType = "DeletionProtected"
+
+	// ReasonDeletionRefused is the reason of the DeletionProtected condition
+	// when the deletion of a protected external resource is refused.
+	ReasonDeletionRefused xpv1.ConditionReason = "DeletionRefused"
+	// ReasonDeletionAllowed is the reason of the DeletionProtected condition
+	// when the protection of a previously refused deletion is removed.
+	ReasonDeletionAllowed xpv1.ConditionReason = "DeletionAllowed"
+
+	errParseDeletionProtection = "cannot parse the " + AnnotationKeyDeletionProtection + " annotation"
+	errDeletionProtected       = "the external resource is protected from deletion by the " + AnnotationKeyDeletionProtection + " annotation"
+)
+
+// deletionProtected returns true if the external resource of the given
+// managed resource is protected from deletion.
+func deletionProtected(mg xpresource.Managed) (bool, error) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyDeletionProtection]
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	return b, errors.Wrap(err, errParseDeletionProtection)
+}
+
+// deletionRefused returns a condition that indicates the deletion of the
+// external resource is refused because it is protected.
+func deletionRefused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionRefused,
+		Message:            errDeletionProtected,
+	}
+}
+
+// allowDeletion marks the DeletionProtected condition of the given managed
+// resource as false if a deletion was refused but the resource is no longer
+// protected.
+func allowDeletion(mg xpresource.Managed) {
+	if c := mg.GetCondition(TypeDeletionProtected); c.Status != corev1.ConditionTrue {
+		return
+	}
+	if protected, err := deletionProtected(mg); err != nil || protected {
+		return
+	}
+	mg.SetConditions(xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionAllowed,
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeletionProtected(t *testing.T) {
+	type want struct {
+		protected bool
+		err       bool
+	}
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        want
+	}{
+		"NotAnnotated": {
+			reason: "A resource without the annotation should not be protected.",
+		},
+		"Protected": {
+			reason:      "A resource annotated with true should be protected.",
+			annotations: map[string]string{AnnotationKeyDeletionProtection: "true"},
+			want:        want{protected: true},
+		},
+		"Unprotected": {
+			reason:      "A resource annotated with false should not be protected.",
+			annotations: map[string]string{AnnotationKeyDeletionProtection: "false"},
+		},
+		"Invalid": {
+			reason:      "An invalid annotation should return an error.",
+			annotations: map[string]string{AnnotationKeyDeletionProtection: "yes please"},
+			want:        want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			protected, err := deletionProtected(mg)
+			if diff := cmp.Diff(tc.want, want{protected: protected, err: err != nil}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ndeletionProtected(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
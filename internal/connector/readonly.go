@@ -0,0 +1,67 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	// TypeDrifted is the type of the condition that reports whether an
+	// external resource differs from the desired state of its managed
+	// resource but the differences are not corrected.
+	TypeDrifted xpv1.ConditionType = "Drifted"
+
+	// ReasonDriftDetected is the reason of the Drifted condition when the
+	// external resource differs from the desired state.
+	ReasonDriftDetected xpv1.ConditionReason = "DriftDetected"
+	// ReasonNoDrift is the reason of the Drifted condition when the external
+	// resource no longer differs from the desired state.
+	ReasonNoDrift xpv1.ConditionReason = "NoDrift"
+
+	errReadOnly         = "the external resource is not changed because its ProviderConfig is read-only"
+	errReadOnlyNotFound = "the external resource does not exist and is not created because its ProviderConfig is read-only"
+)
+
+// drifted returns a condition that indicates the external resource differs
+// from the desired state in the way described by the given message.
+func drifted(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDriftDetected,
+		Message:            msg,
+	}
+}
+
+// reportDrift reports the differences in the given change summary with the
+// Drifted condition of the given managed resource.
+func reportDrift(mg xpresource.Managed, s *v1beta1.ChangeSummary) {
+	msg := describe(s)
+	if c := mg.GetCondition(TypeDrifted); c.Status == corev1.ConditionTrue && c.Message == msg {
+		return
+	}
+	mg.SetConditions(drifted(msg))
+}
+
+// clearDrift marks the Drifted condition of the given managed resource as
+// false if a drift was reported before.
+func clearDrift(mg xpresource.Managed) {
+	if c := mg.GetCondition(TypeDrifted); c.Status != corev1.ConditionTrue {
+		return
+	}
+	mg.SetConditions(xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDrift,
+	})
+}
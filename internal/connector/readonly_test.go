@@ -0,0 +1,255 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+func TestReportDrift(t *testing.T) {
+	s := &apisv1beta1.ChangeSummary{
+		Action:  apisv1beta1.PlannedActionUpdate,
+		Changes: []apisv1beta1.AttributeChange{{Path: "acl"}},
+	}
+	before := metav1.NewTime(time.Unix(0, 0))
+
+	cases := map[string]struct {
+		reason string
+		cond   *xpv1.Condition
+		s      *apisv1beta1.ChangeSummary
+		want   xpv1.Condition
+	}{
+		"NotDrifted": {
+			reason: "A drift should be reported with the Drifted condition.",
+			s:      s,
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, Reason: ReasonDriftDetected, Message: "Update planned for 1 attribute(s): acl"},
+		},
+		"SameDrift": {
+			reason: "The drift that is already reported should not be reported again.",
+			cond:   &xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, LastTransitionTime: before, Reason: ReasonDriftDetected, Message: "Update planned for 1 attribute(s): acl"},
+			s:      s,
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, LastTransitionTime: before, Reason: ReasonDriftDetected, Message: "Update planned for 1 attribute(s): acl"},
+		},
+		"OtherDrift": {
+			reason: "A drift different from the one already reported should be reported.",
+			cond:   &xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, LastTransitionTime: before, Reason: ReasonDriftDetected, Message: "Update planned for 1 attribute(s): tags"},
+			s:      s,
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, Reason: ReasonDriftDetected, Message: "Update planned for 1 attribute(s): acl"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			if tc.cond != nil {
+				mg.SetConditions(*tc.cond)
+			}
+			reportDrift(mg, tc.s)
+			// The transitions are only compared if they must not change.
+			var opts []cmp.Option
+			if tc.want.LastTransitionTime.IsZero() {
+				opts = append(opts, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime"))
+			}
+			if diff := cmp.Diff(tc.want, mg.GetCondition(TypeDrifted), opts...); diff != "" {
+				t.Errorf("\n%s\nreportDrift(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClearDrift(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cond   *xpv1.Condition
+		want   xpv1.Condition
+	}{
+		"Drifted": {
+			reason: "A reported drift should be cleared.",
+			cond:   &xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionTrue, Reason: ReasonDriftDetected, Message: "Update planned"},
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionFalse, Reason: ReasonNoDrift},
+		},
+		"NeverDrifted": {
+			reason: "The Drifted condition should not be added if no drift was reported.",
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionUnknown},
+		},
+		"Cleared": {
+			reason: "A cleared drift should be left as it is.",
+			cond:   &xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionFalse, Reason: ReasonNoDrift},
+			want:   xpv1.Condition{Type: TypeDrifted, Status: corev1.ConditionFalse, Reason: ReasonNoDrift},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			if tc.cond != nil {
+				mg.SetConditions(*tc.cond)
+			}
+			clearDrift(mg)
+			if diff := cmp.Diff(tc.want, mg.GetCondition(TypeDrifted), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nclearDrift(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReadOnlyExternal(t *testing.T) {
+	str := func(s string) *string { return &s }
+	object := func(opts ...func(o *v1beta1.Object)) *v1beta1.Object {
+		o := &v1beta1.Object{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID("read-only"), Annotations: map[string]string{meta.AnnotationKeyExternalName: "bucket/key"}},
+			Spec:       v1beta1.ObjectSpec{ForProvider: v1beta1.ObjectParameters{Bucket: str("bucket"), Key: str("key")}},
+		}
+		o.SetConditions(xpv1.Available())
+		for _, f := range opts {
+			f(o)
+		}
+		return o
+	}
+	deleted := func(o *v1beta1.Object) { o.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)}) }
+	drifted := func(o *v1beta1.Object) { o.SetConditions(drifted("Update planned for 1 attribute(s): acl")) }
+	exists := terraform.RefreshResult{Exists: true, State: objectState("key")}
+	change := &plannedChange{Change: tfjson.Change{
+		Actions: tfjson.Actions{tfjson.ActionUpdate},
+		Before:  map[string]any{"acl": "public-read"},
+		After:   map[string]any{"acl": "private"},
+	}}
+
+	type want struct {
+		obs     managed.ExternalObservation
+		err     error
+		changed bool
+		// drifted is the status of the Drifted condition.
+		drifted corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		reason string
+		w      *fakeWorkspace
+		change *plannedChange
+		mg     *v1beta1.Object
+		op     func(e *external, mg *v1beta1.Object) (managed.ExternalObservation, error)
+		want   want
+	}{
+		"ObserveDeleted": {
+			reason: "The external resource of a deleted read-only managed resource should be left as it is.",
+			w:      &fakeWorkspace{},
+			mg:     object(deleted),
+			op:     observeExternal,
+			want:   want{drifted: corev1.ConditionUnknown},
+		},
+		"ObserveNotFound": {
+			reason: "A missing external resource of a read-only managed resource should not be created.",
+			w:      &fakeWorkspace{},
+			mg:     object(),
+			op:     observeExternal,
+			want:   want{err: errors.New(errReadOnlyNotFound), drifted: corev1.ConditionUnknown},
+		},
+		"ObserveDrift": {
+			reason: "The drift of the external resource of a read-only managed resource should be reported and the resource should be up to date so that the drift is not corrected.",
+			w:      &fakeWorkspace{refresh: exists},
+			change: change,
+			mg:     object(),
+			op:     observeExternal,
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				drifted: corev1.ConditionTrue,
+			},
+		},
+		"ObserveNoDrift": {
+			reason: "A drift that is no longer planned should be cleared.",
+			w:      &fakeWorkspace{refresh: exists, plan: terraform.PlanResult{Exists: true, UpToDate: true}},
+			mg:     object(drifted),
+			op:     observeExternal,
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				drifted: corev1.ConditionFalse,
+			},
+		},
+		"Create": {
+			reason: "The external resource of a read-only managed resource should not be created.",
+			w:      &fakeWorkspace{},
+			mg:     object(),
+			op:     createExternal,
+			want:   want{err: errors.New(errReadOnly), drifted: corev1.ConditionUnknown},
+		},
+		"Update": {
+			reason: "The external resource of a read-only managed resource should not be updated.",
+			w:      &fakeWorkspace{},
+			mg:     object(drifted),
+			op:     updateExternal,
+			want:   want{err: errors.New(errReadOnly), drifted: corev1.ConditionTrue},
+		},
+		"Delete": {
+			reason: "The external resource of a read-only managed resource should not be deleted.",
+			w:      &fakeWorkspace{},
+			mg:     object(deleted),
+			op:     deleteExternal,
+			want:   want{err: errors.New(errReadOnly), drifted: corev1.ConditionUnknown},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			forgetRefresh(tc.mg.GetUID())
+			e := &external{
+				workspace: tc.w,
+				config:    objectConfig(false),
+				recorder:  event.NewNopRecorder(),
+				planned:   &planWorkspace{change: tc.change},
+				readOnly:  true,
+				state:     &workspaceState{},
+			}
+			obs, err := tc.op(e, tc.mg)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\n-want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, tc.w.changed()); diff != "" {
+				t.Errorf("\n%s\n-want external resource changed, +got external resource changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drifted, tc.mg.GetCondition(TypeDrifted).Status); diff != "" {
+				t.Errorf("\n%s\n-want Drifted condition, +got Drifted condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func observeExternal(e *external, mg *v1beta1.Object) (managed.ExternalObservation, error) {
+	return e.Observe(context.Background(), mg)
+}
+
+func createExternal(e *external, mg *v1beta1.Object) (managed.ExternalObservation, error) {
+	_, err := e.Create(context.Background(), mg)
+	return managed.ExternalObservation{}, err
+}
+
+func updateExternal(e *external, mg *v1beta1.Object) (managed.ExternalObservation, error) {
+	_, err := e.Update(context.Background(), mg)
+	return managed.ExternalObservation{}, err
+}
+
+func deleteExternal(e *external, mg *v1beta1.Object) (managed.ExternalObservation, error) {
+	return managed.ExternalObservation{}, e.Delete(context.Background(), mg)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet/pkg/resource"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+	"github.com/upbound/provider-aws/internal/fingerprint"
+)
+
+var (
+	maxRefreshStalenessMu sync.RWMutex
+	maxRefreshStaleness   time.Duration
+)
+
+// SetMaxRefreshStaleness enables skipping the Terraform refresh and plan of
+// the managed resources whose parameters and external resources did not
+// change since they were last found up to date, for the kinds whose external
+// resources have fingerprints. The changes that do not change the
+// fingerprints, such as those of tags, are observed once the given duration
+// elapses since the last full refresh. Refreshes are never skipped if it is
+// zero.
+func SetMaxRefreshStaleness(d time.Duration) {
+	maxRefreshStalenessMu.Lock()
+	defer maxRefreshStalenessMu.Unlock()
+	maxRefreshStaleness = d
+}
+
+func getMaxRefreshStaleness() time.Duration {
+	maxRefreshStalenessMu.RLock()
+	defer maxRefreshStalenessMu.RUnlock()
+	return maxRefreshStaleness
+}
+
+// lastRefresh is the last full refresh of a managed resource whose external
+// resource was found up to date.
+type lastRefresh struct {
+	refreshKey
+	at   time.Time
+	conn managed.ConnectionDetails
+}
+
+// refreshKey tells whether a managed resource or its external resource
+// changed since their last full refresh.
+type refreshKey struct {
+	parameters  string
+	fingerprint string
+}
+
+// refreshes are the last full refreshes by the UIDs of the managed resources.
+var refreshes sync.Map
+
+// awsConfigMaxAge is the maximum age of the cached AWS configurations of the
+// fingerprinters, which bounds how long rotated credentials of an unchanged
+// ProviderConfig take to be used.
+const awsConfigMaxAge = 10 * time.Minute
+
+// fingerprintConfigs are the AWS configurations the fingerprints are taken
+// with.
+var fingerprintConfigs = &awsConfigCache{get: clients.GetAWSConfig, maxAge: awsConfigMaxAge}
+
+// awsConfigKey identifies the AWS configurations that are the same.
+type awsConfigKey struct {
+	providerConfig string
+	region         string
+}
+
+type cachedAWSConfig struct {
+	resourceVersion string
+	cfg             *aws.Config
+	at              time.Time
+}
+
+// awsConfigCache caches the AWS configurations by the ProviderConfigs and the
+// regions of the managed resources, so that their credentials, such as the
+// sessions of the assumed roles, are reused rather than retrieved on every
+// observation. A configuration is renewed once its ProviderConfig changes or
+// it is older than the maximum age.
+type awsConfigCache struct {
+	get     func(ctx context.Context, c client.Client, mg xpresource.Managed) (*aws.Config, error)
+	maxAge  time.Duration
+	configs sync.Map
+}
+
+// Get returns the AWS configuration of the given managed resource, which
+// references the given ProviderConfig.
+func (c *awsConfigCache) Get(ctx context.Context, kube client.Client, tr resource.Terraformed, pc *v1beta1.ProviderConfig) (*aws.Config, error) {
+	region, err := clients.GetRegion(tr)
+	if pc == nil || err != nil {
+		return c.get(ctx, kube, tr)
+	}
+	key := awsConfigKey{providerConfig: pc.GetName(), region: region}
+	if v, ok := c.configs.Load(key); ok {
+		cached := v.(*cachedAWSConfig)
+		if cached.resourceVersion == pc.GetResourceVersion() && time.Since(cached.at) < c.maxAge {
+			return cached.cfg, nil
+		}
+	}
+	cfg, err := c.get(ctx, kube, tr)
+	if err != nil {
+		return nil, err
+	}
+	c.configs.Store(key, &cachedAWSConfig{resourceVersion: pc.GetResourceVersion(), cfg: cfg, at: time.Now()})
+	return cfg, nil
+}
+
+// fingerprinter returns the function that takes the fingerprints of the
+// external resource of the given managed resource, which references the
+// given ProviderConfig.
+func (c *Connector) fingerprinter(tr resource.Terraformed, pc *v1beta1.ProviderConfig) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		cfg, err := fingerprintConfigs.Get(ctx, c.kube, tr, pc)
+		if err != nil {
+			return "", errors.Wrap(err, errGetAWSConfig)
+		}
+		f, _ := fingerprint.New(c.config.Name, *cfg)
+		return f.Fingerprint(ctx, tr)
+	}
+}
+
+// skipRefresh returns the observation of the last full refresh of the given
+// managed resource if neither its parameters nor its external resource
+// changed since then and it is not older than the maximum staleness.
+// Otherwise, it returns the key the next full refresh is recorded with,
+// which is empty if the full refresh cannot be skipped next time. A
+// fingerprint that cannot be taken only costs a full refresh.
+func (e *external) skipRefresh(ctx context.Context, tr resource.Terraformed, now time.Time) (*managed.ExternalObservation, refreshKey) {
+	if e.fingerprint == nil || meta.WasDeleted(tr) {
+		forgetRefresh(tr.GetUID())
+		return nil, refreshKey{}
+	}
+	params, err := parametersHash(tr)
+	if err != nil {
+		return nil, refreshKey{}
+	}
+	fp, err := e.fingerprint(ctx)
+	if err != nil || fp == "" {
+		return nil, refreshKey{}
+	}
+	key := refreshKey{parameters: params, fingerprint: fp}
+	v, ok := refreshes.Load(tr.GetUID())
+	if !ok {
+		return nil, key
+	}
+	last := v.(*lastRefresh)
+	if last.refreshKey != key || now.Sub(last.at) >= e.maxStaleness || !tr.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()) {
+		return nil, key
+	}
+	return &managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: last.conn,
+	}, key
+}
+
+// recordRefresh records a full refresh of the given managed resource that
+// found its external resource up to date.
+func recordRefresh(uid types.UID, key refreshKey, at time.Time, conn managed.ConnectionDetails) {
+	if key == (refreshKey{}) {
+		return
+	}
+	refreshes.Store(uid, &lastRefresh{refreshKey: key, at: at, conn: conn})
+}
+
+// forgetRefresh forgets the last full refresh of the managed resource with
+// the given UID, so that its next observation is a full refresh.
+func forgetRefresh(uid types.UID) {
+	refreshes.Delete(uid)
+}
+
+// parametersHash returns a hash of the parameters and the external name of
+// the given managed resource.
+func parametersHash(tr resource.Terraformed) (string, error) {
+	params, err := tr.GetParameters()
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(map[string]any{
+		"parameters":   params,
+		"externalName": meta.GetExternalName(tr),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+	apisv1beta1 "github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+func TestSkipRefresh(t *testing.T) {
+	now := time.Unix(3600, 0)
+	str := func(s string) *string { return &s }
+	object := func(key string, opts ...func(o *v1beta1.Object)) *v1beta1.Object {
+		o := &v1beta1.Object{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID("uid"), Annotations: map[string]string{"crossplane.io/external-name": "bucket/" + key}},
+			Spec:       v1beta1.ObjectSpec{ForProvider: v1beta1.ObjectParameters{Bucket: str("bucket"), Key: str(key)}},
+		}
+		o.SetConditions(xpv1.Available())
+		for _, f := range opts {
+			f(o)
+		}
+		return o
+	}
+	fingerprint := func(fp string, err error) func(context.Context) (string, error) {
+		return func(context.Context) (string, error) { return fp, err }
+	}
+	conn := managed.ConnectionDetails{"url": []byte("s3://bucket/key")}
+	params, err := parametersHash(object("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		obs *managed.ExternalObservation
+		key refreshKey
+	}
+	cases := map[string]struct {
+		reason      string
+		last        *lastRefresh
+		fingerprint func(context.Context) (string, error)
+		object      *v1beta1.Object
+		want        want
+	}{
+		"Unchanged": {
+			reason:      "The refresh should be skipped if neither the parameters nor the fingerprint changed.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute), conn: conn},
+			fingerprint: fingerprint("etag", nil),
+			object:      object("key"),
+			want: want{
+				obs: &managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: conn},
+				key: refreshKey{parameters: params, fingerprint: "etag"},
+			},
+		},
+		"NeverRefreshed": {
+			reason:      "The refresh should not be skipped if the resource was never found up to date.",
+			fingerprint: fingerprint("etag", nil),
+			object:      object("key"),
+			want:        want{key: refreshKey{parameters: params, fingerprint: "etag"}},
+		},
+		"ParametersChanged": {
+			reason:      "The refresh should not be skipped if the parameters changed.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			fingerprint: fingerprint("etag", nil),
+			object:      object("other"),
+			want:        want{key: refreshKey{parameters: mustHash(t, object("other")), fingerprint: "etag"}},
+		},
+		"FingerprintChanged": {
+			reason:      "The refresh should not be skipped if the external resource changed.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			fingerprint: fingerprint("other-etag", nil),
+			object:      object("key"),
+			want:        want{key: refreshKey{parameters: params, fingerprint: "other-etag"}},
+		},
+		"Stale": {
+			reason:      "The refresh should not be skipped once the maximum staleness elapsed.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Hour)},
+			fingerprint: fingerprint("etag", nil),
+			object:      object("key"),
+			want:        want{key: refreshKey{parameters: params, fingerprint: "etag"}},
+		},
+		"NotAvailable": {
+			reason:      "The refresh should not be skipped if the resource is not available.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			fingerprint: fingerprint("etag", nil),
+			object:      object("key", func(o *v1beta1.Object) { o.SetConditions(xpv1.Unavailable()) }),
+			want:        want{key: refreshKey{parameters: params, fingerprint: "etag"}},
+		},
+		"NoFingerprint": {
+			reason:      "The refresh should not be skipped nor recorded if the fingerprint cannot be taken.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			fingerprint: fingerprint("", errors.New("boom")),
+			object:      object("key"),
+			want:        want{},
+		},
+		"Deleted": {
+			reason:      "The refresh of a deleted resource should not be skipped.",
+			last:        &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			fingerprint: fingerprint("etag", nil),
+			object:      object("key", func(o *v1beta1.Object) { o.SetDeletionTimestamp(&metav1.Time{Time: now}) }),
+			want:        want{},
+		},
+		"Unsupported": {
+			reason: "The refresh of a kind without fingerprints should not be skipped.",
+			last:   &lastRefresh{refreshKey: refreshKey{parameters: params, fingerprint: "etag"}, at: now.Add(-time.Minute)},
+			object: object("key"),
+			want:   want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			forgetRefresh(tc.object.GetUID())
+			if tc.last != nil {
+				refreshes.Store(tc.object.GetUID(), tc.last)
+			}
+			e := &external{fingerprint: tc.fingerprint, maxStaleness: time.Hour}
+			obs, key := e.skipRefresh(context.Background(), tc.object, now)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\nskipRefresh(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, key, cmp.AllowUnexported(refreshKey{})); diff != "" {
+				t.Errorf("\n%s\nskipRefresh(...): -want key, +got key:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func mustHash(t *testing.T, o *v1beta1.Object) string {
+	t.Helper()
+	h, err := parametersHash(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestAWSConfigCacheGet(t *testing.T) {
+	str := func(s string) *string { return &s }
+	object := &v1beta1.Object{Spec: v1beta1.ObjectSpec{ForProvider: v1beta1.ObjectParameters{Region: str("us-east-1")}}}
+	pc := func(name, resourceVersion string) *apisv1beta1.ProviderConfig {
+		return &apisv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion}}
+	}
+	cached := &aws.Config{Region: "us-east-1"}
+	retrieved := &aws.Config{Region: "us-east-1", RetryMaxAttempts: 1}
+
+	type want struct {
+		cfg *aws.Config
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		cached *cachedAWSConfig
+		pc     *apisv1beta1.ProviderConfig
+		err    error
+		want   want
+	}{
+		"Cached": {
+			reason: "The configuration of an unchanged ProviderConfig and region should be reused.",
+			cached: &cachedAWSConfig{resourceVersion: "1", cfg: cached, at: time.Now()},
+			pc:     pc("default", "1"),
+			want:   want{cfg: cached},
+		},
+		"NotCached": {
+			reason: "The configuration should be retrieved if it was not yet.",
+			pc:     pc("default", "1"),
+			want:   want{cfg: retrieved},
+		},
+		"OtherProviderConfig": {
+			reason: "The configuration of another ProviderConfig should not be reused.",
+			cached: &cachedAWSConfig{resourceVersion: "1", cfg: cached, at: time.Now()},
+			pc:     pc("other", "1"),
+			want:   want{cfg: retrieved},
+		},
+		"ProviderConfigChanged": {
+			reason: "The configuration should be retrieved again once its ProviderConfig changed.",
+			cached: &cachedAWSConfig{resourceVersion: "1", cfg: cached, at: time.Now()},
+			pc:     pc("default", "2"),
+			want:   want{cfg: retrieved},
+		},
+		"Expired": {
+			reason: "The configuration should be retrieved again once it is older than the maximum age.",
+			cached: &cachedAWSConfig{resourceVersion: "1", cfg: cached, at: time.Now().Add(-time.Hour)},
+			pc:     pc("default", "1"),
+			want:   want{cfg: retrieved},
+		},
+		"NoProviderConfig": {
+			reason: "The configuration should not be cached without a ProviderConfig.",
+			cached: &cachedAWSConfig{resourceVersion: "1", cfg: cached, at: time.Now()},
+			want:   want{cfg: retrieved},
+		},
+		"GetError": {
+			reason: "An error retrieving the configuration should be returned.",
+			pc:     pc("default", "1"),
+			err:    errors.New("boom"),
+			want:   want{err: errors.New("boom")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &awsConfigCache{
+				get: func(context.Context, client.Client, xpresource.Managed) (*aws.Config, error) {
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return retrieved, nil
+				},
+				maxAge: time.Minute,
+			}
+			if tc.cached != nil {
+				c.configs.Store(awsConfigKey{providerConfig: "default", region: "us-east-1"}, tc.cached)
+			}
+			cfg, err := c.Get(context.Background(), &test.MockClient{}, object, tc.pc)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.cfg != cfg {
+				t.Errorf("\n%s\nGet(...): want configuration %v, got %v", tc.reason, tc.want.cfg, cfg)
+			}
+		})
+	}
+}
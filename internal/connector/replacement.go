@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/provider-aws/apis/v1beta1"
+)
+
+const (
+	// AnnotationKeyApproveReplacement is the annotation that approves the
+	// replacement of a stateful external resource. Its value must be the
+	// generation of the managed resource whose changes force the
+	// replacement, so that an approval does not carry over to later changes.
+	AnnotationKeyApproveReplacement = "aws.upbound.io/approve-replacement"
+
+	// TypeReplacementBlocked is the type of the condition that reports
+	// whether the changes of a managed resource are not applied because they
+	// force the replacement of its stateful external resource.
+	TypeReplacementBlocked xpv1.ConditionType = "ReplacementBlocked"
+
+	// ReasonApprovalRequired is the reason of the ReplacementBlocked
+	// condition when the replacement is not approved.
+	ReasonApprovalRequired xpv1.ConditionReason = "ApprovalRequired"
+	// ReasonNoReplacementBlocked is the reason of the ReplacementBlocked
+	// condition when no replacement is blocked.
+	ReasonNoReplacementBlocked xpv1.ConditionReason = "NoReplacementBlocked"
+
+	reasonReplacementBlocked event.Reason = "ReplacementBlocked"
+)
+
+// replacementApproved returns true if the replacement of the external
+// resource of the given managed resource is approved for its current
+// generation.
+func replacementApproved(mg xpresource.Managed) bool {
+	return mg.GetAnnotations()[AnnotationKeyApproveReplacement] == strconv.FormatInt(mg.GetGeneration(), 10)
+}
+
+// replacementBlocked returns a condition that indicates the changes of the
+// given generation are not applied because they force a replacement.
+func replacementBlocked(s *v1beta1.ChangeSummary) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReplacementBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApprovalRequired,
+		Message: fmt.Sprintf("%s. Set the %s annotation to %d to approve the replacement.",
+			describe(s), AnnotationKeyApproveReplacement, s.Generation),
+	}
+}
+
+// noReplacementBlocked returns a condition that indicates no changes are
+// blocked because they force a replacement.
+func noReplacementBlocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReplacementBlocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoReplacementBlocked,
+	}
+}
+
+// blockReplacement reports that the changes in the given summary are blocked
+// on the given managed resource. An event is only recorded when the blocked
+// changes are different from the ones already reported.
+func blockReplacement(mg xpresource.Managed, s *v1beta1.ChangeSummary, r event.Recorder) {
+	c := replacementBlocked(s)
+	if last := mg.GetCondition(TypeReplacementBlocked); last.Status == corev1.ConditionTrue && last.Message == c.Message {
+		return
+	}
+	r.Event(mg, event.Warning(reasonReplacementBlocked, errors.New(c.Message)))
+	mg.SetConditions(c)
+}
+
+// unblockReplacement marks the ReplacementBlocked condition of the given
+// managed resource as false if it was reported.
+func unblockReplacement(mg xpresource.Managed) {
+	if c := mg.GetCondition(TypeReplacementBlocked); c.Status == corev1.ConditionTrue {
+		mg.SetConditions(noReplacementBlocked())
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReplacementApproved(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        bool
+	}{
+		"NotAnnotated": {
+			reason: "A replacement should not be approved without the annotation.",
+			want:   false,
+		},
+		"CurrentGeneration": {
+			reason:      "A replacement should be approved for the annotated generation.",
+			annotations: map[string]string{AnnotationKeyApproveReplacement: "3"},
+			want:        true,
+		},
+		"PreviousGeneration": {
+			reason:      "An approval of a previous generation should not carry over.",
+			annotations: map[string]string{AnnotationKeyApproveReplacement: "2"},
+			want:        false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			mg.SetGeneration(3)
+			if diff := cmp.Diff(tc.want, replacementApproved(mg)); diff != "" {
+				t.Errorf("\n%s\nreplacementApproved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// apiSecretClient is a resource.SecretClient that reads the referenced
+// Kubernetes secrets from the API server.
+type apiSecretClient struct {
+	kube client.Client
+}
+
+// GetSecretData gets and returns data for the referenced secret.
+func (a *apiSecretClient) GetSecretData(ctx context.Context, ref *xpv1.SecretReference) (map[string][]byte, error) {
+	secret := &v1.Secret{}
+	if err := a.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+// GetSecretValue gets and returns value for key of the referenced secret.
+func (a *apiSecretClient) GetSecretValue(ctx context.Context, sel xpv1.SecretKeySelector) ([]byte, error) {
+	d, err := a.GetSecretData(ctx, &sel.SecretReference)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get secret data")
+	}
+	return d[sel.Key], err
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/internal/encryption"
+	"github.com/upbound/provider-aws/internal/persistence"
+)
+
+const (
+	errReadStateFiles  = "cannot read the state files of the workspace"
+	errWriteStateFiles = "cannot write the state files of the workspace"
+	errLoadState       = "cannot load the persisted state of the workspace"
+	errSaveState       = "cannot persist the state of the workspace"
+	errDeleteState     = "cannot delete the persisted state of the workspace"
+)
+
+var (
+	stateStoreMu sync.RWMutex
+	stateStore   persistence.Store
+	stateMaxAge  time.Duration
+
+	// savedDigests are the digests of the last persisted state files by the
+	// UIDs of their managed resources, so that unchanged states are not
+	// persisted again.
+	savedDigests sync.Map
+)
+
+// SetStateStore sets the Store that persists the Terraform state of the
+// workspaces outside of the provider pod. The persisted state of a workspace
+// is reused when the workspace has no state, such as after the provider
+// restarts, if it was saved for the same external resource at most maxAge
+// before. It is not persisted if no Store is set.
+func SetStateStore(s persistence.Store, maxAge time.Duration) {
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	stateStore, stateMaxAge = s, maxAge
+}
+
+// workspaceState keeps the state files of a workspace at rest. They are
+// encrypted with the state Encrypter and persisted with the state Store, if
+// set.
+type workspaceState struct {
+	enc          *encryption.Encrypter
+	store        persistence.Store
+	maxAge       time.Duration
+	dir          string
+	uid          types.UID
+	externalName string
+}
+
+func newWorkspaceState(mg xpresource.Managed, dir string) *workspaceState {
+	stateStoreMu.RLock()
+	defer stateStoreMu.RUnlock()
+	return &workspaceState{
+		enc:          getStateEncrypter(),
+		store:        stateStore,
+		maxAge:       stateMaxAge,
+		dir:          dir,
+		uid:          mg.GetUID(),
+		externalName: meta.GetExternalName(mg),
+	}
+}
+
+// active returns true if the state files are encrypted or persisted.
+func (s *workspaceState) active() bool {
+	return s.enc != nil || s.store != nil
+}
+
+// open makes the state files available to Terraform. They are restored from
+// the state Store if the workspace has none and decrypted.
+func (s *workspaceState) open(ctx context.Context) error {
+	restored, err := s.restore(ctx)
+	if err != nil {
+		return err
+	}
+	if err := unsealState(ctx, s.enc, s.dir); err != nil {
+		return err
+	}
+	if !restored {
+		return nil
+	}
+	files, err := s.files(stateFiles)
+	if err != nil {
+		return err
+	}
+	savedDigests.Store(s.uid, persistence.Digest(files))
+	return nil
+}
+
+// close encrypts the state files and persists them unless they are unchanged
+// since they were last persisted. The plaintext state files are compared,
+// since the encrypted ones differ every time they are encrypted.
+func (s *workspaceState) close(ctx context.Context) error {
+	files, err := s.files(stateFiles)
+	if err != nil {
+		return err
+	}
+	if err := sealState(ctx, s.enc, s.dir); err != nil {
+		return err
+	}
+	return s.persist(ctx, persistence.Digest(files))
+}
+
+// removeState deletes the persisted state files of the workspace of the
+// managed resource with the given UID from the given Store, if set.
+func removeState(ctx context.Context, store persistence.Store, uid types.UID) error {
+	savedDigests.Delete(uid)
+	if store == nil {
+		return nil
+	}
+	return errors.Wrap(store.Delete(ctx, uid), errDeleteState)
+}
+
+// Finalizer deletes the persisted state of the workspace of a managed
+// resource before its finalizer is removed. The finalizer is removed on
+// every path a managed resource is deleted by, whether its external
+// resource was deleted, orphaned or never adopted, so that no persisted
+// state outlives its managed resource.
+type Finalizer struct {
+	xpresource.Finalizer
+}
+
+// NewFinalizer returns a Finalizer that wraps the given finalizer.
+func NewFinalizer(f xpresource.Finalizer) *Finalizer {
+	return &Finalizer{Finalizer: f}
+}
+
+// RemoveFinalizer forgets the workspace of the given managed resource and
+// deletes its persisted state before it removes the finalizer with the
+// wrapped finalizer.
+func (f *Finalizer) RemoveFinalizer(ctx context.Context, obj xpresource.Object) error {
+	forgetWorkspace(obj.GetUID())
+	stateStoreMu.RLock()
+	store := stateStore
+	stateStoreMu.RUnlock()
+	if err := removeState(ctx, store, obj.GetUID()); err != nil {
+		return err
+	}
+	return f.Finalizer.RemoveFinalizer(ctx, obj)
+}
+
+// restore restores the persisted state files into the workspace if it has
+// none, and returns true if it did.
+func (s *workspaceState) restore(ctx context.Context) (bool, error) {
+	if s.store == nil {
+		return false, nil
+	}
+	files, err := s.files(stateFileNames())
+	if err != nil || len(files) > 0 {
+		return false, err
+	}
+	st, err := s.store.Load(ctx, s.uid)
+	if err != nil {
+		return false, errors.Wrap(err, errLoadState)
+	}
+	// The external resource is still refreshed, so a stale state is only
+	// rejected if it might be of another external resource or is too old
+	// to be worth reusing.
+	if !persistence.Fresh(st, s.externalName, s.maxAge, time.Now()) {
+		return false, nil
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return false, errors.Wrap(err, errWriteStateFiles)
+	}
+	for _, name := range stateFileNames() {
+		data, ok := st.Files[name]
+		if !ok {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(s.dir, name), data, 0600); err != nil {
+			return false, errors.Wrap(err, errWriteStateFiles)
+		}
+	}
+	return true, nil
+}
+
+// persist saves the state files of the workspace, whether they are encrypted
+// or not, unless the given digest of their plaintext is the one of the state
+// files last persisted.
+func (s *workspaceState) persist(ctx context.Context, d string) error {
+	if s.store == nil {
+		return nil
+	}
+	files, err := s.files(stateFileNames())
+	if err != nil || len(files) == 0 {
+		return err
+	}
+	if last, ok := savedDigests.Load(s.uid); ok && last.(string) == d {
+		return nil
+	}
+	if err := s.store.Save(ctx, s.uid, &persistence.State{
+		ExternalName: s.externalName,
+		SavedAt:      time.Now(),
+		Files:        files,
+	}); err != nil {
+		return errors.Wrap(err, errSaveState)
+	}
+	savedDigests.Store(s.uid, d)
+	return nil
+}
+
+// files returns the contents of the given state files in the workspace by
+// their names.
+func (s *workspaceState) files(names []string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadStateFiles)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// stateFileNames returns the names of the state files of a workspace,
+// encrypted or not.
+func stateFileNames() []string {
+	names := make([]string, 0, 2*len(stateFiles))
+	for _, f := range stateFiles {
+		names = append(names, f, f+encryption.Extension)
+	}
+	return names
+}
+
+// stateWorkspace opens the state of the underlying workspace only for the
+// duration of its Terraform operations. The state is left open while an
+// async operation runs and closed once it has finished.
+type stateWorkspace struct {
+	tjcontroller.Workspace
+	state   *workspaceState
+	running func() bool
+}
+
+// opened runs the given function with the state opened.
+func (w *stateWorkspace) opened(ctx context.Context, fn func() error) error {
+	if err := w.state.open(ctx); err != nil {
+		return err
+	}
+	err := fn()
+	if w.running() {
+		return err
+	}
+	if cErr := w.state.close(ctx); cErr != nil && err == nil {
+		err = cErr
+	}
+	return err
+}
+
+func (w *stateWorkspace) async(callback terraform.CallbackFn) terraform.CallbackFn {
+	return func(err error, ctx context.Context) error {
+		if cErr := w.state.close(ctx); cErr != nil && err == nil {
+			err = cErr
+		}
+		return callback(err, ctx)
+	}
+}
+
+func (w *stateWorkspace) ApplyAsync(callback terraform.CallbackFn) error {
+	return w.opened(context.Background(), func() error {
+		return w.Workspace.ApplyAsync(w.async(callback))
+	})
+}
+
+func (w *stateWorkspace) Apply(ctx context.Context) (res terraform.ApplyResult, err error) {
+	err = w.opened(ctx, func() error {
+		res, err = w.Workspace.Apply(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (w *stateWorkspace) DestroyAsync(callback terraform.CallbackFn) error {
+	return w.opened(context.Background(), func() error {
+		return w.Workspace.DestroyAsync(w.async(callback))
+	})
+}
+
+func (w *stateWorkspace) Destroy(ctx context.Context) error {
+	return w.opened(ctx, func() error {
+		return w.Workspace.Destroy(ctx)
+	})
+}
+
+func (w *stateWorkspace) Refresh(ctx context.Context) (res terraform.RefreshResult, err error) {
+	err = w.opened(ctx, func() error {
+		res, err = w.Workspace.Refresh(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (w *stateWorkspace) Plan(ctx context.Context) (res terraform.PlanResult, err error) {
+	err = w.opened(ctx, func() error {
+		res, err = w.Workspace.Plan(ctx)
+		return err
+	})
+	return res, err
+}
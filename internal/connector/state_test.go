@@ -0,0 +1,221 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/provider-aws/internal/encryption"
+	"github.com/upbound/provider-aws/internal/persistence"
+)
+
+// countingStore counts the states saved in the underlying store.
+type countingStore struct {
+	persistence.Store
+	saves int
+}
+
+func (s *countingStore) Save(ctx context.Context, uid types.UID, st *persistence.State) error {
+	s.saves++
+	return s.Store.Save(ctx, uid, st)
+}
+
+func TestWorkspaceStateRestore(t *testing.T) {
+	ctx := context.Background()
+	uid := types.UID("0c3bd1a4-8a1e-4b8e-a0a4-3c0e5e1f9d2a")
+	saved := []byte(`{"id":"db-1"}`)
+	cases := map[string]struct {
+		reason       string
+		externalName string
+		local        []byte
+		want         []byte
+	}{
+		"Restored": {
+			reason:       "The persisted state should be restored into a workspace without state.",
+			externalName: "db-1",
+			want:         saved,
+		},
+		"OtherExternalResource": {
+			reason:       "The persisted state of another external resource should not be restored.",
+			externalName: "db-2",
+		},
+		"LocalState": {
+			reason:       "The state of the workspace should not be overwritten.",
+			externalName: "db-1",
+			local:        []byte(`{"id":"db-1","newer":true}`),
+			want:         []byte(`{"id":"db-1","newer":true}`),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			store, err := persistence.NewDirectoryStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Save(ctx, uid, &persistence.State{
+				ExternalName: "db-1",
+				SavedAt:      time.Now(),
+				Files:        map[string][]byte{"terraform.tfstate": saved},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			dir := filepath.Join(t.TempDir(), string(uid))
+			if tc.local != nil {
+				if err := os.MkdirAll(dir, 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), tc.local, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := &workspaceState{store: store, dir: dir, uid: uid, externalName: tc.externalName}
+			if err := s.open(ctx); err != nil {
+				t.Fatalf("\n%s\nopen(...): %v", tc.reason, err)
+			}
+			got, _ := os.ReadFile(filepath.Join(dir, "terraform.tfstate"))
+			if diff := cmp.Diff(string(tc.want), string(got)); diff != "" {
+				t.Errorf("\n%s\nopen(...): -want state, +got state:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceStatePersist(t *testing.T) {
+	ctx := context.Background()
+	kw, err := encryption.NewSecretKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		reason string
+		enc    *encryption.Encrypter
+	}{
+		"Plaintext": {
+			reason: "An unchanged state should not be saved again.",
+		},
+		"Encrypted": {
+			reason: "An unchanged state should not be saved again even though it is encrypted differently every time.",
+			enc:    encryption.New(kw),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			uid := types.UID("7f0e2b8c-1d4a-4f6e-9b3c-2a5d8e7f6c1b")
+			ds, err := persistence.NewDirectoryStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			store := &countingStore{Store: ds}
+			dir := t.TempDir()
+			s := &workspaceState{enc: tc.enc, store: store, dir: dir, uid: uid, externalName: "db-1"}
+			t.Cleanup(func() { savedDigests.Delete(uid) })
+
+			for i, state := range []string{`{"id":"db-1"}`, `{"id":"db-1"}`, `{"id":"db-1","port":5432}`} {
+				if err := s.open(ctx); err != nil {
+					t.Fatalf("\n%s\nopen(...) %d: %v", tc.reason, i, err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), []byte(state), 0600); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.close(ctx); err != nil {
+					t.Fatalf("\n%s\nclose(...) %d: %v", tc.reason, i, err)
+				}
+			}
+			if diff := cmp.Diff(2, store.saves); diff != "" {
+				t.Errorf("\n%s\nclose(...): -want saves, +got saves:\n%s", tc.reason, diff)
+			}
+
+			// The state restored into the emptied workspace, such as after
+			// the provider restarts, is the one last persisted, which is not
+			// saved again either.
+			savedDigests.Delete(uid)
+			if err := os.RemoveAll(dir); err != nil {
+				t.Fatal(err)
+			}
+			restored := &workspaceState{enc: tc.enc, store: store, dir: dir, uid: uid, externalName: "db-1"}
+			if err := restored.open(ctx); err != nil {
+				t.Fatalf("\n%s\nopen(...): %v", tc.reason, err)
+			}
+			if err := restored.close(ctx); err != nil {
+				t.Fatalf("\n%s\nclose(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(2, store.saves); diff != "" {
+				t.Errorf("\n%s\nclose(...): a restored state should not be saved again: -want saves, +got saves:\n%s", tc.reason, diff)
+			}
+
+			if err := removeState(ctx, store, uid); err != nil {
+				t.Fatalf("\n%s\nremoveState(...): %v", tc.reason, err)
+			}
+			if got, err := ds.Load(ctx, uid); err != nil || got != nil {
+				t.Errorf("\n%s\nremoveState(...): want no persisted state, got %v, %v", tc.reason, got, err)
+			}
+		})
+	}
+}
+
+func TestFinalizerRemoveFinalizer(t *testing.T) {
+	ctx := context.Background()
+	uid := types.UID("5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e")
+	errBoom := errors.New("boom")
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   error
+	}{
+		"Removed": {
+			reason: "The persisted state should be deleted before the finalizer is removed.",
+		},
+		"RemoveError": {
+			reason: "The error of the wrapped finalizer should be returned.",
+			err:    errBoom,
+			want:   errBoom,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ds, err := persistence.NewDirectoryStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ds.Save(ctx, uid, &persistence.State{ExternalName: "db-1"}); err != nil {
+				t.Fatal(err)
+			}
+			SetStateStore(ds, 0)
+			t.Cleanup(func() { SetStateStore(nil, 0) })
+			deleted := false
+			runningOperations.Store(uid, func() bool { return true })
+
+			f := NewFinalizer(xpresource.FinalizerFns{RemoveFinalizerFn: func(ctx context.Context, obj xpresource.Object) error {
+				// The state is deleted before the finalizer is removed.
+				st, err := ds.Load(ctx, uid)
+				deleted = err == nil && st == nil
+				return tc.err
+			}})
+			err = f.RemoveFinalizer(ctx, &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRemoveFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if !deleted {
+				t.Errorf("\n%s\nRemoveFinalizer(...): the persisted state was not deleted before the finalizer was removed", tc.reason)
+			}
+			if WorkspaceInUse(uid) {
+				t.Errorf("\n%s\nRemoveFinalizer(...): the workspace was not forgotten", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	fieldTimeouts = "spec.forProvider.timeouts[0]"
+
+	errConvertUnstructured = "cannot convert the managed resource to unstructured"
+	errParseTimeout        = "cannot parse the %s timeout"
+)
+
+// withOperationTimeouts returns the given resource configuration with the
+// operation timeouts set in the timeouts spec field of the given managed
+// resource, if any.
+func withOperationTimeouts(mg xpresource.Managed, cfg *config.Resource) (*config.Resource, error) {
+	if cfg.TerraformResource == nil || cfg.TerraformResource.Schema["timeouts"] == nil {
+		return cfg, nil
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertUnstructured)
+	}
+	p := fieldpath.Pave(u)
+	if _, err := p.GetValue(fieldTimeouts); err != nil {
+		return cfg, nil
+	}
+	out := *cfg
+	for op, t := range map[string]*time.Duration{
+		"create": &out.OperationTimeouts.Create,
+		"update": &out.OperationTimeouts.Update,
+		"delete": &out.OperationTimeouts.Delete,
+	} {
+		v, err := p.GetString(fieldTimeouts + "." + op)
+		if err != nil || v == "" {
+			continue
+		}
+		if *t, err = time.ParseDuration(v); err != nil {
+			return nil, errors.Wrapf(err, errParseTimeout, op)
+		}
+	}
+	return &out, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+
+	"github.com/upbound/provider-aws/apis/rds/v1beta1"
+)
+
+func TestWithOperationTimeouts(t *testing.T) {
+	str := func(s string) *string { return &s }
+	cluster := func(timeouts ...v1beta1.ClusterTimeoutsParameters) *v1beta1.Cluster {
+		return &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Timeouts: timeouts}}}
+	}
+	cfg := func(timeouts bool) *config.Resource {
+		r := &config.Resource{
+			Name:              "aws_rds_cluster",
+			TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{}},
+			OperationTimeouts: config.OperationTimeouts{Create: time.Hour, Update: time.Hour, Delete: time.Hour},
+		}
+		if timeouts {
+			r.TerraformResource.Schema["timeouts"] = &schema.Schema{Type: schema.TypeList}
+		}
+		return r
+	}
+
+	type want struct {
+		timeouts config.OperationTimeouts
+		err      error
+	}
+	cases := map[string]struct {
+		reason   string
+		timeouts bool
+		mg       *v1beta1.Cluster
+		want     want
+	}{
+		"NoTimeoutsField": {
+			reason: "The operation timeouts of a kind without the timeouts field should not be changed.",
+			mg:     cluster(v1beta1.ClusterTimeoutsParameters{Create: str("2h")}),
+			want:   want{timeouts: config.OperationTimeouts{Create: time.Hour, Update: time.Hour, Delete: time.Hour}},
+		},
+		"NoTimeouts": {
+			reason:   "The operation timeouts should not be changed if the timeouts field is not set.",
+			timeouts: true,
+			mg:       cluster(),
+			want:     want{timeouts: config.OperationTimeouts{Create: time.Hour, Update: time.Hour, Delete: time.Hour}},
+		},
+		"Timeouts": {
+			reason:   "The operation timeouts set in the timeouts field should override the ones of the configuration.",
+			timeouts: true,
+			mg:       cluster(v1beta1.ClusterTimeoutsParameters{Create: str("2h"), Delete: str("30m")}),
+			want:     want{timeouts: config.OperationTimeouts{Create: 2 * time.Hour, Update: time.Hour, Delete: 30 * time.Minute}},
+		},
+		"InvalidTimeout": {
+			reason:   "A timeout that cannot be parsed should be an error.",
+			timeouts: true,
+			mg:       cluster(v1beta1.ClusterTimeoutsParameters{Update: str("soon")}),
+			want:     want{err: errors.Wrapf(errors.New(`time: invalid duration "soon"`), errParseTimeout, "update")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := cfg(tc.timeouts)
+			out, err := withOperationTimeouts(tc.mg, in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nwithOperationTimeouts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.timeouts, out.OperationTimeouts); diff != "" {
+				t.Errorf("\n%s\nwithOperationTimeouts(...): -want timeouts, +got timeouts:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(config.OperationTimeouts{Create: time.Hour, Update: time.Hour, Delete: time.Hour}, in.OperationTimeouts); diff != "" {
+				t.Errorf("\n%s\nwithOperationTimeouts(...): the given configuration was changed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
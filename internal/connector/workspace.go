@@ -0,0 +1,180 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/config"
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/terraform"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/tracing"
+)
+
+// workspaceDir returns the directory of the Terraform workspace of the given
+// resource, which is where the upjet workspace store creates it.
+func workspaceDir(mg metav1.Object) string {
+	return filepath.Join(os.TempDir(), string(mg.GetUID()))
+}
+
+const errTouchWorkspace = "cannot record the use of the workspace"
+
+// runningOperations are the functions that report whether an async operation
+// is running in the workspaces by the UIDs of their managed resources.
+var runningOperations sync.Map
+
+// syncOperations are the numbers of the sync operations, such as refreshes
+// and plans, that are running in the workspaces by the UIDs of their managed
+// resources.
+var syncOperations = struct {
+	sync.Mutex
+	running map[types.UID]int
+}{running: map[types.UID]int{}}
+
+// useWorkspace records that a sync operation is running in the workspace of
+// the managed resource with the given UID until the returned function is
+// called.
+func useWorkspace(uid types.UID) func() {
+	syncOperations.Lock()
+	syncOperations.running[uid]++
+	syncOperations.Unlock()
+	return func() {
+		syncOperations.Lock()
+		defer syncOperations.Unlock()
+		if syncOperations.running[uid]--; syncOperations.running[uid] <= 0 {
+			delete(syncOperations.running, uid)
+		}
+	}
+}
+
+// touchWorkspace records that the workspace in the given directory is used
+// by updating the modification time of the directory, which the workspace
+// garbage collector evicts the least recently used workspaces by.
+func touchWorkspace(dir string, uid types.UID, running func() bool) error {
+	runningOperations.Store(uid, running)
+	now := time.Now()
+	return errors.Wrap(os.Chtimes(dir, now, now), errTouchWorkspace)
+}
+
+// forgetWorkspace forgets the workspace of the managed resource with the given
+// UID once its external resource no longer exists.
+func forgetWorkspace(uid types.UID) {
+	runningOperations.Delete(uid)
+}
+
+// WorkspaceInUse returns true if a sync or async operation is running in the
+// workspace of the managed resource with the given UID.
+func WorkspaceInUse(uid types.UID) bool {
+	syncOperations.Lock()
+	n := syncOperations.running[uid]
+	syncOperations.Unlock()
+	if n > 0 {
+		return true
+	}
+	fn, ok := runningOperations.Load(uid)
+	return ok && fn.(func() bool)()
+}
+
+// instrumentedWorkspace records metrics and opens spans for the Terraform
+// operations run in the underlying workspace.
+type instrumentedWorkspace struct {
+	tjcontroller.Workspace
+	uid   types.UID
+	group string
+	kind  string
+
+	// parent is the span the spans of the async operations, which outlive
+	// the reconcile that started them, are attached to.
+	parent trace.SpanContext
+}
+
+func newInstrumentedWorkspace(ctx context.Context, w tjcontroller.Workspace, uid types.UID, cfg *config.Resource) *instrumentedWorkspace {
+	return &instrumentedWorkspace{
+		Workspace: w,
+		uid:       uid,
+		group:     cfg.ShortGroup,
+		kind:      cfg.Kind,
+		parent:    trace.SpanContextFromContext(ctx),
+	}
+}
+
+func (w *instrumentedWorkspace) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "terraform "+operation, trace.WithAttributes(
+		tracing.AttrGroup.String(w.group),
+		tracing.AttrKind.String(w.kind),
+	))
+}
+
+func (w *instrumentedWorkspace) async(operation string, callback terraform.CallbackFn) terraform.CallbackFn {
+	start := time.Now()
+	_, span := w.start(trace.ContextWithSpanContext(context.Background(), w.parent), operation)
+	return func(err error, ctx context.Context) error {
+		metrics.ObserveTerraformOperation(w.group, w.kind, operation, start, err)
+		tracing.End(span, err)
+		return callback(err, ctx)
+	}
+}
+
+func (w *instrumentedWorkspace) ApplyAsync(callback terraform.CallbackFn) error {
+	return w.Workspace.ApplyAsync(w.async(metrics.OperationApply, callback))
+}
+
+func (w *instrumentedWorkspace) Apply(ctx context.Context) (terraform.ApplyResult, error) {
+	defer useWorkspace(w.uid)()
+	start := time.Now()
+	ctx, span := w.start(ctx, metrics.OperationApply)
+	res, err := w.Workspace.Apply(ctx)
+	metrics.ObserveTerraformOperation(w.group, w.kind, metrics.OperationApply, start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (w *instrumentedWorkspace) DestroyAsync(callback terraform.CallbackFn) error {
+	return w.Workspace.DestroyAsync(w.async(metrics.OperationDestroy, callback))
+}
+
+func (w *instrumentedWorkspace) Destroy(ctx context.Context) error {
+	defer useWorkspace(w.uid)()
+	start := time.Now()
+	ctx, span := w.start(ctx, metrics.OperationDestroy)
+	err := w.Workspace.Destroy(ctx)
+	metrics.ObserveTerraformOperation(w.group, w.kind, metrics.OperationDestroy, start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (w *instrumentedWorkspace) Refresh(ctx context.Context) (terraform.RefreshResult, error) {
+	defer useWorkspace(w.uid)()
+	start := time.Now()
+	ctx, span := w.start(ctx, metrics.OperationRefresh)
+	res, err := w.Workspace.Refresh(ctx)
+	// A refresh isn't run while an async operation is in progress.
+	if !res.IsApplying && !res.IsDestroying {
+		metrics.ObserveTerraformOperation(w.group, w.kind, metrics.OperationRefresh, start, err)
+	}
+	tracing.End(span, err)
+	return res, err
+}
+
+func (w *instrumentedWorkspace) Plan(ctx context.Context) (terraform.PlanResult, error) {
+	defer useWorkspace(w.uid)()
+	start := time.Now()
+	ctx, span := w.start(ctx, metrics.OperationPlan)
+	res, err := w.Workspace.Plan(ctx)
+	metrics.ObserveTerraformOperation(w.group, w.kind, metrics.OperationPlan, start, err)
+	tracing.End(span, err)
+	return res, err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/terraform"
+)
+
+// inUseWorkspace is a workspace that records whether it is reported to be in
+// use while its sync operations run.
+type inUseWorkspace struct {
+	fakeWorkspace
+	uid   types.UID
+	inUse bool
+}
+
+func (w *inUseWorkspace) Apply(ctx context.Context) (terraform.ApplyResult, error) {
+	w.inUse = WorkspaceInUse(w.uid)
+	return w.fakeWorkspace.Apply(ctx)
+}
+
+func (w *inUseWorkspace) Destroy(ctx context.Context) error {
+	w.inUse = WorkspaceInUse(w.uid)
+	return w.fakeWorkspace.Destroy(ctx)
+}
+
+func (w *inUseWorkspace) Refresh(ctx context.Context) (terraform.RefreshResult, error) {
+	w.inUse = WorkspaceInUse(w.uid)
+	return w.fakeWorkspace.Refresh(ctx)
+}
+
+func (w *inUseWorkspace) Plan(ctx context.Context) (terraform.PlanResult, error) {
+	w.inUse = WorkspaceInUse(w.uid)
+	return w.fakeWorkspace.Plan(ctx)
+}
+
+func TestWorkspaceInUse(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		run    func(ctx context.Context, w *instrumentedWorkspace) error
+	}{
+		opApply: {
+			reason: "The workspace should be in use while it is applied.",
+			run: func(ctx context.Context, w *instrumentedWorkspace) error {
+				_, err := w.Apply(ctx)
+				return err
+			},
+		},
+		opDestroy: {
+			reason: "The workspace should be in use while it is destroyed.",
+			run: func(ctx context.Context, w *instrumentedWorkspace) error {
+				return w.Destroy(ctx)
+			},
+		},
+		opRefresh: {
+			reason: "The workspace should be in use while it is refreshed.",
+			run: func(ctx context.Context, w *instrumentedWorkspace) error {
+				_, err := w.Refresh(ctx)
+				return err
+			},
+		},
+		opPlan: {
+			reason: "The workspace should be in use while it is planned.",
+			run: func(ctx context.Context, w *instrumentedWorkspace) error {
+				_, err := w.Plan(ctx)
+				return err
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			uid := types.UID("0c2a9d1e-5b0f-4a7e-9d1c-2f3b4a5c6d7e")
+			inner := &inUseWorkspace{uid: uid}
+			w := newInstrumentedWorkspace(ctx, inner, uid, objectConfig(false))
+			if err := tc.run(ctx, w); err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(true, inner.inUse); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want in use, +got in use:\n%s", tc.reason, name, diff)
+			}
+			if WorkspaceInUse(uid) {
+				t.Errorf("\n%s\n%s(...): the workspace should not be in use after the operation", tc.reason, name)
+			}
+		})
+	}
+}
@@ -7,8 +7,6 @@ Copyright 2022 Upbound Inc.
 package certificatevalidation
 
 import (
-	"time"
-
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
@@ -19,6 +17,12 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	v1beta1 "github.com/upbound/provider-aws/apis/acm/v1beta1"
+	"github.com/upbound/provider-aws/config"
+	"github.com/upbound/provider-aws/internal/connector"
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/poll"
+	"github.com/upbound/provider-aws/internal/priority"
+	"github.com/upbound/provider-aws/internal/tracing"
 )
 
 // Setup adds a controller that reconciles CertificateValidation managed resources.
@@ -29,23 +33,25 @@ func Setup(mgr ctrl.Manager, o tjcontroller.Options) error {
 	if o.SecretStoreConfigGVK != nil {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), *o.SecretStoreConfigGVK))
 	}
+	ws := metrics.NewWorkspaceStore(o.WorkspaceStore)
+	pollInterval := config.PollIntervals.Get(v1beta1.CertificateValidation_GroupVersionKind, o.PollInterval)
+	eventRecorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	r := managed.NewReconciler(mgr,
 		xpresource.ManagedKind(v1beta1.CertificateValidation_GroupVersionKind),
-		managed.WithExternalConnecter(tjcontroller.NewConnector(mgr.GetClient(), o.WorkspaceStore, o.SetupFn, o.Provider.Resources["aws_acm_certificate_validation"],
-			tjcontroller.WithCallbackProvider(tjcontroller.NewAPICallbacks(mgr, xpresource.ManagedKind(v1beta1.CertificateValidation_GroupVersionKind))),
+		managed.WithExternalConnecter(connector.NewConnector(mgr.GetClient(), ws, o.SetupFn, o.Provider.Resources["aws_acm_certificate_validation"],
+			connector.WithEventRecorder(eventRecorder),
+			connector.WithCallbackProvider(tjcontroller.NewAPICallbacks(mgr, xpresource.ManagedKind(v1beta1.CertificateValidation_GroupVersionKind))),
 		)),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithFinalizer(terraform.NewWorkspaceFinalizer(o.WorkspaceStore, xpresource.NewAPIFinalizer(mgr.GetClient(), managed.FinalizerName))),
-		managed.WithTimeout(3*time.Minute),
+		managed.WithRecorder(eventRecorder),
+		managed.WithFinalizer(connector.NewFinalizer(terraform.NewWorkspaceFinalizer(ws, xpresource.NewAPIFinalizer(mgr.GetClient(), managed.FinalizerName)))),
+		managed.WithTimeout(config.ReconcileTimeouts.Get(v1beta1.CertificateValidation_GroupVersionKind, config.DefaultReconcileTimeout)),
 		managed.WithInitializers(initializers),
 		managed.WithConnectionPublishers(cps...),
-		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollInterval(pollInterval),
 	)
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		For(&v1beta1.CertificateValidation{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+	q := priority.NewQueue(name, o.MaxConcurrentReconciles)
+	return priority.Setup(mgr, name, o.ForControllerRuntime(), q, &v1beta1.CertificateValidation{},
+		q.NewReconciler(ratelimiter.NewReconciler(name, tracing.NewReconciler(q.NewPollReconciler(poll.NewReconciler(r, mgr.GetClient(), mgr.GetScheme(), v1beta1.CertificateValidation_GroupVersionKind, pollInterval, o.Logger.WithValues("controller", name))), v1beta1.CertificateValidation_GroupVersionKind), o.GlobalRateLimiter), o.Logger.WithValues("controller", name)))
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/upbound/provider-aws/apis/v1alpha1"
 	"github.com/upbound/provider-aws/internal/clients"
 	"github.com/upbound/provider-aws/internal/features"
+	"github.com/upbound/provider-aws/internal/tracing"
 )
 
 const (
@@ -53,7 +54,7 @@ func Setup(mgr ctrl.Manager, o tjcontroller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.ClusterAuth{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracing.NewReconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.ClusterAuth_GroupVersionKind),
 			managed.WithExternalConnecter(&connector{
 				kube:               mgr.GetClient(),
@@ -65,7 +66,7 @@ func Setup(mgr ctrl.Manager, o tjcontroller.Options) error {
 			managed.WithPollInterval(time.Minute*1),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithConnectionPublishers(cps...)))
+			managed.WithConnectionPublishers(cps...)), v1beta1.ClusterAuth_GroupVersionKind))
 }
 
 type connector struct {
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package encryption encrypts the files of the Terraform workspaces at rest
+// with envelope encryption. Every file is encrypted with a data key, which is
+// itself encrypted with a key encryption key from a Kubernetes Secret or AWS
+// KMS and stored along with the file.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Extension is the extension of the encrypted files, which are stored
+	// next to where their plaintext files are.
+	Extension = ".enc"
+
+	envelopeVersion = 1
+	dataKeyLength   = 32
+
+	errNewDataKey     = "cannot generate a data key"
+	errDecryptDataKey = "cannot decrypt the data key"
+	errNewCipher      = "cannot create the cipher"
+	errRead           = "cannot read %s"
+	errWrite          = "cannot write %s"
+	errRemove         = "cannot remove %s"
+	errParseEnvelope  = "cannot parse the encrypted file %s"
+	errVersion        = "unsupported version %d of the encrypted file %s"
+	errDecrypt        = "cannot decrypt %s"
+	errStatFS         = "cannot get the file system of %s"
+)
+
+// A KeyWrapper generates the data keys the files are encrypted with and
+// encrypts and decrypts them with a key encryption key.
+type KeyWrapper interface {
+	// NewDataKey returns a new data key and its encrypted form.
+	NewDataKey(ctx context.Context) (key, encrypted []byte, err error)
+
+	// DecryptDataKey returns the data key with the given encrypted form.
+	DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// envelope is the content of an encrypted file.
+type envelope struct {
+	Version      int    `json:"version"`
+	EncryptedKey []byte `json:"encryptedKey"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// An Encrypter encrypts files at rest and decrypts them only while they are
+// used. The data key it encrypts files with is generated once and kept in
+// memory, as are the data keys it decrypts, so that the key encryption key
+// is not used on every call.
+type Encrypter struct {
+	keys KeyWrapper
+
+	mu           sync.Mutex
+	key          []byte
+	encryptedKey []byte
+	decrypted    map[string][]byte
+
+	// files serializes the calls for the same file.
+	files sync.Map
+}
+
+// New returns a new Encrypter that wraps its data keys with the given
+// KeyWrapper.
+func New(kw KeyWrapper) *Encrypter {
+	return &Encrypter{keys: kw, decrypted: map[string][]byte{}}
+}
+
+// Seal encrypts the file at the given path into the same path with the
+// Extension and removes the plaintext file. It does nothing if the file does
+// not exist.
+func (e *Encrypter) Seal(ctx context.Context, path string) error {
+	defer e.lock(path)()
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, errRead, path)
+	}
+	key, encryptedKey, err := e.dataKey(ctx)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	env := envelope{
+		Version:      envelopeVersion,
+		EncryptedKey: encryptedKey,
+		Nonce:        make([]byte, gcm.NonceSize()),
+	}
+	if _, err := io.ReadFull(rand.Reader, env.Nonce); err != nil {
+		return errors.Wrap(err, errNewCipher)
+	}
+	// The path is authenticated so that the encrypted files of different
+	// workspaces cannot be swapped.
+	env.Ciphertext = gcm.Seal(nil, env.Nonce, data, []byte(path))
+	out, err := json.Marshal(env)
+	if err != nil {
+		return errors.Wrapf(err, errWrite, path+Extension)
+	}
+	if err := writeFile(path+Extension, out); err != nil {
+		return err
+	}
+	return errors.Wrapf(os.Remove(path), errRemove, path)
+}
+
+// Unseal decrypts the encrypted file of the given path into the path. It does
+// nothing if the plaintext file exists, which is then newer than the
+// encrypted one, or if there is no encrypted file.
+func (e *Encrypter) Unseal(ctx context.Context, path string) error {
+	defer e.lock(path)()
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	in, err := os.ReadFile(filepath.Clean(path + Extension))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, errRead, path+Extension)
+	}
+	env := envelope{}
+	if err := json.Unmarshal(in, &env); err != nil {
+		return errors.Wrapf(err, errParseEnvelope, path+Extension)
+	}
+	if env.Version != envelopeVersion {
+		return errors.Errorf(errVersion, env.Version, path+Extension)
+	}
+	key, err := e.decryptDataKey(ctx, env.EncryptedKey)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	data, err := gcm.Open(nil, env.Nonce, env.Ciphertext, []byte(path))
+	if err != nil {
+		return errors.Wrapf(err, errDecrypt, path+Extension)
+	}
+	return writeFile(path, data)
+}
+
+func (e *Encrypter) lock(path string) func() {
+	mu, _ := e.files.LoadOrStore(path, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (e *Encrypter) dataKey(ctx context.Context) ([]byte, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.key != nil {
+		return e.key, e.encryptedKey, nil
+	}
+	key, encrypted, err := e.keys.NewDataKey(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errNewDataKey)
+	}
+	e.key, e.encryptedKey = key, encrypted
+	e.decrypted[string(encrypted)] = key
+	return key, encrypted, nil
+}
+
+func (e *Encrypter) decryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if key, ok := e.decrypted[string(encrypted)]; ok {
+		return key, nil
+	}
+	key, err := e.keys.DecryptDataKey(ctx, encrypted)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecryptDataKey)
+	}
+	e.decrypted[string(encrypted)] = key
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewCipher)
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, errors.Wrap(err, errNewCipher)
+}
+
+// writeFile writes the given file through a temporary file, so that it is
+// never partially written.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, errWrite, path)
+	}
+	return errors.Wrapf(os.Rename(tmp, path), errWrite, path)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countingKeyWrapper counts the calls made to the underlying KeyWrapper.
+type countingKeyWrapper struct {
+	KeyWrapper
+	newCalls, decryptCalls int
+}
+
+func (w *countingKeyWrapper) NewDataKey(ctx context.Context) ([]byte, []byte, error) {
+	w.newCalls++
+	return w.KeyWrapper.NewDataKey(ctx)
+}
+
+func (w *countingKeyWrapper) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	w.decryptCalls++
+	return w.KeyWrapper.DecryptDataKey(ctx, encrypted)
+}
+
+func newKeyWrapper(t *testing.T) *countingKeyWrapper {
+	t.Helper()
+	kw, err := NewSecretKeyWrapper(bytes.Repeat([]byte{7}, dataKeyLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingKeyWrapper{KeyWrapper: kw}
+}
+
+func TestSealUnseal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "terraform.tfstate")
+	state := []byte(`{"password":"secret"}`)
+	if err := os.WriteFile(path, state, 0600); err != nil {
+		t.Fatal(err)
+	}
+	kw := newKeyWrapper(t)
+	e := New(kw)
+
+	if err := e.Seal(ctx, path); err != nil {
+		t.Fatalf("Seal(...): %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Seal(...): the plaintext file should be removed: %v", err)
+	}
+	sealed, err := os.ReadFile(path + Extension)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Errorf("Seal(...): the encrypted file contains the plaintext")
+	}
+
+	if err := e.Unseal(ctx, path); err != nil {
+		t.Fatalf("Unseal(...): %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(state), string(got)); diff != "" {
+		t.Errorf("Unseal(...): -want, +got:\n%s", diff)
+	}
+
+	// A second round trip reuses the data key and a new Encrypter, such as
+	// the one of a restarted provider, decrypts it only once.
+	if err := e.Seal(ctx, path); err != nil {
+		t.Fatalf("Seal(...): %v", err)
+	}
+	restarted := New(kw)
+	for i := 0; i < 2; i++ {
+		if err := restarted.Unseal(ctx, path); err != nil {
+			t.Fatalf("Unseal(...): %v", err)
+		}
+		if err := restarted.Seal(ctx, path); err != nil {
+			t.Fatalf("Seal(...): %v", err)
+		}
+	}
+	if diff := cmp.Diff(2, kw.newCalls); diff != "" {
+		t.Errorf("data keys generated: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, kw.decryptCalls); diff != "" {
+		t.Errorf("data keys decrypted: -want, +got:\n%s", diff)
+	}
+}
+
+func TestUnsealSwapped(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(a, []byte("a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	e := New(newKeyWrapper(t))
+	if err := e.Seal(ctx, a); err != nil {
+		t.Fatalf("Seal(...): %v", err)
+	}
+	if err := os.Rename(a+Extension, b+Extension); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Unseal(ctx, b); err == nil {
+		t.Errorf("Unseal(...): an encrypted file moved to another path should not be decrypted")
+	}
+}
+
+func TestUnsealNoop(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		plaintext bool
+	}{
+		"NoEncryptedFile": {
+			reason: "Nothing should be done without an encrypted file.",
+		},
+		"PlaintextExists": {
+			reason:    "An existing plaintext file should not be overwritten.",
+			plaintext: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "terraform.tfstate")
+			if tc.plaintext {
+				if err := os.WriteFile(path, []byte("new"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path+Extension, []byte("not an envelope"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := New(newKeyWrapper(t)).Unseal(context.Background(), path); err != nil {
+				t.Errorf("\n%s\nUnseal(...): %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestMemoryBacked(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		dir    string
+		want   bool
+	}{
+		"Memory": {
+			reason: "A directory on a memory-backed file system should be reported as memory-backed.",
+			dir:    "/dev/shm",
+			want:   runtime.GOOS == "linux",
+		},
+		"Disk": {
+			reason: "A directory on a persistent file system should not be reported as memory-backed.",
+			dir:    ".",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := os.Stat(tc.dir); err != nil {
+				t.Skipf("%s is not available: %v", tc.dir, err)
+			}
+			got, err := MemoryBacked(tc.dir)
+			if err != nil {
+				t.Fatalf("\n%s\nMemoryBacked(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMemoryBacked(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package encryption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSClient is the subset of the AWS KMS API used to generate and decrypt
+// data keys.
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, in *kms.GenerateDataKeyInput, opts ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, in *kms.DecryptInput, opts ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// KMSKeyWrapper generates the data keys with an AWS KMS key, which encrypts
+// them.
+type KMSKeyWrapper struct {
+	client KMSClient
+	keyID  string
+}
+
+// NewKMSKeyWrapper returns a new KMSKeyWrapper that uses the KMS key with the
+// given ID, ARN or alias.
+func NewKMSKeyWrapper(client KMSClient, keyID string) *KMSKeyWrapper {
+	return &KMSKeyWrapper{client: client, keyID: keyID}
+}
+
+// NewDataKey returns a new data key generated by KMS and its encrypted form.
+func (w *KMSKeyWrapper) NewDataKey(ctx context.Context) ([]byte, []byte, error) {
+	out, err := w.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(w.keyID),
+		KeySpec: kmstypes.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.Plaintext, out.CiphertextBlob, nil
+}
+
+// DecryptDataKey decrypts the given encrypted data key with KMS.
+func (w *KMSKeyWrapper) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	out, err := w.client.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob: encrypted,
+		KeyId:          aws.String(w.keyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
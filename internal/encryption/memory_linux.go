@@ -0,0 +1,28 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package encryption
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// The magic numbers of the memory-backed file systems in the statfs(2) types.
+const (
+	tmpfsMagic = 0x01021994
+	ramfsMagic = 0x858458f6
+)
+
+// MemoryBacked returns true if the given directory is on a memory-backed file
+// system, such as an emptyDir volume with the Memory medium, so that the
+// plaintext files in it never reach persistent disk.
+func MemoryBacked(dir string) (bool, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return false, errors.Wrapf(err, errStatFS, dir)
+	}
+	return fs.Type == tmpfsMagic || fs.Type == ramfsMagic, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package encryption
+
+// MemoryBacked returns true if the given directory is on a memory-backed file
+// system. It is only known on Linux, so it always returns false otherwise.
+func MemoryBacked(string) (bool, error) {
+	return false, nil
+}
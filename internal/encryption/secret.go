@@ -0,0 +1,65 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// SecretKey is the key of the key encryption key in the data of its
+// Kubernetes Secret.
+const SecretKey = "key"
+
+const errKeyLength = "the key encryption key must be 32 bytes long"
+
+// SecretKeyWrapper encrypts the data keys with a key encryption key that is
+// read from a Kubernetes Secret.
+type SecretKeyWrapper struct {
+	kek []byte
+}
+
+// NewSecretKeyWrapper returns a new SecretKeyWrapper that encrypts the data
+// keys with the given 32 bytes long AES-256 key.
+func NewSecretKeyWrapper(kek []byte) (*SecretKeyWrapper, error) {
+	if len(kek) != dataKeyLength {
+		return nil, errors.New(errKeyLength)
+	}
+	return &SecretKeyWrapper{kek: kek}, nil
+}
+
+// NewDataKey returns a new random data key and its encrypted form.
+func (w *SecretKeyWrapper) NewDataKey(_ context.Context) ([]byte, []byte, error) {
+	key := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, err
+	}
+	gcm, err := newGCM(w.kek)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	// The nonce is prepended to the encrypted key.
+	return key, gcm.Seal(nonce, nonce, key, nil), nil
+}
+
+// DecryptDataKey returns the data key with the given encrypted form.
+func (w *SecretKeyWrapper) DecryptDataKey(_ context.Context, encrypted []byte) ([]byte, error) {
+	gcm, err := newGCM(w.kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, errors.New(errDecryptDataKey)
+	}
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -12,4 +12,9 @@ const (
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
+
+	// EnableAlphaNativeClients enables the external clients that manage the
+	// external resources of some frequently reconciled kinds with the AWS
+	// SDK instead of Terraform.
+	EnableAlphaNativeClients feature.Flag = "EnableAlphaNativeClients"
 )
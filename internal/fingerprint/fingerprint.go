@@ -0,0 +1,80 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package fingerprint takes lightweight fingerprints of external resources,
+// such as their ETags or revisions, which tell whether they changed without
+// reading them in full.
+package fingerprint
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+// A Fingerprinter takes fingerprints of external resources.
+type Fingerprinter interface {
+	// Fingerprint returns a fingerprint of the external resource of the
+	// given managed resource, which changes whenever the external resource
+	// changes. It returns an empty string if the external resource does not
+	// exist.
+	Fingerprint(ctx context.Context, tr resource.Terraformed) (string, error)
+}
+
+// fingerprinters are the constructors of the Fingerprinters of the Terraform
+// resources whose fingerprints are taken.
+var fingerprinters = map[string]func(cfg aws.Config) Fingerprinter{
+	"aws_s3_object":       func(cfg aws.Config) Fingerprinter { return newS3Object(cfg) },
+	"aws_iam_policy":      func(cfg aws.Config) Fingerprinter { return newIAMPolicy(cfg) },
+	"aws_lambda_function": func(cfg aws.Config) Fingerprinter { return newLambdaFunction(cfg) },
+}
+
+// Supported returns true if fingerprints are taken of the given Terraform
+// resource.
+func Supported(resource string) bool {
+	_, ok := fingerprinters[resource]
+	return ok
+}
+
+// New returns the Fingerprinter of the given Terraform resource, or false if
+// fingerprints are not taken of it.
+func New(resource string, cfg aws.Config) (Fingerprinter, bool) {
+	fn, ok := fingerprinters[resource]
+	if !ok {
+		return nil, false
+	}
+	return fn(cfg), true
+}
+
+// join joins the given parts of a fingerprint.
+func join(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// isErrorCode returns true if the given error is an AWS API error with one of
+// the given codes.
+func isErrorCode(err error, codes ...string) bool {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return false
+	}
+	for _, c := range codes {
+		if ae.ErrorCode() == c {
+			return true
+		}
+	}
+	return false
+}
+
+// stringAttr returns the string attribute with the given name of the given
+// Terraform attributes.
+func stringAttr(attrs map[string]any, name string) string {
+	s, _ := attrs[name].(string)
+	return s
+}
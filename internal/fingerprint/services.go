@@ -0,0 +1,105 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package fingerprint
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+const (
+	errGetParameters  = "cannot get the parameters"
+	errGetObservation = "cannot get the observation"
+)
+
+// s3Object takes the entity tag, version and modification time of S3 objects.
+// The tags of the objects are not part of the fingerprint.
+type s3Object struct {
+	client *s3.Client
+}
+
+func newS3Object(cfg aws.Config) *s3Object {
+	return &s3Object{client: s3.NewFromConfig(cfg)}
+}
+
+func (f *s3Object) Fingerprint(ctx context.Context, tr resource.Terraformed) (string, error) {
+	params, err := tr.GetParameters()
+	if err != nil {
+		return "", errors.Wrap(err, errGetParameters)
+	}
+	out, err := f.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(stringAttr(params, "bucket")),
+		Key:    aws.String(stringAttr(params, "key")),
+	})
+	if isErrorCode(err, "NotFound", "NoSuchKey", "NoSuchBucket") {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return join(aws.ToString(out.ETag), aws.ToString(out.VersionId), aws.ToTime(out.LastModified).String()), nil
+}
+
+// iamPolicy takes the default version and update time of IAM policies, which
+// change whenever their documents change. The tags of the policies are not
+// part of the fingerprint.
+type iamPolicy struct {
+	client *iam.Client
+}
+
+func newIAMPolicy(cfg aws.Config) *iamPolicy {
+	return &iamPolicy{client: iam.NewFromConfig(cfg)}
+}
+
+func (f *iamPolicy) Fingerprint(ctx context.Context, tr resource.Terraformed) (string, error) {
+	obs, err := tr.GetObservation()
+	if err != nil {
+		return "", errors.Wrap(err, errGetObservation)
+	}
+	arn := stringAttr(obs, "arn")
+	if arn == "" {
+		return "", nil
+	}
+	out, err := f.client.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(arn)})
+	if isErrorCode(err, "NoSuchEntity") {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return join(aws.ToString(out.Policy.DefaultVersionId), aws.ToTime(out.Policy.UpdateDate).String()), nil
+}
+
+// lambdaFunction takes the revisions of Lambda functions, which change
+// whenever their code or configuration change. The tags of the functions
+// are not part of the fingerprint.
+type lambdaFunction struct {
+	client *lambda.Client
+}
+
+func newLambdaFunction(cfg aws.Config) *lambdaFunction {
+	return &lambdaFunction{client: lambda.NewFromConfig(cfg)}
+}
+
+func (f *lambdaFunction) Fingerprint(ctx context.Context, tr resource.Terraformed) (string, error) {
+	out, err := f.client.GetFunctionConfiguration(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(meta.GetExternalName(tr)),
+	})
+	if isErrorCode(err, "ResourceNotFoundException") {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.RevisionId), nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package logger contains the logr.LogSink used by the provider. It supports
+// JSON and console output, per group and per kind log levels that can be
+// changed at runtime, and redacts credentials and sensitive Terraform
+// attributes from the log lines.
+package logger
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Log formats.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Log levels.
+const (
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+const (
+	keyFormat      = "format"
+	keyLevel       = "level"
+	keyLevelPrefix = "level."
+)
+
+// Config is the logging configuration of the provider.
+type Config struct {
+	// Format of the log lines, either json or console.
+	Format string
+
+	// Level is the default log level, either info or debug.
+	Level string
+
+	// Levels overrides the default log level for a group, such as "rds", or
+	// for a kind, such as "ec2.SecurityGroup".
+	Levels map[string]string
+
+	// Development enables the development mode of the underlying zap logger,
+	// which adds stack traces to warnings.
+	Development bool
+}
+
+// ParseLevels parses a comma separated list of <group or group.Kind>=<level>
+// pairs, such as "rds=debug,ec2.SecurityGroup=debug".
+func ParseLevels(s string) (map[string]string, error) {
+	levels := map[string]string{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid log level override %q, expected <group or group.Kind>=<level>", p)
+		}
+		levels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return levels, nil
+}
+
+// Merge returns a copy of the configuration overridden with the given
+// ConfigMap data. The "format" and "level" keys override the format and the
+// default level, and "level.<group or group.Kind>" keys override the level
+// of a group or a kind.
+func (c Config) Merge(data map[string]string) Config {
+	out := c
+	out.Levels = make(map[string]string, len(c.Levels))
+	for k, v := range c.Levels {
+		out.Levels[k] = v
+	}
+	for k, v := range data {
+		switch {
+		case k == keyFormat:
+			out.Format = v
+		case k == keyLevel:
+			out.Level = v
+		case strings.HasPrefix(k, keyLevelPrefix):
+			out.Levels[strings.TrimPrefix(k, keyLevelPrefix)] = v
+		}
+	}
+	return out
+}
+
+// Validate returns an error if the configuration has an unknown format or
+// level.
+func (c Config) Validate() error {
+	switch c.Format {
+	case FormatJSON, FormatConsole:
+	default:
+		return errors.Errorf("unknown log format %q, expected %q or %q", c.Format, FormatJSON, FormatConsole)
+	}
+	if _, err := verbosity(c.Level); err != nil {
+		return err
+	}
+	for k, l := range c.Levels {
+		if _, err := verbosity(l); err != nil {
+			return errors.Wrapf(err, "invalid log level for %q", k)
+		}
+	}
+	return nil
+}
+
+// verbosity returns the maximum logr verbosity that is logged for the given
+// level. Debug messages of the crossplane-runtime logger are logged with
+// verbosity 1.
+func verbosity(level string) (int, error) {
+	switch strings.ToLower(level) {
+	case LevelInfo:
+		return 0, nil
+	case LevelDebug:
+		return 1, nil
+	default:
+		return 0, errors.Errorf("unknown log level %q, expected %q or %q", level, LevelInfo, LevelDebug)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const resyncPeriod = 10 * time.Minute
+
+// ConfigMapWatcher reconfigures a Sink whenever the logging ConfigMap of the
+// provider changes. The ConfigMap data overrides the default configuration as
+// described in Config.Merge, and the default configuration is restored when
+// the ConfigMap is deleted.
+type ConfigMapWatcher struct {
+	sink      *Sink
+	defaults  Config
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	log       logging.Logger
+}
+
+// NewConfigMapWatcher returns a ConfigMapWatcher for the ConfigMap with the
+// given namespace and name.
+func NewConfigMapWatcher(s *Sink, defaults Config, c kubernetes.Interface, namespace, name string, l logging.Logger) *ConfigMapWatcher {
+	return &ConfigMapWatcher{
+		sink:      s,
+		defaults:  defaults,
+		client:    c,
+		namespace: namespace,
+		name:      name,
+		log:       l,
+	}
+}
+
+// NeedLeaderElection returns false since every replica of the provider logs.
+func (w *ConfigMapWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the ConfigMap until the given context is done.
+func (w *ConfigMapWatcher) Start(ctx context.Context) error {
+	f := informers.NewSharedInformerFactoryWithOptions(w.client, resyncPeriod,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.name).String()
+		}))
+	i := f.Core().V1().ConfigMaps().Informer()
+	i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			w.apply(obj)
+		},
+		UpdateFunc: func(_, obj any) {
+			w.apply(obj)
+		},
+		DeleteFunc: func(any) {
+			w.configure(w.defaults)
+		},
+	})
+	f.Start(ctx.Done())
+	<-ctx.Done()
+	return nil
+}
+
+func (w *ConfigMapWatcher) apply(obj any) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	w.configure(w.defaults.Merge(cm.Data))
+}
+
+func (w *ConfigMapWatcher) configure(c Config) {
+	if err := w.sink.Configure(c); err != nil {
+		w.log.Info("Cannot apply the logging configuration, keeping the current one", "configMap", w.namespace+"/"+w.name, "error", err)
+		return
+	}
+	w.log.Debug("Applied the logging configuration", "configMap", w.namespace+"/"+w.name, "level", c.Level, "levels", c.Levels)
+}
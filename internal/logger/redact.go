@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// sensitiveKeys are the substrings of the log keys and the attribute
+	// names whose values are never logged.
+	sensitiveKeys = []string{
+		"password",
+		"secret",
+		"token",
+		"access_key",
+		"accesskey",
+		"private_key",
+		"privatekey",
+		"credentials",
+	}
+
+	// sensitiveAttribute matches sensitive attributes in the JSON or HCL
+	// documents, such as Terraform configuration and state, that are
+	// embedded in the log values.
+	sensitiveAttribute = regexp.MustCompile(`(?i)("?[a-z0-9_]*(?:password|secret|token|access_key|private_key)[a-z0-9_]*"?\s*[:=]\s*)"(?:[^"\\]|\\.)*"`)
+
+	// awsAccessKeyID matches the AWS access key IDs of long term and
+	// temporary credentials.
+	awsAccessKeyID = regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`)
+)
+
+func isSensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString removes the AWS access key IDs and the values of sensitive
+// attributes from the given string.
+func redactString(s string) string {
+	s = sensitiveAttribute.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	return awsAccessKeyID.ReplaceAllString(s, redacted)
+}
+
+// redactKeysAndValues returns a copy of the given logr key/value pairs where
+// the values of sensitive keys are replaced and the string values are
+// redacted.
+func redactKeysAndValues(kvs []any) []any {
+	out := make([]any, len(kvs))
+	copy(out, kvs)
+	for i := 1; i < len(out); i += 2 {
+		if k, ok := out[i-1].(string); ok && isSensitiveKey(k) {
+			out[i] = redacted
+			continue
+		}
+		switch v := out[i].(type) {
+		case string:
+			out[i] = redactString(v)
+		case []byte:
+			out[i] = redactString(string(v))
+		case error:
+			out[i] = redactString(v.Error())
+		case fmt.Stringer:
+			out[i] = redactString(v.String())
+		}
+	}
+	return out
+}
+
+// redactedError is an error whose message is redacted.
+type redactedError struct {
+	err error
+}
+
+func (e redactedError) Error() string {
+	return redactString(e.err.Error())
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package logger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// keyController is the key the controller name is logged with by both
+	// the managed reconcilers and controller-runtime, e.g.
+	// managed/rds.aws.upbound.io/v1beta1, Kind=Instance
+	keyController = "controller"
+	// keyControllerGroup and keyControllerKind are the keys the group and
+	// the kind of a controller are logged with by controller-runtime.
+	keyControllerGroup = "controllerGroup"
+	keyControllerKind  = "controllerKind"
+)
+
+// root holds the configuration shared by a Sink and all the sinks derived
+// from it.
+type root struct {
+	mu         sync.RWMutex
+	base       logr.LogSink
+	info       logr.RuntimeInfo
+	defaultV   int
+	levels     map[string]int
+	generation uint64
+}
+
+func (r *root) configure(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	def, _ := verbosity(c.Level)
+	levels := make(map[string]int, len(c.Levels))
+	for k, l := range c.Levels {
+		v, _ := verbosity(l)
+		levels[strings.ToLower(k)] = v
+	}
+	opts := []zap.Opts{zap.UseDevMode(c.Development), zap.Level(zapcore.DebugLevel)}
+	if c.Format == FormatJSON {
+		opts = append(opts, zap.JSONEncoder())
+	} else {
+		opts = append(opts, zap.ConsoleEncoder())
+	}
+	base := zap.New(opts...).GetSink()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.base = base
+	r.defaultV = def
+	r.levels = levels
+	r.generation++
+	return nil
+}
+
+// verbosity returns the maximum verbosity for the given group and kind. A
+// kind override takes precedence over a group override.
+func (r *root) verbosity(group, kind string) int {
+	if v, ok := r.levels[group+"."+kind]; ok && kind != "" {
+		return v
+	}
+	if v, ok := r.levels[group]; ok && group != "" {
+		return v
+	}
+	return r.defaultV
+}
+
+// derived is the underlying sink of a Sink built for a given generation of
+// the root configuration.
+type derived struct {
+	generation uint64
+	sink       logr.LogSink
+	maxV       int
+}
+
+// Sink is a logr.LogSink that filters the log lines according to the log
+// level of the group and the kind of the controller that logs them, and
+// redacts credentials and sensitive attributes.
+type Sink struct {
+	root *root
+
+	names     []string
+	kvs       []any
+	callDepth int
+	group     string
+	kind      string
+
+	current atomic.Pointer[derived]
+}
+
+// NewSink returns a new Sink with the given configuration.
+func NewSink(c Config) (*Sink, error) {
+	r := &root{}
+	if err := r.configure(c); err != nil {
+		return nil, err
+	}
+	return &Sink{root: r}, nil
+}
+
+// Configure changes the configuration of this sink and all the sinks derived
+// from it.
+func (s *Sink) Configure(c Config) error {
+	return s.root.configure(c)
+}
+
+// sink returns the underlying sink for the current root configuration.
+func (s *Sink) sink() *derived {
+	s.root.mu.RLock()
+	defer s.root.mu.RUnlock()
+	if d := s.current.Load(); d != nil && d.generation == s.root.generation {
+		return d
+	}
+	base := s.root.base
+	base.Init(logr.RuntimeInfo{CallDepth: s.root.info.CallDepth + 1})
+	if s.callDepth > 0 {
+		if cd, ok := base.(logr.CallDepthLogSink); ok {
+			base = cd.WithCallDepth(s.callDepth)
+		}
+	}
+	for _, n := range s.names {
+		base = base.WithName(n)
+	}
+	if len(s.kvs) > 0 {
+		base = base.WithValues(s.kvs...)
+	}
+	d := &derived{
+		generation: s.root.generation,
+		sink:       base,
+		maxV:       s.root.verbosity(s.group, s.kind),
+	}
+	s.current.Store(d)
+	return d
+}
+
+func (s *Sink) clone() *Sink {
+	return &Sink{
+		root:      s.root,
+		names:     append([]string{}, s.names...),
+		kvs:       append([]any{}, s.kvs...),
+		callDepth: s.callDepth,
+		group:     s.group,
+		kind:      s.kind,
+	}
+}
+
+// Init receives optional information about the logr library.
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.root.mu.Lock()
+	defer s.root.mu.Unlock()
+	s.root.info = info
+}
+
+// Enabled tests whether this Sink is enabled at the specified verbosity
+// level.
+func (s *Sink) Enabled(level int) bool {
+	d := s.sink()
+	return level <= d.maxV && d.sink.Enabled(level)
+}
+
+// Info logs a non-error message with the given key/value pairs.
+func (s *Sink) Info(level int, msg string, kvs ...any) {
+	s.sink().sink.Info(level, redactString(msg), redactKeysAndValues(kvs)...)
+}
+
+// Error logs an error, with the given message and key/value pairs.
+func (s *Sink) Error(err error, msg string, kvs ...any) {
+	if err != nil {
+		err = redactedError{err: err}
+	}
+	s.sink().sink.Error(err, redactString(msg), redactKeysAndValues(kvs)...)
+}
+
+// WithValues returns a new Sink with additional key/value pairs. The
+// controller name is used to look up the log level of its group and kind.
+func (s *Sink) WithValues(kvs ...any) logr.LogSink {
+	c := s.clone()
+	c.kvs = append(c.kvs, redactKeysAndValues(kvs)...)
+	for i := 1; i < len(kvs); i += 2 {
+		k, _ := kvs[i-1].(string)
+		v, _ := kvs[i].(string)
+		switch k {
+		case keyController:
+			if g, kind := parseControllerName(v); g != "" {
+				c.group, c.kind = g, kind
+			}
+		case keyControllerGroup:
+			c.group = shortGroup(v)
+		case keyControllerKind:
+			c.kind = strings.ToLower(v)
+		}
+	}
+	return c
+}
+
+// WithName returns a new Sink with the specified name appended.
+func (s *Sink) WithName(name string) logr.LogSink {
+	c := s.clone()
+	c.names = append(c.names, name)
+	return c
+}
+
+// WithCallDepth returns a Sink that will offset the call stack by the
+// specified number of frames when logging call site information.
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	c := s.clone()
+	c.callDepth += depth
+	return c
+}
+
+// parseControllerName returns the short group and the kind of the given
+// managed resource controller name, which is either in the form of
+// managed/rds.aws.upbound.io/v1beta1, Kind=Instance or
+// managed/ClusterAuth.eks.aws.upbound.io.
+func parseControllerName(name string) (string, string) {
+	name = strings.TrimPrefix(name, "managed/")
+	if i := strings.Index(name, ", Kind="); i != -1 {
+		gv := name[:i]
+		if j := strings.Index(gv, "/"); j != -1 {
+			gv = gv[:j]
+		}
+		return shortGroup(gv), strings.ToLower(name[i+len(", Kind="):])
+	}
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) < 3 {
+		return "", ""
+	}
+	return strings.ToLower(parts[1]), strings.ToLower(parts[0])
+}
+
+// shortGroup returns the first segment of the given API group, e.g. rds for
+// rds.aws.upbound.io.
+func shortGroup(g string) string {
+	if i := strings.Index(g, "."); i != -1 {
+		g = g[:i]
+	}
+	return strings.ToLower(g)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// STS operation label values.
+const (
+	OperationAssumeRole                = "AssumeRole"
+	OperationAssumeRoleWithWebIdentity = "AssumeRoleWithWebIdentity"
+)
+
+const apiCallMetricsID = "ProviderAWSAPICallMetrics"
+
+// AddAPICallMetrics adds a middleware to the given AWS SDK stack that records
+// the count and the duration of every API call made with it. It's added to
+// the end of the initialize step so that the service metadata of the
+// operation is already available in the context.
+func AddAPICallMetrics(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(apiCallMetricsID, func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		start := time.Now()
+		out, md, err := next.HandleInitialize(ctx, in)
+		service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+		AWSAPICalls.WithLabelValues(service, operation, Result(err)).Inc()
+		AWSAPICallDuration.WithLabelValues(service, operation).Observe(time.Since(start).Seconds())
+		return out, md, err
+	}), middleware.After)
+}
+
+// NewCredentialsProvider returns an aws.CredentialsProvider that records the
+// duration and the failures of the credential retrievals of the given STS
+// credentials provider under the given operation label.
+func NewCredentialsProvider(operation string, p aws.CredentialsProvider) aws.CredentialsProvider {
+	return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		start := time.Now()
+		creds, err := p.Retrieve(ctx)
+		STSAssumeRoleDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		if err != nil {
+			STSAssumeRoleFailures.WithLabelValues(operation).Inc()
+		}
+		return creds, err
+	})
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package metrics contains the provider specific Prometheus metrics exposed
+// through the controller-runtime metrics endpoint.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "provider_aws"
+
+	// ResultSuccess is the result label value of successful calls.
+	ResultSuccess = "success"
+	// ResultError is the result label value of failed calls.
+	ResultError = "error"
+)
+
+// Terraform operation label values.
+const (
+	OperationRefresh = "refresh"
+	OperationPlan    = "plan"
+	OperationApply   = "apply"
+	OperationDestroy = "destroy"
+)
+
+var (
+	// TerraformOperations counts the Terraform operations run for managed
+	// resources.
+	TerraformOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "operations_total",
+		Help:      "Number of Terraform operations run, labelled by the kind of the managed resource, the operation and its result.",
+	}, []string{"group", "kind", "operation", "result"})
+
+	// TerraformOperationDuration observes the duration of the Terraform
+	// operations run for managed resources.
+	TerraformOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of Terraform operations, labelled by the kind of the managed resource and the operation.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300, 600, 1200, 3600},
+	}, []string{"group", "kind", "operation"})
+
+	// TerraformWorkspaces is the number of Terraform workspaces currently
+	// held by the workspace store.
+	TerraformWorkspaces = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "workspaces",
+		Help:      "Number of Terraform workspaces held by the workspace store, labelled by the kind of the managed resource.",
+	}, []string{"group", "kind"})
+
+	// TerraformWorkspaceDirectories is the number of Terraform workspace
+	// directories on disk, including those of workspaces that are no longer
+	// held by the workspace store.
+	TerraformWorkspaceDirectories = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "workspace_directories",
+		Help:      "Number of Terraform workspace directories on disk.",
+	})
+
+	// TerraformWorkspaceDiskBytes is the disk usage of the Terraform
+	// workspaces.
+	TerraformWorkspaceDiskBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "workspace_disk_bytes",
+		Help:      "Number of bytes used on disk by the Terraform workspaces.",
+	})
+
+	// TerraformWorkspaceRemovals counts the Terraform workspaces removed by
+	// the workspace garbage collector.
+	TerraformWorkspaceRemovals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "workspace_removals_total",
+		Help:      "Number of Terraform workspaces removed from disk, labelled by whether they were orphaned or evicted to stay within the disk budget.",
+	}, []string{"reason"})
+
+	// SharedProviderStarts counts the calls made to the shared native
+	// Terraform provider runner.
+	SharedProviderStarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "shared_provider_starts_total",
+		Help:      "Number of calls to start or attach to the shared native Terraform provider, labelled by their result.",
+	}, []string{"result"})
+
+	// SharedProviderStartDuration observes the time it takes to start or
+	// attach to the shared native Terraform provider.
+	SharedProviderStartDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "shared_provider_start_duration_seconds",
+		Help:      "Time it takes to start or attach to the shared native Terraform provider.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	})
+
+	// NativeProviderUp reports whether the native provider processes of the
+	// shared provider pool are running.
+	NativeProviderUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "native_provider_up",
+		Help:      "Whether the native Terraform provider process is running, labelled by its place in the pool.",
+	}, []string{"process"})
+
+	// NativeProviderRestarts counts the restarts of the native provider
+	// processes of the shared provider pool.
+	NativeProviderRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "native_provider_restarts_total",
+		Help:      "Number of restarts of the native Terraform provider processes, labelled by their place in the pool and the reason of the restart.",
+	}, []string{"process", "reason"})
+
+	// NativeProviderMemoryBytes is the resident memory of the native provider
+	// processes of the shared provider pool.
+	NativeProviderMemoryBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "native_provider_memory_bytes",
+		Help:      "Resident memory of the native Terraform provider processes, labelled by their place in the pool.",
+	}, []string{"process"})
+
+	// NativeProviderOperations counts the workspace operations the native
+	// provider processes of the shared provider pool were handed out to.
+	NativeProviderOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "native_provider_operations_total",
+		Help:      "Number of workspace operations the native Terraform provider processes were handed out to, labelled by their place in the pool.",
+	}, []string{"process"})
+
+	// TerraformRefreshesSkipped counts the observations that skipped the
+	// Terraform refresh and plan since neither the managed resources nor
+	// their external resources changed.
+	TerraformRefreshesSkipped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "terraform",
+		Name:      "refreshes_skipped_total",
+		Help:      "Number of observations that skipped the Terraform refresh and plan of unchanged resources, labelled by the kind of the managed resource.",
+	}, []string{"group", "kind"})
+
+	// PriorityQueueDepth observes the number of requests waiting to be
+	// handed to the workqueues of the controllers by priority.
+	PriorityQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "priority_queue_depth",
+		Help:      "Number of requests waiting to be handed to the workqueue of the controller, labelled by the controller and their priority.",
+	}, []string{"controller", "priority"})
+
+	// ReconcileErrors counts the failed reconciles of the controllers that
+	// hand their requests to the workqueue by priority, whose errors are not
+	// returned to controller-runtime.
+	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of failed reconciles, labelled by the controller.",
+	}, []string{"controller"})
+
+	// DriftDetections counts the drifts of external resources from the
+	// desired state of their managed resources that were not made by the
+	// provider, such as changes made with the AWS console.
+	DriftDetections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "managed",
+		Name:      "drift_detections_total",
+		Help:      "Number of drifts of external resources detected, labelled by the kind of the managed resource.",
+	}, []string{"group", "kind"})
+
+	// AWSAPICalls counts the AWS API calls made by the provider with the AWS
+	// SDK.
+	AWSAPICalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "aws",
+		Name:      "api_calls_total",
+		Help:      "Number of AWS API calls made with the AWS SDK, labelled by service, operation and result.",
+	}, []string{"service", "operation", "result"})
+
+	// AWSAPICallDuration observes the duration of the AWS API calls made by
+	// the provider with the AWS SDK.
+	AWSAPICallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "aws",
+		Name:      "api_call_duration_seconds",
+		Help:      "Duration of AWS API calls made with the AWS SDK, labelled by service and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "operation"})
+
+	// NativeBatchedObservations counts the observations of the native
+	// external clients that are served from batched list calls.
+	NativeBatchedObservations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "native",
+		Name:      "batched_observations_total",
+		Help:      "Number of observations served from batched list calls, labelled by the kind of the managed resource and whether the list call was made for them, its results were cached or the external resource was missing from them.",
+	}, []string{"kind", "result"})
+
+	// STSAssumeRoleDuration observes the time it takes to retrieve
+	// credentials by assuming a role.
+	STSAssumeRoleDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "sts",
+		Name:      "assume_role_duration_seconds",
+		Help:      "Time it takes to retrieve credentials by assuming a role, labelled by the STS operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// STSAssumeRoleFailures counts the failed attempts to retrieve
+	// credentials by assuming a role.
+	STSAssumeRoleFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sts",
+		Name:      "assume_role_failures_total",
+		Help:      "Number of failed attempts to retrieve credentials by assuming a role, labelled by the STS operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		TerraformOperations,
+		TerraformOperationDuration,
+		TerraformWorkspaces,
+		TerraformWorkspaceDirectories,
+		TerraformWorkspaceDiskBytes,
+		TerraformWorkspaceRemovals,
+		SharedProviderStarts,
+		SharedProviderStartDuration,
+		NativeProviderUp,
+		NativeProviderRestarts,
+		NativeProviderMemoryBytes,
+		NativeProviderOperations,
+		TerraformRefreshesSkipped,
+		PriorityQueueDepth,
+		ReconcileErrors,
+		DriftDetections,
+		AWSAPICalls,
+		AWSAPICallDuration,
+		NativeBatchedObservations,
+		STSAssumeRoleDuration,
+		STSAssumeRoleFailures,
+	)
+}
+
+// Result returns the result label value for the given error.
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// ObserveTerraformOperation records a finished Terraform operation that was
+// started at the given time.
+func ObserveTerraformOperation(group, kind, operation string, start time.Time, err error) {
+	TerraformOperations.WithLabelValues(group, kind, operation, Result(err)).Inc()
+	TerraformOperationDuration.WithLabelValues(group, kind, operation).Observe(time.Since(start).Seconds())
+}
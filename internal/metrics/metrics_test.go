@@ -0,0 +1,181 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// runner is a terraform.ProviderRunner that starts with the given error.
+type runner struct {
+	err error
+}
+
+func (r runner) Start() (string, error) {
+	return "reattach-config", r.err
+}
+
+// samples returns the number of samples observed by the given histogram.
+func samples(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := o.(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestObserveTerraformOperation(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		result string
+	}{
+		"Success": {
+			reason: "A successful operation should be counted as a success.",
+			result: ResultSuccess,
+		},
+		"Error": {
+			reason: "A failed operation should be counted as an error.",
+			err:    errors.New("boom"),
+			result: ResultError,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Every case counts the operations of its own kind.
+			kind := "Operation" + name
+			ObserveTerraformOperation("s3", kind, OperationApply, time.Now(), tc.err)
+			if diff := cmp.Diff(1.0, testutil.ToFloat64(TerraformOperations.WithLabelValues("s3", kind, OperationApply, tc.result))); diff != "" {
+				t.Errorf("\n%s\nObserveTerraformOperation(...): -want operations, +got operations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(uint64(1), samples(t, TerraformOperationDuration.WithLabelValues("s3", kind, OperationApply))); diff != "" {
+				t.Errorf("\n%s\nObserveTerraformOperation(...): -want durations, +got durations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderRunnerStart(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		result string
+	}{
+		"Success": {
+			reason: "A successful start of the shared provider should be counted as a success.",
+			result: ResultSuccess,
+		},
+		"Error": {
+			reason: "A failed start of the shared provider should be counted as an error.",
+			err:    errors.New("boom"),
+			result: ResultError,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			starts := testutil.ToFloat64(SharedProviderStarts.WithLabelValues(tc.result))
+			durations := samples(t, SharedProviderStartDuration)
+			if _, err := NewProviderRunner(runner{err: tc.err}).Start(); !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nStart(): want error %v, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(starts+1, testutil.ToFloat64(SharedProviderStarts.WithLabelValues(tc.result))); diff != "" {
+				t.Errorf("\n%s\nStart(): -want starts, +got starts:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(durations+1, samples(t, SharedProviderStartDuration)); diff != "" {
+				t.Errorf("\n%s\nStart(): -want durations, +got durations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAddAPICallMetrics(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		result string
+	}{
+		"Success": {
+			reason: "A successful AWS API call should be counted as a success.",
+			result: ResultSuccess,
+		},
+		"Error": {
+			reason: "A failed AWS API call should be counted as an error.",
+			err:    errors.New("boom"),
+			result: ResultError,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Every case counts the calls of its own operation.
+			operation := "Get" + name
+			stack := middleware.NewStack("test", func() any { return struct{}{} })
+			if err := stack.Initialize.Add(&awsmiddleware.RegisterServiceMetadata{ServiceID: "S3", OperationName: operation}, middleware.Before); err != nil {
+				t.Fatal(err)
+			}
+			if err := AddAPICallMetrics(stack); err != nil {
+				t.Fatal(err)
+			}
+			h := middleware.DecorateHandler(middleware.HandlerFunc(func(context.Context, any) (any, middleware.Metadata, error) {
+				return nil, middleware.Metadata{}, tc.err
+			}), stack)
+			if _, _, err := h.Handle(context.Background(), struct{}{}); !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nHandle(...): want error %v, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(1.0, testutil.ToFloat64(AWSAPICalls.WithLabelValues("S3", operation, tc.result))); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(uint64(1), samples(t, AWSAPICallDuration.WithLabelValues("S3", operation))); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want durations, +got durations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewCredentialsProvider(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		err      error
+		failures float64
+	}{
+		"Retrieved": {
+			reason: "Retrieved credentials should not be counted as a failure.",
+		},
+		"Failed": {
+			reason:   "A failure to retrieve credentials should be counted.",
+			err:      errors.New("boom"),
+			failures: 1,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Every case counts the retrievals of its own operation.
+			operation := OperationAssumeRole + name
+			p := NewCredentialsProvider(operation, aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{}, tc.err
+			}))
+			if _, err := p.Retrieve(context.Background()); !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nRetrieve(...): want error %v, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(tc.failures, testutil.ToFloat64(STSAssumeRoleFailures.WithLabelValues(operation))); diff != "" {
+				t.Errorf("\n%s\nRetrieve(...): -want failures, +got failures:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(uint64(1), samples(t, STSAssumeRoleDuration.WithLabelValues(operation))); diff != "" {
+				t.Errorf("\n%s\nRetrieve(...): -want durations, +got durations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
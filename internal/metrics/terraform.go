@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource"
+	"github.com/upbound/upjet/pkg/terraform"
+)
+
+// ProviderRunner is a terraform.ProviderRunner that records the calls made to
+// the underlying runner.
+type ProviderRunner struct {
+	terraform.ProviderRunner
+}
+
+// NewProviderRunner returns a new ProviderRunner wrapping the given runner.
+func NewProviderRunner(r terraform.ProviderRunner) *ProviderRunner {
+	return &ProviderRunner{ProviderRunner: r}
+}
+
+// Start starts or attaches to the shared native provider and records the
+// result of the call.
+func (pr *ProviderRunner) Start() (string, error) {
+	start := time.Now()
+	c, err := pr.ProviderRunner.Start()
+	SharedProviderStarts.WithLabelValues(Result(err)).Inc()
+	SharedProviderStartDuration.Observe(time.Since(start).Seconds())
+	return c, err
+}
+
+type workspaceKind struct {
+	group string
+	kind  string
+}
+
+// WorkspaceStore wraps a terraform.WorkspaceStore and keeps count of the
+// workspaces it holds per managed resource kind.
+type WorkspaceStore struct {
+	*terraform.WorkspaceStore
+
+	mu    sync.Mutex
+	kinds map[types.UID]workspaceKind
+}
+
+// NewWorkspaceStore returns a new WorkspaceStore wrapping the given store.
+func NewWorkspaceStore(ws *terraform.WorkspaceStore) *WorkspaceStore {
+	return &WorkspaceStore{
+		WorkspaceStore: ws,
+		kinds:          map[types.UID]workspaceKind{},
+	}
+}
+
+// Workspace returns the workspace of the given resource from the underlying
+// store and counts it if it's seen for the first time.
+func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient, tr resource.Terraformed, ts terraform.Setup, cfg *config.Resource) (*terraform.Workspace, error) {
+	w, err := ws.WorkspaceStore.Workspace(ctx, c, tr, ts, cfg)
+	if w == nil {
+		return w, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if _, ok := ws.kinds[tr.GetUID()]; !ok {
+		k := workspaceKind{group: cfg.ShortGroup, kind: cfg.Kind}
+		ws.kinds[tr.GetUID()] = k
+		TerraformWorkspaces.WithLabelValues(k.group, k.kind).Inc()
+	}
+	return w, err
+}
+
+// Remove removes the workspace of the given object from the underlying store
+// and stops counting it.
+func (ws *WorkspaceStore) Remove(obj xpresource.Object) error {
+	if err := ws.WorkspaceStore.Remove(obj); err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if k, ok := ws.kinds[obj.GetUID()]; ok {
+		delete(ws.kinds, obj.GetUID())
+		TerraformWorkspaces.WithLabelValues(k.group, k.kind).Dec()
+	}
+	return nil
+}
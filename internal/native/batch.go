@@ -0,0 +1,124 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+)
+
+const (
+	batchListed  = "listed"
+	batchCached  = "cached"
+	batchMissing = "missing"
+)
+
+// batchKey identifies the results of a batched list call.
+type batchKey struct {
+	// providerConfig is the name of the ProviderConfig the call is made
+	// with, which stands for the account.
+	providerConfig string
+	region         string
+	kind           string
+	// scope narrows the call down for the kinds that cannot be listed
+	// across an account, such as the hosted zone of the records.
+	scope string
+}
+
+// batch holds the results of a list call.
+type batch struct {
+	done    chan struct{}
+	expires time.Time
+	items   any
+	err     error
+}
+
+// A batcher makes a single paginated list call per account, region and kind
+// in a poll window and serves its results to the individual observations
+// until the window expires. Concurrent observations wait for the same call.
+type batcher struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	batches map[batchKey]*batch
+}
+
+func newBatcher(window time.Duration) *batcher {
+	return &batcher{
+		window:  window,
+		now:     time.Now,
+		batches: map[batchKey]*batch{},
+	}
+}
+
+// get returns the results of the list call with the given key, calling list
+// if there are none of the current window. Failed calls are not cached.
+func (b *batcher) get(ctx context.Context, key batchKey, list func(ctx context.Context) (any, error)) (any, error) {
+	b.mu.Lock()
+	bt, ok := b.batches[key]
+	if ok && b.expired(bt) {
+		ok = false
+	}
+	if ok {
+		b.mu.Unlock()
+		select {
+		case <-bt.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		metrics.NativeBatchedObservations.WithLabelValues(key.kind, batchCached).Inc()
+		return bt.items, bt.err
+	}
+	bt = &batch{done: make(chan struct{})}
+	b.batches[key] = bt
+	b.mu.Unlock()
+
+	bt.items, bt.err = list(ctx)
+	bt.expires = b.now().Add(b.window)
+	close(bt.done)
+	if bt.err != nil {
+		b.forget(key, bt)
+	}
+	metrics.NativeBatchedObservations.WithLabelValues(key.kind, batchListed).Inc()
+	return bt.items, bt.err
+}
+
+// expired returns true if the given batch was listed before the current
+// window. It must be called with the lock held.
+func (b *batcher) expired(bt *batch) bool {
+	select {
+	case <-bt.done:
+		return !b.now().Before(bt.expires)
+	default:
+		return false
+	}
+}
+
+// invalidate drops the results of the list call with the given key, so that
+// the changes made to the external resources are observed.
+func (b *batcher) invalidate(key batchKey) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.batches, key)
+}
+
+// batchMissed records an observation of an external resource that was
+// missing from the results of a list call, which is then observed on its own
+// since it might have been created after the call.
+func batchMissed(key batchKey) {
+	metrics.NativeBatchedObservations.WithLabelValues(key.kind, batchMissing).Inc()
+}
+
+func (b *batcher) forget(key batchKey, bt *batch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.batches[key] == bt {
+		delete(b.batches, key)
+	}
+}
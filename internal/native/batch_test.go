@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestBatcherGet(t *testing.T) {
+	errBoom := errors.New("boom")
+	key := batchKey{providerConfig: "default", region: "us-east-1", kind: "SecurityGroupRule"}
+	start := time.Unix(0, 0)
+
+	type call struct {
+		// after is the time since the first call.
+		after      time.Duration
+		invalidate bool
+		err        error
+	}
+	type want struct {
+		items []any
+		err   error
+		lists int
+	}
+	cases := map[string]struct {
+		reason string
+		calls  []call
+		want   want
+	}{
+		"Cached": {
+			reason: "The results of a list call should be served until the window expires.",
+			calls:  []call{{}, {after: 30 * time.Second}, {after: 59 * time.Second}},
+			want:   want{items: []any{1, 1, 1}, err: nil, lists: 1},
+		},
+		"Expired": {
+			reason: "The list call should be made again once the window expires.",
+			calls:  []call{{}, {after: time.Minute}, {after: 90 * time.Second}},
+			want:   want{items: []any{1, 2, 2}, lists: 2},
+		},
+		"Invalidated": {
+			reason: "The list call should be made again once its results are invalidated.",
+			calls:  []call{{}, {after: time.Second, invalidate: true}},
+			want:   want{items: []any{1, 2}, lists: 2},
+		},
+		"Failed": {
+			reason: "The failed list calls should not be cached.",
+			calls:  []call{{err: errBoom}, {after: time.Second}},
+			want:   want{items: []any{nil, 2}, err: errBoom, lists: 2},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := newBatcher(time.Minute)
+			lists := 0
+			var items []any
+			var lastErr error
+			for _, c := range tc.calls {
+				b.now = func() time.Time { return start.Add(c.after) }
+				if c.invalidate {
+					b.invalidate(key)
+				}
+				got, err := b.get(context.Background(), key, func(_ context.Context) (any, error) {
+					lists++
+					if c.err != nil {
+						return nil, c.err
+					}
+					return lists, nil
+				})
+				if err != nil {
+					lastErr = err
+				}
+				items = append(items, got)
+			}
+			if diff := cmp.Diff(tc.want.items, items); diff != "" {
+				t.Errorf("\n%s\nget(...): -want items, +got items:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, lastErr, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.lists, lists); diff != "" {
+				t.Errorf("\n%s\nget(...): -want list calls, +got list calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+)
+
+const (
+	errGetBucketPolicy    = "cannot get the bucket policy"
+	errPutBucketPolicy    = "cannot put the bucket policy"
+	errDeleteBucketPolicy = "cannot delete the bucket policy"
+)
+
+// bucketPolicyAPI is the part of the S3 API the BucketPolicy client uses.
+type bucketPolicyAPI interface {
+	GetBucketPolicy(ctx context.Context, in *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	PutBucketPolicy(ctx context.Context, in *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error)
+	DeleteBucketPolicy(ctx context.Context, in *s3.DeleteBucketPolicyInput, opts ...func(*s3.Options)) (*s3.DeleteBucketPolicyOutput, error)
+}
+
+type bucketPolicyConnector struct {
+	kube        client.Client
+	newClientFn func(cfg aws.Config) bucketPolicyAPI
+}
+
+func newBucketPolicyConnector(kube client.Client) *bucketPolicyConnector {
+	return &bucketPolicyConnector{
+		kube: kube,
+		newClientFn: func(cfg aws.Config) bucketPolicyAPI {
+			return s3.NewFromConfig(cfg)
+		},
+	}
+}
+
+func (c *bucketPolicyConnector) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	cfg, err := clients.GetAWSConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &bucketPolicy{client: c.newClientFn(*cfg)}, nil
+}
+
+// bucketPolicy manages the policies of S3 buckets like the
+// aws_s3_bucket_policy Terraform resource. The ID of a bucket policy is the
+// name of its bucket.
+type bucketPolicy struct {
+	client bucketPolicyAPI
+}
+
+func (e *bucketPolicy) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.BucketPolicy)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	out, err := e.client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: aws.String(id)})
+	if isErrorCode(err, "NoSuchBucket", "NoSuchBucketPolicy") {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetBucketPolicy)
+	}
+	// Like the Terraform provider, the desired policy is kept in the state
+	// if it is equivalent to the observed one.
+	desired, policy := aws.ToString(cr.Spec.ForProvider.Policy), aws.ToString(out.Policy)
+	equivalent := policiesEquivalent(desired, policy)
+	if equivalent {
+		policy = desired
+	}
+	return observed(cr, map[string]any{
+		"id":     id,
+		"bucket": id,
+		"policy": policy,
+	}, equivalent && id == aws.ToString(cr.Spec.ForProvider.Bucket))
+}
+
+func (e *bucketPolicy) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.BucketPolicy)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errUnexpectedObject)
+	}
+	if err := e.put(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	meta.SetExternalName(cr, aws.ToString(cr.Spec.ForProvider.Bucket))
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+func (e *bucketPolicy) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.BucketPolicy)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errUnexpectedObject)
+	}
+	// The policy of the previous bucket is replaced like Terraform replaces
+	// the resource.
+	bucket := aws.ToString(cr.Spec.ForProvider.Bucket)
+	if id := meta.GetExternalName(cr); id != bucket {
+		if err := e.delete(ctx, id); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		meta.SetExternalName(cr, bucket)
+	}
+	return managed.ExternalUpdate{}, e.put(ctx, cr)
+}
+
+func (e *bucketPolicy) put(ctx context.Context, cr *v1beta1.BucketPolicy) error {
+	_, err := e.client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+		Bucket: cr.Spec.ForProvider.Bucket,
+		Policy: cr.Spec.ForProvider.Policy,
+	})
+	return errors.Wrap(err, errPutBucketPolicy)
+}
+
+func (e *bucketPolicy) Delete(ctx context.Context, mg xpresource.Managed) error {
+	return e.delete(ctx, meta.GetExternalName(mg))
+}
+
+func (e *bucketPolicy) delete(ctx context.Context, bucket string) error {
+	_, err := e.client.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{Bucket: aws.String(bucket)})
+	if isErrorCode(err, "NoSuchBucket", "NoSuchBucketPolicy") {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteBucketPolicy)
+}
+
+// policiesEquivalent returns true if the given JSON policy documents are
+// equivalent. Like in the Terraform provider, the order of the elements of
+// the string arrays is ignored and a single element array is equivalent to
+// its element.
+func policiesEquivalent(a, b string) bool {
+	var pa, pb any
+	if err := json.Unmarshal([]byte(a), &pa); err != nil {
+		return a == b
+	}
+	if err := json.Unmarshal([]byte(b), &pb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizePolicy(pa), normalizePolicy(pb))
+}
+
+func normalizePolicy(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = normalizePolicy(e)
+		}
+		return t
+	case []any:
+		if len(t) == 1 {
+			return normalizePolicy(t[0])
+		}
+		strs := make([]string, 0, len(t))
+		for i, e := range t {
+			t[i] = normalizePolicy(e)
+			if s, ok := t[i].(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		if len(strs) != len(t) {
+			return t
+		}
+		sort.Strings(strs)
+		return strs
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+)
+
+const (
+	policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:root"]},"Action":["s3:GetObject","s3:ListBucket"],"Resource":["arn:aws:s3:::bucket","arn:aws:s3:::bucket/*"]}]}`
+	// normalizedPolicy is the policy as S3 returns it.
+	normalizedPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":["s3:ListBucket","s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*","arn:aws:s3:::bucket"]}]}`
+)
+
+// fakeS3 is a set of buckets whose policies are normalized by S3.
+type fakeS3 struct {
+	// policies are the policies of the buckets.
+	policies map[string]string
+}
+
+func (f *fakeS3) GetBucketPolicy(_ context.Context, in *s3.GetBucketPolicyInput, _ ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	p, ok := f.policies[aws.ToString(in.Bucket)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchBucketPolicy"}
+	}
+	return &s3.GetBucketPolicyOutput{Policy: aws.String(p)}, nil
+}
+
+func (f *fakeS3) PutBucketPolicy(_ context.Context, in *s3.PutBucketPolicyInput, _ ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+	f.policies[aws.ToString(in.Bucket)] = normalizedPolicy
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (f *fakeS3) DeleteBucketPolicy(_ context.Context, in *s3.DeleteBucketPolicyInput, _ ...func(*s3.Options)) (*s3.DeleteBucketPolicyOutput, error) {
+	delete(f.policies, aws.ToString(in.Bucket))
+	return &s3.DeleteBucketPolicyOutput{}, nil
+}
+
+func newBucketPolicy() *v1beta1.BucketPolicy {
+	return &v1beta1.BucketPolicy{Spec: v1beta1.BucketPolicySpec{ForProvider: v1beta1.BucketPolicyParameters{
+		Bucket: aws.String("bucket"),
+		Policy: aws.String(policy),
+	}}}
+}
+
+func TestBucketPolicyEquivalence(t *testing.T) {
+	reason := "A policy normalized by S3 should be observed like Terraform observes it."
+	e := &bucketPolicy{client: &fakeS3{policies: map[string]string{}}}
+
+	native := newBucketPolicy()
+	if _, err := e.Create(context.Background(), native); err != nil {
+		t.Fatalf("\n%s\nCreate(...): %v", reason, err)
+	}
+	obs, err := e.Observe(context.Background(), native)
+	if err != nil {
+		t.Fatalf("\n%s\nObserve(...): %v", reason, err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", reason, diff)
+	}
+
+	terraform := newBucketPolicy()
+	terraformObserved(t, terraform, map[string]any{
+		"id":     "bucket",
+		"bucket": "bucket",
+		"policy": policy,
+	})
+	equivalent(t, reason, terraform, native)
+}
+
+func TestPoliciesEquivalent(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a, b   string
+		want   bool
+	}{
+		"Normalized": {
+			reason: "A policy should be equivalent to the policy normalized by S3.",
+			a:      policy,
+			b:      normalizedPolicy,
+			want:   true,
+		},
+		"Different": {
+			reason: "Policies with different actions should not be equivalent.",
+			a:      policy,
+			b:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*","arn:aws:s3:::bucket"]}]}`,
+			want:   false,
+		},
+		"Invalid": {
+			reason: "An invalid policy should not be equivalent to a valid one.",
+			a:      policy,
+			b:      "{",
+			want:   false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := policiesEquivalent(tc.a, tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npoliciesEquivalent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
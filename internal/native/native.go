@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package native contains external clients that manage the external resources
+// of some of the most frequently reconciled kinds with the AWS SDK directly
+// instead of running Terraform. They keep the schemas and external names of
+// the Terraform external clients, so that the managed resources can be
+// switched between them.
+package native
+
+import (
+	"time"
+
+	"github.com/aws/smithy-go"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+const (
+	errUnexpectedObject = "managed resource is not of the expected kind"
+	errSetObservation   = "cannot set observation"
+)
+
+// An Option configures the native external connecters.
+type Option func(*options)
+
+type options struct {
+	batchWindow time.Duration
+}
+
+// WithBatchedObservation makes the SecurityGroupRule and Record clients
+// observe their external resources with a single list call per account,
+// region and kind in the given window, instead of a call per managed
+// resource.
+func WithBatchedObservation(window time.Duration) Option {
+	return func(o *options) {
+		o.batchWindow = window
+	}
+}
+
+// Connecters returns the native external connecters by the names of the
+// Terraform resources of their kinds.
+func Connecters(kube client.Client, opts ...Option) map[string]managed.ExternalConnecter {
+	o := &options{}
+	for _, f := range opts {
+		f(o)
+	}
+	var b *batcher
+	if o.batchWindow > 0 {
+		b = newBatcher(o.batchWindow)
+	}
+	return map[string]managed.ExternalConnecter{
+		"aws_security_group_rule":        newSecurityGroupRuleConnector(kube, b),
+		"aws_route53_record":             newRecordConnector(kube, b),
+		"aws_iam_role_policy_attachment": newRolePolicyAttachmentConnector(kube),
+		"aws_s3_bucket_policy":           newBucketPolicyConnector(kube),
+	}
+}
+
+// observed records the given Terraform state attributes of an existing
+// external resource in the status of its managed resource, like the Terraform
+// external clients do.
+func observed(tr resource.Terraformed, attrs map[string]any, upToDate bool) (managed.ExternalObservation, error) {
+	if err := tr.SetObservation(attrs); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errSetObservation)
+	}
+	tr.SetConditions(xpv1.Available())
+	resource.SetUpToDateCondition(tr, upToDate)
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: upToDate,
+	}, nil
+}
+
+// isErrorCode returns true if the given error is an AWS API error with one of
+// the given codes.
+func isErrorCode(err error, codes ...string) bool {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return false
+	}
+	for _, c := range codes {
+		if ae.ErrorCode() == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+// terraformObserved sets the given managed resource up as the Terraform
+// external client leaves it after observing an up to date external resource
+// with the given Terraform state attributes.
+func terraformObserved(t *testing.T, tr resource.Terraformed, attrs map[string]any) {
+	t.Helper()
+	meta.SetExternalName(tr, attrs["id"].(string))
+	if err := tr.SetObservation(attrs); err != nil {
+		t.Fatal(err)
+	}
+	tr.SetConditions(xpv1.Available())
+	resource.SetUpToDateCondition(tr, true)
+}
+
+// equivalent reports the differences between a managed resource observed by
+// a native client and the same managed resource observed by the Terraform
+// external client.
+func equivalent(t *testing.T, reason string, terraform, native resource.Terraformed) {
+	t.Helper()
+	if diff := cmp.Diff(terraform, native, test.EquateConditions(), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("\n%s\n-want Terraform observation, +got native observation:\n%s", reason, diff)
+	}
+}
@@ -0,0 +1,495 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/route53/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+)
+
+const (
+	errGetHostedZone  = "cannot get the hosted zone of the record"
+	errListRecordSets = "cannot list the record sets of the hosted zone"
+	errChangeRecord   = "cannot change the record"
+)
+
+// recordTypes matches the types of the records, which tells them apart from
+// the set identifiers in the record IDs.
+var recordTypes = regexp.MustCompile(`^(A|AAAA|CAA|CNAME|DS|MX|NAPTR|NS|PTR|SOA|SPF|SRV|TXT)$`)
+
+// recordAPI is the part of the Route 53 API the Record client uses.
+type recordAPI interface {
+	GetHostedZone(ctx context.Context, in *route53.GetHostedZoneInput, opts ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error)
+	ListResourceRecordSets(ctx context.Context, in *route53.ListResourceRecordSetsInput, opts ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+	ChangeResourceRecordSets(ctx context.Context, in *route53.ChangeResourceRecordSetsInput, opts ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error)
+}
+
+type recordConnector struct {
+	kube        client.Client
+	batcher     *batcher
+	newClientFn func(cfg aws.Config) recordAPI
+}
+
+func newRecordConnector(kube client.Client, b *batcher) *recordConnector {
+	return &recordConnector{
+		kube:    kube,
+		batcher: b,
+		newClientFn: func(cfg aws.Config) recordAPI {
+			return route53.NewFromConfig(cfg)
+		},
+	}
+}
+
+func (c *recordConnector) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	cfg, err := clients.GetAWSConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &record{
+		client:  c.newClientFn(*cfg),
+		batcher: c.batcher,
+		key:     batchKey{providerConfig: mg.GetProviderConfigReference().Name, region: cfg.Region, kind: v1beta1.Record_Kind},
+	}, nil
+}
+
+// record manages the records of Route 53 hosted zones like the
+// aws_route53_record Terraform resource. The ID of a record is made of its
+// hosted zone, name, type and set identifier, if any.
+type record struct {
+	client recordAPI
+	// batcher lists all the record sets of a hosted zone at once if it is
+	// set.
+	batcher *batcher
+	key     batchKey
+}
+
+// recordID is the identity of a record.
+type recordID struct {
+	zone, name, typ, set string
+}
+
+func (id recordID) String() string {
+	parts := []string{id.zone, strings.ToLower(id.name), id.typ}
+	if id.set != "" {
+		parts = append(parts, id.set)
+	}
+	return strings.Join(parts, "_")
+}
+
+// parseRecordID parses the given record ID like the Terraform provider does.
+// The names might contain underscores, so the type and the set identifier
+// are parsed from the end.
+func parseRecordID(s string) recordID {
+	var id recordID
+	zone, rest, ok := strings.Cut(s, "_")
+	if !ok {
+		return id
+	}
+	id.zone = zone
+	if i := strings.LastIndex(rest, "_"); i != -1 {
+		id.name, id.typ = rest[:i], rest[i+1:]
+		if !recordTypes.MatchString(id.typ) {
+			id.set, id.typ = id.typ, ""
+			if i := strings.LastIndex(id.name, "_"); i != -1 {
+				id.name, id.typ = id.name[:i], id.name[i+1:]
+			}
+		}
+	}
+	id.name = strings.TrimSuffix(id.name, ".")
+	return id
+}
+
+func specRecordID(p v1beta1.RecordParameters) recordID {
+	return recordID{
+		zone: aws.ToString(p.ZoneID),
+		name: aws.ToString(p.Name),
+		typ:  aws.ToString(p.Type),
+		set:  aws.ToString(p.SetIdentifier),
+	}
+}
+
+func (e *record) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.Record)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+	}
+	ext := meta.GetExternalName(cr)
+	if ext == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	id := parseRecordID(ext)
+	zoneName, err := e.zoneName(ctx, id.zone)
+	if err != nil || zoneName == "" {
+		return managed.ExternalObservation{ResourceExists: false}, err
+	}
+	rrs, err := e.find(ctx, id, zoneName)
+	if err != nil || rrs == nil {
+		return managed.ExternalObservation{ResourceExists: false}, err
+	}
+	p := cr.Spec.ForProvider
+	desired := expandRecordSet(p, zoneName)
+	// A record whose identity changed is replaced by an update.
+	upToDate := specRecordID(p).String() == ext && recordSetsEqual(desired, *rrs)
+	return observed(cr, map[string]any{
+		"id":      ext,
+		"zone_id": id.zone,
+		"name":    aws.ToString(p.Name),
+		"type":    id.typ,
+		"fqdn":    expandRecordName(id.name, zoneName),
+	}, upToDate)
+}
+
+// zoneName returns the name of the given hosted zone, or an empty string if it
+// does not exist.
+func (e *record) zoneName(ctx context.Context, zone string) (string, error) {
+	if e.batcher != nil {
+		zr, err := e.zoneRecords(ctx, zone)
+		if err != nil {
+			return "", err
+		}
+		if zr.name != "" {
+			return zr.name, nil
+		}
+		batchMissed(e.zoneKey(zone))
+	}
+	return e.getZoneName(ctx, zone)
+}
+
+func (e *record) getZoneName(ctx context.Context, zone string) (string, error) {
+	out, err := e.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(zone)})
+	if isErrorCode(err, "NoSuchHostedZone") {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, errGetHostedZone)
+	}
+	return aws.ToString(out.HostedZone.Name), nil
+}
+
+// lookup returns the record set with the given identity, if it and its hosted
+// zone exist.
+func (e *record) lookup(ctx context.Context, id recordID) (*types.ResourceRecordSet, error) {
+	zoneName, err := e.zoneName(ctx, id.zone)
+	if err != nil || zoneName == "" {
+		return nil, err
+	}
+	return e.find(ctx, id, zoneName)
+}
+
+// find returns the record set with the given identity, if any.
+func (e *record) find(ctx context.Context, id recordID, zoneName string) (*types.ResourceRecordSet, error) {
+	name := expandRecordName(id.name, zoneName)
+	if e.batcher != nil {
+		zr, err := e.zoneRecords(ctx, id.zone)
+		if err != nil {
+			return nil, err
+		}
+		if rrs, ok := zr.sets[recordSetKey(name, id.typ, id.set)]; ok {
+			return &rrs, nil
+		}
+		batchMissed(e.zoneKey(id.zone))
+	}
+	in := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(id.zone),
+		StartRecordName: aws.String(name),
+		StartRecordType: types.RRType(id.typ),
+	}
+	if id.set != "" {
+		in.StartRecordIdentifier = aws.String(id.set)
+	}
+	for {
+		out, err := e.client.ListResourceRecordSets(ctx, in)
+		if isErrorCode(err, "NoSuchHostedZone") {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errListRecordSets)
+		}
+		for i := range out.ResourceRecordSets {
+			rrs := &out.ResourceRecordSets[i]
+			// The record sets are listed in order, starting with the
+			// one that is looked for if it exists.
+			if cleanRecordName(aws.ToString(rrs.Name)) != name || string(rrs.Type) != id.typ {
+				return nil, nil
+			}
+			if aws.ToString(rrs.SetIdentifier) == id.set {
+				return rrs, nil
+			}
+		}
+		if !out.IsTruncated {
+			return nil, nil
+		}
+		in.StartRecordName, in.StartRecordType, in.StartRecordIdentifier = out.NextRecordName, out.NextRecordType, out.NextRecordIdentifier
+	}
+}
+
+// zoneRecords are the name and the record sets of a hosted zone, which are
+// listed at once.
+type zoneRecords struct {
+	name string
+	// sets are the record sets by their names, types and set identifiers.
+	sets map[string]types.ResourceRecordSet
+}
+
+// zoneRecords returns the name and the record sets of the given hosted zone
+// listed in the current window. Both are empty if it does not exist.
+func (e *record) zoneRecords(ctx context.Context, zone string) (*zoneRecords, error) {
+	zr, err := e.batcher.get(ctx, e.zoneKey(zone), func(ctx context.Context) (any, error) {
+		return e.listZoneRecords(ctx, zone)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zr.(*zoneRecords), nil
+}
+
+func (e *record) listZoneRecords(ctx context.Context, zone string) (*zoneRecords, error) {
+	name, err := e.getZoneName(ctx, zone)
+	if err != nil || name == "" {
+		return &zoneRecords{}, err
+	}
+	zr := &zoneRecords{name: name, sets: map[string]types.ResourceRecordSet{}}
+	in := &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(zone)}
+	for {
+		out, err := e.client.ListResourceRecordSets(ctx, in)
+		if isErrorCode(err, "NoSuchHostedZone") {
+			return &zoneRecords{}, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errListRecordSets)
+		}
+		for _, rrs := range out.ResourceRecordSets {
+			zr.sets[recordSetKey(cleanRecordName(aws.ToString(rrs.Name)), string(rrs.Type), aws.ToString(rrs.SetIdentifier))] = rrs
+		}
+		if !out.IsTruncated {
+			return zr, nil
+		}
+		in.StartRecordName, in.StartRecordType, in.StartRecordIdentifier = out.NextRecordName, out.NextRecordType, out.NextRecordIdentifier
+	}
+}
+
+// zoneKey returns the key of the batched list calls of the given hosted zone.
+func (e *record) zoneKey(zone string) batchKey {
+	k := e.key
+	k.scope = zone
+	return k
+}
+
+func recordSetKey(name, typ, set string) string {
+	return strings.Join([]string{strings.ToLower(name), typ, set}, "_")
+}
+
+func (e *record) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.Record)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errUnexpectedObject)
+	}
+	p := cr.Spec.ForProvider
+	zoneName, err := e.zoneName(ctx, aws.ToString(p.ZoneID))
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	// Like in the Terraform provider, existing records are overwritten
+	// unless it is disallowed.
+	action := types.ChangeActionUpsert
+	if p.AllowOverwrite != nil && !*p.AllowOverwrite {
+		action = types.ChangeActionCreate
+	}
+	rrs := expandRecordSet(p, zoneName)
+	if err := e.change(ctx, p.ZoneID, types.Change{Action: action, ResourceRecordSet: &rrs}); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	meta.SetExternalName(cr, specRecordID(p).String())
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+func (e *record) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.Record)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errUnexpectedObject)
+	}
+	p := cr.Spec.ForProvider
+	zoneName, err := e.zoneName(ctx, aws.ToString(p.ZoneID))
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	rrs := expandRecordSet(p, zoneName)
+	changes := []types.Change{{Action: types.ChangeActionUpsert, ResourceRecordSet: &rrs}}
+	// Like in the Terraform provider, the previous record is deleted if the
+	// identity of the record changed, in the same batch if it is in the
+	// same hosted zone.
+	ext, id := meta.GetExternalName(cr), specRecordID(p)
+	if old := parseRecordID(ext); ext != id.String() {
+		prev, err := e.lookup(ctx, old)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		del := types.Change{Action: types.ChangeActionDelete, ResourceRecordSet: prev}
+		switch {
+		case prev == nil:
+		case old.zone == id.zone:
+			changes = append([]types.Change{del}, changes...)
+		default:
+			if err := e.change(ctx, aws.String(old.zone), del); err != nil {
+				return managed.ExternalUpdate{}, err
+			}
+		}
+	}
+	if err := e.change(ctx, p.ZoneID, changes...); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	meta.SetExternalName(cr, id.String())
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *record) Delete(ctx context.Context, mg xpresource.Managed) error {
+	id := parseRecordID(meta.GetExternalName(mg))
+	rrs, err := e.lookup(ctx, id)
+	if err != nil || rrs == nil {
+		return err
+	}
+	err = e.change(ctx, aws.String(id.zone), types.Change{Action: types.ChangeActionDelete, ResourceRecordSet: rrs})
+	if isErrorCode(err, "InvalidChangeBatch") {
+		// The record was deleted since it was found.
+		return nil
+	}
+	return err
+}
+
+func (e *record) change(ctx context.Context, zone *string, changes ...types.Change) error {
+	_, err := e.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: zone,
+		ChangeBatch:  &types.ChangeBatch{Changes: changes},
+	})
+	if e.batcher != nil {
+		// The listed record sets are dropped, so that the changes are
+		// observed.
+		e.batcher.invalidate(e.zoneKey(aws.ToString(zone)))
+	}
+	return errors.Wrap(err, errChangeRecord)
+}
+
+// cleanRecordName returns the given name as returned by Route 53 without the
+// trailing dot and with the escaped wildcard unescaped.
+func cleanRecordName(name string) string {
+	return strings.Replace(strings.TrimSuffix(name, "."), `\052`, "*", 1)
+}
+
+// expandRecordName returns the fully qualified name of the record with the
+// given name in the zone with the given name.
+func expandRecordName(name, zone string) string {
+	rn := strings.ToLower(strings.TrimSuffix(name, "."))
+	zone = strings.TrimSuffix(zone, ".")
+	if !strings.HasSuffix(rn, zone) {
+		if len(name) == 0 {
+			return zone
+		}
+		return rn + "." + zone
+	}
+	return rn
+}
+
+// expandRecordSet returns the record set of the given record.
+func expandRecordSet(p v1beta1.RecordParameters, zoneName string) types.ResourceRecordSet {
+	rrs := types.ResourceRecordSet{
+		Name:             aws.String(expandRecordName(aws.ToString(p.Name), zoneName)),
+		Type:             types.RRType(aws.ToString(p.Type)),
+		SetIdentifier:    p.SetIdentifier,
+		HealthCheckId:    p.HealthCheckID,
+		MultiValueAnswer: p.MultivalueAnswerRoutingPolicy,
+	}
+	if p.TTL != nil {
+		rrs.TTL = aws.Int64(int64(*p.TTL))
+	}
+	for _, r := range p.Records {
+		rrs.ResourceRecords = append(rrs.ResourceRecords, types.ResourceRecord{Value: r})
+	}
+	if len(p.Alias) > 0 {
+		a := p.Alias[0]
+		rrs.AliasTarget = &types.AliasTarget{
+			DNSName:              a.Name,
+			HostedZoneId:         a.ZoneID,
+			EvaluateTargetHealth: aws.ToBool(a.EvaluateTargetHealth),
+		}
+	}
+	if len(p.FailoverRoutingPolicy) > 0 {
+		rrs.Failover = types.ResourceRecordSetFailover(aws.ToString(p.FailoverRoutingPolicy[0].Type))
+	}
+	if len(p.GeolocationRoutingPolicy) > 0 {
+		g := p.GeolocationRoutingPolicy[0]
+		rrs.GeoLocation = &types.GeoLocation{ContinentCode: g.Continent, CountryCode: g.Country, SubdivisionCode: g.Subdivision}
+	}
+	if len(p.LatencyRoutingPolicy) > 0 {
+		rrs.Region = types.ResourceRecordSetRegion(aws.ToString(p.LatencyRoutingPolicy[0].Region))
+	}
+	if len(p.WeightedRoutingPolicy) > 0 {
+		rrs.Weight = aws.Int64(int64(aws.ToFloat64(p.WeightedRoutingPolicy[0].Weight)))
+	}
+	return rrs
+}
+
+// comparableRecordSet is the part of a record set that is compared to tell
+// whether a record is up to date.
+type comparableRecordSet struct {
+	ttl              int64
+	records          string
+	aliasName        string
+	aliasZone        string
+	aliasEvaluate    bool
+	failover         string
+	continent        string
+	country          string
+	subdivision      string
+	region           string
+	weight           int64
+	healthCheck      string
+	multiValueAnswer bool
+}
+
+func toComparable(rrs types.ResourceRecordSet) comparableRecordSet {
+	c := comparableRecordSet{
+		ttl:              aws.ToInt64(rrs.TTL),
+		failover:         string(rrs.Failover),
+		region:           string(rrs.Region),
+		weight:           aws.ToInt64(rrs.Weight),
+		healthCheck:      aws.ToString(rrs.HealthCheckId),
+		multiValueAnswer: aws.ToBool(rrs.MultiValueAnswer),
+	}
+	// The records are a set.
+	records := make([]string, len(rrs.ResourceRecords))
+	for i, r := range rrs.ResourceRecords {
+		records[i] = aws.ToString(r.Value)
+	}
+	sort.Strings(records)
+	c.records = strings.Join(records, "\n")
+	if a := rrs.AliasTarget; a != nil {
+		c.aliasName = strings.ToLower(cleanRecordName(aws.ToString(a.DNSName)))
+		c.aliasZone = aws.ToString(a.HostedZoneId)
+		c.aliasEvaluate = a.EvaluateTargetHealth
+	}
+	if g := rrs.GeoLocation; g != nil {
+		c.continent, c.country, c.subdivision = aws.ToString(g.ContinentCode), aws.ToString(g.CountryCode), aws.ToString(g.SubdivisionCode)
+	}
+	return c
+}
+
+// recordSetsEqual returns true if the given record sets are equal.
+func recordSetsEqual(a, b types.ResourceRecordSet) bool {
+	return toComparable(a) == toComparable(b)
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/provider-aws/apis/route53/v1beta1"
+)
+
+// fakeRoute53 is a set of hosted zones whose record sets are changed.
+type fakeRoute53 struct {
+	// zones are the names of the hosted zones by their IDs.
+	zones map[string]string
+	sets  map[string][]types.ResourceRecordSet
+	// batches are the changes of the record sets by their hosted zones.
+	batches map[string][][]types.Change
+	// calls is the number of calls to get the hosted zones and list their
+	// record sets.
+	calls int
+}
+
+func newFakeRoute53(zones map[string]string) *fakeRoute53 {
+	return &fakeRoute53{zones: zones, sets: map[string][]types.ResourceRecordSet{}, batches: map[string][][]types.Change{}}
+}
+
+func (f *fakeRoute53) GetHostedZone(_ context.Context, in *route53.GetHostedZoneInput, _ ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error) {
+	f.calls++
+	name, ok := f.zones[aws.ToString(in.Id)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchHostedZone"}
+	}
+	return &route53.GetHostedZoneOutput{HostedZone: &types.HostedZone{Id: in.Id, Name: aws.String(name)}}, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(_ context.Context, in *route53.ListResourceRecordSetsInput, _ ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	f.calls++
+	sets := f.sets[aws.ToString(in.HostedZoneId)]
+	if in.StartRecordName == nil {
+		return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: sets}, nil
+	}
+	for i, rrs := range sets {
+		if cleanRecordName(aws.ToString(rrs.Name)) == aws.ToString(in.StartRecordName) && rrs.Type == in.StartRecordType {
+			return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: sets[i:]}, nil
+		}
+	}
+	return &route53.ListResourceRecordSetsOutput{}, nil
+}
+
+func (f *fakeRoute53) ChangeResourceRecordSets(_ context.Context, in *route53.ChangeResourceRecordSetsInput, _ ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+	zone := aws.ToString(in.HostedZoneId)
+	f.batches[zone] = append(f.batches[zone], in.ChangeBatch.Changes)
+	for _, c := range in.ChangeBatch.Changes {
+		rrs := *c.ResourceRecordSet
+		rrs.Name = aws.String(cleanRecordName(aws.ToString(rrs.Name)) + ".")
+		var sets []types.ResourceRecordSet
+		for _, s := range f.sets[zone] {
+			if aws.ToString(s.Name) != aws.ToString(rrs.Name) || s.Type != rrs.Type || aws.ToString(s.SetIdentifier) != aws.ToString(rrs.SetIdentifier) {
+				sets = append(sets, s)
+			}
+		}
+		if c.Action != types.ChangeActionDelete {
+			sets = append(sets, rrs)
+		}
+		f.sets[zone] = sets
+	}
+	return &route53.ChangeResourceRecordSetsOutput{}, nil
+}
+
+func newRecord(p v1beta1.RecordParameters) *v1beta1.Record {
+	return &v1beta1.Record{Spec: v1beta1.RecordSpec{ForProvider: p}}
+}
+
+func TestRecordEquivalence(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		params v1beta1.RecordParameters
+		// attrs are the Terraform state attributes of the record.
+		attrs map[string]any
+	}{
+		"Simple": {
+			reason: "A simple record should be observed like Terraform observes it.",
+			params: v1beta1.RecordParameters{
+				ZoneID:  aws.String("Z123"),
+				Name:    aws.String("WWW"),
+				Type:    aws.String("A"),
+				TTL:     aws.Float64(300),
+				Records: []*string{aws.String("192.0.2.2"), aws.String("192.0.2.1")},
+			},
+			attrs: map[string]any{
+				"id":      "Z123_www_A",
+				"zone_id": "Z123",
+				"name":    "WWW",
+				"type":    "A",
+				"ttl":     300,
+				"records": []any{"192.0.2.1", "192.0.2.2"},
+				"fqdn":    "www.example.com",
+			},
+		},
+		"Weighted": {
+			reason: "A weighted record with a set identifier should be observed like Terraform observes it.",
+			params: v1beta1.RecordParameters{
+				ZoneID:                aws.String("Z123"),
+				Name:                  aws.String("api_v1.example.com"),
+				Type:                  aws.String("CNAME"),
+				TTL:                   aws.Float64(60),
+				Records:               []*string{aws.String("blue.example.com")},
+				SetIdentifier:         aws.String("blue"),
+				WeightedRoutingPolicy: []v1beta1.WeightedRoutingPolicyParameters{{Weight: aws.Float64(10)}},
+			},
+			attrs: map[string]any{
+				"id":                      "Z123_api_v1.example.com_CNAME_blue",
+				"zone_id":                 "Z123",
+				"name":                    "api_v1.example.com",
+				"type":                    "CNAME",
+				"ttl":                     60,
+				"records":                 []any{"blue.example.com"},
+				"set_identifier":          "blue",
+				"weighted_routing_policy": []any{map[string]any{"weight": 10}},
+				"fqdn":                    "api_v1.example.com",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &record{client: newFakeRoute53(map[string]string{"Z123": "example.com."})}
+
+			native := newRecord(tc.params)
+			if _, err := e.Create(context.Background(), native); err != nil {
+				t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+			}
+			obs, err := e.Observe(context.Background(), native)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			terraform := newRecord(tc.params)
+			terraformObserved(t, terraform, tc.attrs)
+			equivalent(t, tc.reason, terraform, native)
+		})
+	}
+}
+
+func TestRecordBatchedObserve(t *testing.T) {
+	names := []string{"www", "api", "mail"}
+	cases := map[string]struct {
+		reason string
+		zone   string
+		calls  int
+	}{
+		"Listed": {
+			reason: "The records of a hosted zone should be observed with a call to get it and a call to list its record sets.",
+			zone:   "Z123",
+			calls:  2,
+		},
+		"MissingZone": {
+			reason: "The records of a missing hosted zone should be looked up on their own.",
+			zone:   "Z456",
+			calls:  4,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeRoute53(map[string]string{"Z123": "example.com."})
+			for _, n := range names {
+				if _, err := (&record{client: api}).Create(context.Background(), newRecord(v1beta1.RecordParameters{
+					ZoneID:  aws.String("Z123"),
+					Name:    aws.String(n),
+					Type:    aws.String("A"),
+					TTL:     aws.Float64(300),
+					Records: []*string{aws.String("192.0.2.1")},
+				})); err != nil {
+					t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+				}
+			}
+			api.calls = 0
+			e := &record{client: api, batcher: newBatcher(time.Minute), key: batchKey{kind: v1beta1.Record_Kind}}
+			for _, n := range names {
+				cr := newRecord(v1beta1.RecordParameters{
+					ZoneID:  aws.String(tc.zone),
+					Name:    aws.String(n),
+					Type:    aws.String("A"),
+					TTL:     aws.Float64(300),
+					Records: []*string{aws.String("192.0.2.1")},
+				})
+				meta.SetExternalName(cr, tc.zone+"_"+n+"_A")
+				got, err := e.Observe(context.Background(), cr)
+				if err != nil {
+					t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+				}
+				want := managed.ExternalObservation{ResourceExists: tc.zone == "Z123", ResourceUpToDate: tc.zone == "Z123"}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.calls, api.calls); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordUpdate(t *testing.T) {
+	www := types.ResourceRecordSet{
+		Name:            aws.String("www.example.com"),
+		Type:            types.RRTypeA,
+		TTL:             aws.Int64(300),
+		ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+	}
+	web := www
+	web.Name = aws.String("web.example.com")
+
+	cases := map[string]struct {
+		reason string
+		zone   string
+		want   map[string][][]types.Change
+	}{
+		"SameZone": {
+			reason: "The previous record should be deleted in the same batch if the record was renamed.",
+			zone:   "Z123",
+			want: map[string][][]types.Change{
+				"Z123": {
+					{{Action: types.ChangeActionUpsert, ResourceRecordSet: &www}},
+					{{Action: types.ChangeActionDelete, ResourceRecordSet: &www}, {Action: types.ChangeActionUpsert, ResourceRecordSet: &web}},
+				},
+			},
+		},
+		"OtherZone": {
+			reason: "The previous record should be deleted in its own batch if the record moved to another hosted zone.",
+			zone:   "Z456",
+			want: map[string][][]types.Change{
+				"Z123": {
+					{{Action: types.ChangeActionUpsert, ResourceRecordSet: &www}},
+					{{Action: types.ChangeActionDelete, ResourceRecordSet: &www}},
+				},
+				"Z456": {
+					{{Action: types.ChangeActionUpsert, ResourceRecordSet: &web}},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeRoute53(map[string]string{"Z123": "example.com.", "Z456": "example.com."})
+			e := &record{client: api}
+			cr := newRecord(v1beta1.RecordParameters{
+				ZoneID:  aws.String("Z123"),
+				Name:    aws.String("www"),
+				Type:    aws.String("A"),
+				TTL:     aws.Float64(300),
+				Records: []*string{aws.String("192.0.2.1")},
+			})
+			if _, err := e.Create(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+			}
+			cr.Spec.ForProvider.ZoneID = aws.String(tc.zone)
+			cr.Spec.ForProvider.Name = aws.String("web")
+			if _, err := e.Update(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\nUpdate(...): %v", tc.reason, err)
+			}
+			// The deleted record set is the one Route 53 returns.
+			for _, batches := range api.batches {
+				for _, changes := range batches {
+					for _, c := range changes {
+						if c.Action == types.ChangeActionDelete {
+							c.ResourceRecordSet.Name = aws.String(cleanRecordName(aws.ToString(c.ResourceRecordSet.Name)))
+						}
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, api.batches, cmp.AllowUnexported(types.Change{}, types.ResourceRecordSet{}, types.ResourceRecord{})); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want changes, +got changes:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.zone+"_web_A", meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want external name, +got external name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseRecordID(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		id     string
+		want   recordID
+	}{
+		"Simple": {
+			reason: "The zone, name and type of a record should be parsed.",
+			id:     "Z123_www.example.com_A",
+			want:   recordID{zone: "Z123", name: "www.example.com", typ: "A"},
+		},
+		"SetIdentifier": {
+			reason: "The set identifier of a record should be parsed.",
+			id:     "Z123_www.example.com_CNAME_blue",
+			want:   recordID{zone: "Z123", name: "www.example.com", typ: "CNAME", set: "blue"},
+		},
+		"Underscores": {
+			reason: "The underscores of the name of a record should be kept.",
+			id:     "Z123__dmarc.example.com_TXT_blue",
+			want:   recordID{zone: "Z123", name: "_dmarc.example.com", typ: "TXT", set: "blue"},
+		},
+		"TrailingDot": {
+			reason: "The trailing dot of the name of a record should be removed.",
+			id:     "Z123_example.com._MX",
+			want:   recordID{zone: "Z123", name: "example.com", typ: "MX"},
+		},
+		"Invalid": {
+			reason: "An ID without any separator should not be parsed.",
+			id:     "Z123",
+			want:   recordID{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseRecordID(tc.id)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(recordID{})); diff != "" {
+				t.Errorf("\n%s\nparseRecordID(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
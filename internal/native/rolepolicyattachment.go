@@ -0,0 +1,212 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/iam/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+)
+
+const (
+	// iamRegion is the region of the global IAM endpoint.
+	iamRegion = "us-east-1"
+
+	// annotationKeyPolicyARN is the annotation that records the ARN of the
+	// policy a role policy attachment attached, which is not a part of its
+	// ID.
+	annotationKeyPolicyARN = "aws.upbound.io/attached-policy-arn"
+
+	errListAttachedPolicies = "cannot list the policies attached to the role"
+	errAttachRolePolicy     = "cannot attach the policy to the role"
+	errDetachRolePolicy     = "cannot detach the policy from the role"
+	errRoleChanged          = "cannot change the role of an existing role policy attachment with the native client"
+	errPolicyChanged        = "cannot change the policy of an existing role policy attachment with the native client"
+	errPolicyUnknown        = "cannot observe the role policy attachment with the native client since the policy it attached is not recorded in the " + annotationKeyPolicyARN + " annotation and its policy is not attached to the role"
+)
+
+// rolePolicyAttachmentAPI is the part of the IAM API the RolePolicyAttachment
+// client uses.
+type rolePolicyAttachmentAPI interface {
+	iam.ListAttachedRolePoliciesAPIClient
+	AttachRolePolicy(ctx context.Context, in *iam.AttachRolePolicyInput, opts ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error)
+	DetachRolePolicy(ctx context.Context, in *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)
+}
+
+type rolePolicyAttachmentConnector struct {
+	kube        client.Client
+	newClientFn func(cfg aws.Config) rolePolicyAttachmentAPI
+}
+
+func newRolePolicyAttachmentConnector(kube client.Client) *rolePolicyAttachmentConnector {
+	return &rolePolicyAttachmentConnector{
+		kube: kube,
+		newClientFn: func(cfg aws.Config) rolePolicyAttachmentAPI {
+			return iam.NewFromConfig(cfg)
+		},
+	}
+}
+
+func (c *rolePolicyAttachmentConnector) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	cfg, err := clients.GetAWSConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	// The role policy attachments have no region, but the IAM endpoint is
+	// resolved with one.
+	if cfg.Region == "" {
+		cfg.Region = iamRegion
+	}
+	return &rolePolicyAttachment{client: c.newClientFn(*cfg)}, nil
+}
+
+// rolePolicyAttachment manages the attachments of managed policies to IAM
+// roles like the aws_iam_role_policy_attachment Terraform resource.
+type rolePolicyAttachment struct {
+	client rolePolicyAttachmentAPI
+}
+
+func (e *rolePolicyAttachment) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.RolePolicyAttachment)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	role, arn := aws.ToString(cr.Spec.ForProvider.Role), aws.ToString(cr.Spec.ForProvider.PolicyArn)
+	// The IDs of the attachments are only prefixed with their roles, so a
+	// changed role is the only change of an attachment that can be told
+	// apart from a missing one.
+	if !strings.HasPrefix(id, role+"-") {
+		return managed.ExternalObservation{}, errors.New(errRoleChanged)
+	}
+	// Otherwise the policy attached to the role before the change would be
+	// left attached.
+	attachedARN, recorded := cr.GetAnnotations()[annotationKeyPolicyARN]
+	if recorded && attachedARN != arn {
+		return managed.ExternalObservation{}, errors.New(errPolicyChanged)
+	}
+	arns, err := e.attachedPolicies(ctx, role)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	_, attached := arns[arn]
+	switch {
+	case !attached && !recorded && len(arns) > 0:
+		// The attachments the native client did not create, such as the
+		// ones created by Terraform, do not record the policy they
+		// attached, which might be any of the policies attached to the
+		// role if the policy of the managed resource changed.
+		return managed.ExternalObservation{}, errors.New(errPolicyUnknown)
+	case !attached:
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if !recorded {
+		meta.AddAnnotations(cr, map[string]string{annotationKeyPolicyARN: arn})
+	}
+	obs, err := observed(cr, map[string]any{
+		"id":         id,
+		"role":       role,
+		"policy_arn": arn,
+	}, true)
+	// The managed resource is updated to persist the recorded policy.
+	obs.ResourceLateInitialized = !recorded
+	return obs, err
+}
+
+// attachedPolicies returns the ARNs of the policies attached to the given
+// role, which are none if the role does not exist.
+func (e *rolePolicyAttachment) attachedPolicies(ctx context.Context, role string) (map[string]struct{}, error) {
+	arns := map[string]struct{}{}
+	p := iam.NewListAttachedRolePoliciesPaginator(e.client, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(role)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if isErrorCode(err, "NoSuchEntity") {
+			return arns, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errListAttachedPolicies)
+		}
+		for _, ap := range page.AttachedPolicies {
+			arns[aws.ToString(ap.PolicyArn)] = struct{}{}
+		}
+	}
+	return arns, nil
+}
+
+func (e *rolePolicyAttachment) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.RolePolicyAttachment)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errUnexpectedObject)
+	}
+	role := aws.ToString(cr.Spec.ForProvider.Role)
+	if _, err := e.client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String(role),
+		PolicyArn: cr.Spec.ForProvider.PolicyArn,
+	}); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errAttachRolePolicy)
+	}
+	// The annotations are persisted along with the external name.
+	meta.AddAnnotations(cr, map[string]string{annotationKeyPolicyARN: aws.ToString(cr.Spec.ForProvider.PolicyArn)})
+	meta.SetExternalName(cr, prefixedUniqueID(role+"-"))
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+// Update does nothing since all the parameters of an attachment identify it.
+func (e *rolePolicyAttachment) Update(_ context.Context, _ xpresource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *rolePolicyAttachment) Delete(ctx context.Context, mg xpresource.Managed) error {
+	cr, ok := mg.(*v1beta1.RolePolicyAttachment)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	// The attached policy is detached even if the policy of the managed
+	// resource has changed since.
+	arn := aws.ToString(cr.Spec.ForProvider.PolicyArn)
+	if attachedARN, ok := cr.GetAnnotations()[annotationKeyPolicyARN]; ok {
+		arn = attachedARN
+	}
+	_, err := e.client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		RoleName:  cr.Spec.ForProvider.Role,
+		PolicyArn: aws.String(arn),
+	})
+	if isErrorCode(err, "NoSuchEntity") {
+		return nil
+	}
+	return errors.Wrap(err, errDetachRolePolicy)
+}
+
+var (
+	uniqueIDMu      sync.Mutex
+	uniqueIDCounter uint32
+)
+
+// prefixedUniqueID returns a unique ID with the given prefix in the format of
+// the IDs the Terraform provider generates, which is the prefix followed by
+// the time and a counter.
+func prefixedUniqueID(prefix string) string {
+	uniqueIDMu.Lock()
+	defer uniqueIDMu.Unlock()
+	uniqueIDCounter++
+	ts := strings.Replace(time.Now().UTC().Format("20060102150405.0000"), ".", "", 1)
+	return fmt.Sprintf("%s%s%08x", prefix, ts, uniqueIDCounter)
+}
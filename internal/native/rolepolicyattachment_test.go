@@ -0,0 +1,253 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/provider-aws/apis/iam/v1beta1"
+)
+
+const policyARN = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+
+// fakeIAM is a set of roles whose policies are attached and detached.
+type fakeIAM struct {
+	// attached are the ARNs of the policies attached to the roles.
+	attached map[string][]string
+}
+
+func (f *fakeIAM) ListAttachedRolePolicies(_ context.Context, in *iam.ListAttachedRolePoliciesInput, _ ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	arns, ok := f.attached[aws.ToString(in.RoleName)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchEntity"}
+	}
+	out := &iam.ListAttachedRolePoliciesOutput{}
+	for _, arn := range arns {
+		out.AttachedPolicies = append(out.AttachedPolicies, types.AttachedPolicy{PolicyArn: aws.String(arn)})
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) AttachRolePolicy(_ context.Context, in *iam.AttachRolePolicyInput, _ ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error) {
+	role := aws.ToString(in.RoleName)
+	f.attached[role] = append(f.attached[role], aws.ToString(in.PolicyArn))
+	return &iam.AttachRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) DetachRolePolicy(_ context.Context, in *iam.DetachRolePolicyInput, _ ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+	role := aws.ToString(in.RoleName)
+	arns, ok := f.attached[role]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchEntity"}
+	}
+	f.attached[role] = nil
+	for _, arn := range arns {
+		if arn != aws.ToString(in.PolicyArn) {
+			f.attached[role] = append(f.attached[role], arn)
+		}
+	}
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+func newRolePolicyAttachment(role string) *v1beta1.RolePolicyAttachment {
+	return &v1beta1.RolePolicyAttachment{Spec: v1beta1.RolePolicyAttachmentSpec{ForProvider: v1beta1.RolePolicyAttachmentParameters{
+		Role:      aws.String(role),
+		PolicyArn: aws.String(policyARN),
+	}}}
+}
+
+func TestRolePolicyAttachmentEquivalence(t *testing.T) {
+	reason := "An attachment should be observed like Terraform observes it."
+	e := &rolePolicyAttachment{client: &fakeIAM{attached: map[string][]string{"admin": nil}}}
+
+	native := newRolePolicyAttachment("admin")
+	if _, err := e.Create(context.Background(), native); err != nil {
+		t.Fatalf("\n%s\nCreate(...): %v", reason, err)
+	}
+	id := meta.GetExternalName(native)
+	// The IDs are generated like resource.PrefixedUniqueId of the
+	// Terraform plugin SDK generates them.
+	if !regexp.MustCompile(`^admin-\d{18}[0-9a-f]{8}$`).MatchString(id) {
+		t.Errorf("\n%s\nCreate(...): external name %q is not a Terraform ID", reason, id)
+	}
+	obs, err := e.Observe(context.Background(), native)
+	if err != nil {
+		t.Fatalf("\n%s\nObserve(...): %v", reason, err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", reason, diff)
+	}
+
+	terraform := newRolePolicyAttachment("admin")
+	// The attached policy is recorded by the native client only, and does
+	// not change the observation.
+	meta.AddAnnotations(terraform, map[string]string{annotationKeyPolicyARN: policyARN})
+	terraformObserved(t, terraform, map[string]any{
+		"id":         id,
+		"role":       "admin",
+		"policy_arn": policyARN,
+	})
+	equivalent(t, reason, terraform, native)
+}
+
+func TestRolePolicyAttachmentObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		role        string
+		id          string
+		attachedARN string
+		attached    map[string][]string
+		want        managed.ExternalObservation
+		err         error
+		// recorded is the recorded ARN of the attached policy.
+		recorded string
+	}{
+		"Detached": {
+			reason:      "A policy that is not attached to the role should not exist.",
+			role:        "admin",
+			id:          "admin-2022091213141516170000000001",
+			attachedARN: policyARN,
+			attached:    map[string][]string{"admin": {"arn:aws:iam::aws:policy/AdministratorAccess"}},
+			want:        managed.ExternalObservation{ResourceExists: false},
+		},
+		"PolicyUnknown": {
+			reason:   "An attachment that did not record its policy should not be observed if its policy is not attached, since the policy it attached might be another one attached to the role.",
+			role:     "admin",
+			id:       "admin-2022091213141516170000000001",
+			attached: map[string][]string{"admin": {"arn:aws:iam::aws:policy/AdministratorAccess"}},
+			err:      errors.New(errPolicyUnknown),
+		},
+		"NothingAttached": {
+			reason:   "An attachment that did not record its policy should not exist if no policies are attached to the role.",
+			role:     "admin",
+			id:       "admin-2022091213141516170000000001",
+			attached: map[string][]string{"admin": nil},
+			want:     managed.ExternalObservation{ResourceExists: false},
+		},
+		"NoSuchRole": {
+			reason:   "An attachment to a missing role should not exist.",
+			role:     "admin",
+			id:       "admin-2022091213141516170000000001",
+			attached: map[string][]string{},
+			want:     managed.ExternalObservation{ResourceExists: false},
+		},
+		"RoleChanged": {
+			reason:   "An attachment whose role changed should not be observed since it cannot be updated.",
+			role:     "viewer",
+			id:       "admin-2022091213141516170000000001",
+			attached: map[string][]string{"viewer": {policyARN}},
+			err:      errors.New(errRoleChanged),
+		},
+		"PolicyChanged": {
+			reason:      "An attachment whose policy changed should not be observed since the policy it attached would be left attached.",
+			role:        "admin",
+			id:          "admin-2022091213141516170000000001",
+			attachedARN: "arn:aws:iam::aws:policy/AdministratorAccess",
+			attached:    map[string][]string{"admin": {"arn:aws:iam::aws:policy/AdministratorAccess"}},
+			err:         errors.New(errPolicyChanged),
+		},
+		"Attached": {
+			reason:      "An attachment of the policy it attached should exist.",
+			role:        "admin",
+			id:          "admin-2022091213141516170000000001",
+			attachedARN: policyARN,
+			attached:    map[string][]string{"admin": {policyARN}},
+			want:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			recorded:    policyARN,
+		},
+		"NotRecorded": {
+			reason:   "The policy of an attachment that did not record it should be recorded once it is observed attached.",
+			role:     "admin",
+			id:       "admin-2022091213141516170000000001",
+			attached: map[string][]string{"admin": {policyARN}},
+			want:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			recorded: policyARN,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newRolePolicyAttachment(tc.role)
+			meta.SetExternalName(cr, tc.id)
+			if tc.attachedARN != "" {
+				meta.AddAnnotations(cr, map[string]string{annotationKeyPolicyARN: tc.attachedARN})
+			}
+			e := &rolePolicyAttachment{client: &fakeIAM{attached: tc.attached}}
+			got, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if tc.recorded == "" {
+				return
+			}
+			if diff := cmp.Diff(tc.recorded, cr.GetAnnotations()[annotationKeyPolicyARN]); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want recorded policy, +got recorded policy:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRolePolicyAttachmentDelete(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		attachedARN string
+		attached    map[string][]string
+		want        map[string][]string
+	}{
+		"Attached": {
+			reason:      "The policy the attachment attached should be detached.",
+			attachedARN: policyARN,
+			attached:    map[string][]string{"admin": {policyARN, "arn:aws:iam::aws:policy/AdministratorAccess"}},
+			want:        map[string][]string{"admin": {"arn:aws:iam::aws:policy/AdministratorAccess"}},
+		},
+		"PolicyChanged": {
+			reason:      "The policy the attachment attached should be detached even if the policy of the managed resource changed since.",
+			attachedARN: "arn:aws:iam::aws:policy/AdministratorAccess",
+			attached:    map[string][]string{"admin": {policyARN, "arn:aws:iam::aws:policy/AdministratorAccess"}},
+			want:        map[string][]string{"admin": {policyARN}},
+		},
+		"NotRecorded": {
+			reason:   "The policy of the managed resource should be detached if the attachment was not created by the native client.",
+			attached: map[string][]string{"admin": {policyARN}},
+			want:     map[string][]string{"admin": nil},
+		},
+		"NoSuchRole": {
+			reason:   "An attachment to a missing role should be deleted.",
+			attached: map[string][]string{},
+			want:     map[string][]string{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newRolePolicyAttachment("admin")
+			meta.SetExternalName(cr, "admin-2022091213141516170000000001")
+			if tc.attachedARN != "" {
+				meta.AddAnnotations(cr, map[string]string{annotationKeyPolicyARN: tc.attachedARN})
+			}
+			f := &fakeIAM{attached: tc.attached}
+			e := &rolePolicyAttachment{client: f}
+			if err := e.Delete(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\nDelete(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, f.attached); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want attached policies, +got attached policies:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	"github.com/upbound/provider-aws/internal/clients"
+)
+
+const (
+	ruleTypeIngress = "ingress"
+	ruleTypeEgress  = "egress"
+
+	errDescribeSecurityGroup  = "cannot describe the security group"
+	errDescribeSecurityGroups = "cannot describe the security groups"
+	errAuthorizeRule          = "cannot authorize the security group rule"
+	errRevokeRule             = "cannot revoke the security group rule"
+	errUpdateRuleDescription  = "cannot update the description of the security group rule"
+	errFmtRuleType            = "unknown security group rule type %q"
+	errRuleChanged            = "cannot change the rule of an existing security group rule with the native client"
+)
+
+// securityGroupRuleAPI is the part of the EC2 API the SecurityGroupRule
+// client uses.
+type securityGroupRuleAPI interface {
+	DescribeSecurityGroups(ctx context.Context, in *ec2.DescribeSecurityGroupsInput, opts ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	AuthorizeSecurityGroupIngress(ctx context.Context, in *ec2.AuthorizeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
+	AuthorizeSecurityGroupEgress(ctx context.Context, in *ec2.AuthorizeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupEgressOutput, error)
+	RevokeSecurityGroupIngress(ctx context.Context, in *ec2.RevokeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupEgress(ctx context.Context, in *ec2.RevokeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error)
+	UpdateSecurityGroupRuleDescriptionsIngress(ctx context.Context, in *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput, opts ...func(*ec2.Options)) (*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput, error)
+	UpdateSecurityGroupRuleDescriptionsEgress(ctx context.Context, in *ec2.UpdateSecurityGroupRuleDescriptionsEgressInput, opts ...func(*ec2.Options)) (*ec2.UpdateSecurityGroupRuleDescriptionsEgressOutput, error)
+}
+
+type securityGroupRuleConnector struct {
+	kube        client.Client
+	batcher     *batcher
+	newClientFn func(cfg aws.Config) securityGroupRuleAPI
+}
+
+func newSecurityGroupRuleConnector(kube client.Client, b *batcher) *securityGroupRuleConnector {
+	return &securityGroupRuleConnector{
+		kube:    kube,
+		batcher: b,
+		newClientFn: func(cfg aws.Config) securityGroupRuleAPI {
+			return ec2.NewFromConfig(cfg)
+		},
+	}
+}
+
+func (c *securityGroupRuleConnector) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	cfg, err := clients.GetAWSConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &securityGroupRule{
+		client:  c.newClientFn(*cfg),
+		batcher: c.batcher,
+		key:     batchKey{providerConfig: mg.GetProviderConfigReference().Name, region: cfg.Region, kind: v1beta1.SecurityGroupRule_Kind},
+	}, nil
+}
+
+// securityGroupRule manages the rules of security groups like the
+// aws_security_group_rule Terraform resource. The ID of a rule is a hash of
+// its security group, type and permission.
+type securityGroupRule struct {
+	client securityGroupRuleAPI
+	// batcher describes all the security groups of the account and region
+	// at once if it is set.
+	batcher *batcher
+	key     batchKey
+}
+
+func (e *securityGroupRule) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.SecurityGroupRule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	p := cr.Spec.ForProvider
+	sgID, ruleType := aws.ToString(p.SecurityGroupID), aws.ToString(p.Type)
+	perm := expandPermission(p)
+	// Only the description of a rule can be updated, while the rest of it
+	// identifies it.
+	if ruleID(sgID, ruleType, perm) != id {
+		return managed.ExternalObservation{}, errors.New(errRuleChanged)
+	}
+	sg, err := e.securityGroup(ctx, sgID)
+	if err != nil || sg == nil {
+		return managed.ExternalObservation{ResourceExists: false}, err
+	}
+	perms := sg.IpPermissions
+	if ruleType == ruleTypeEgress {
+		perms = sg.IpPermissionsEgress
+	}
+	match := findRuleMatch(perm, perms)
+	if match == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	attrs := map[string]any{
+		"id":                id,
+		"security_group_id": sgID,
+		"type":              ruleType,
+		"protocol":          aws.ToString(p.Protocol),
+		"from_port":         aws.ToFloat64(p.FromPort),
+		"to_port":           aws.ToFloat64(p.ToPort),
+	}
+	description := ruleDescription(perm, match)
+	if description != "" {
+		attrs["description"] = description
+	}
+	return observed(cr, attrs, description == aws.ToString(p.Description))
+}
+
+func (e *securityGroupRule) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.SecurityGroupRule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errUnexpectedObject)
+	}
+	p := cr.Spec.ForProvider
+	sgID, perm := p.SecurityGroupID, expandPermission(p)
+	var err error
+	switch t := aws.ToString(p.Type); t {
+	case ruleTypeIngress:
+		_, err = e.client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	case ruleTypeEgress:
+		_, err = e.client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	default:
+		return managed.ExternalCreation{}, errors.Errorf(errFmtRuleType, t)
+	}
+	e.changed()
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errAuthorizeRule)
+	}
+	meta.SetExternalName(cr, ruleID(aws.ToString(sgID), aws.ToString(p.Type), perm))
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+// securityGroup returns the security group with the given ID, or nil if it
+// does not exist.
+func (e *securityGroupRule) securityGroup(ctx context.Context, id string) (*types.SecurityGroup, error) {
+	if e.batcher != nil {
+		groups, err := e.batcher.get(ctx, e.key, e.describeSecurityGroups)
+		if err != nil {
+			return nil, err
+		}
+		if sg, ok := groups.(map[string]types.SecurityGroup)[id]; ok {
+			return &sg, nil
+		}
+		batchMissed(e.key)
+	}
+	out, err := e.client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: []string{id}})
+	if isErrorCode(err, "InvalidGroup.NotFound") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errDescribeSecurityGroup)
+	}
+	if len(out.SecurityGroups) == 0 {
+		return nil, nil
+	}
+	return &out.SecurityGroups[0], nil
+}
+
+// describeSecurityGroups returns all the security groups by their IDs.
+func (e *securityGroupRule) describeSecurityGroups(ctx context.Context) (any, error) {
+	groups := map[string]types.SecurityGroup{}
+	p := ec2.NewDescribeSecurityGroupsPaginator(e.client, &ec2.DescribeSecurityGroupsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, errDescribeSecurityGroups)
+		}
+		for _, sg := range page.SecurityGroups {
+			groups[aws.ToString(sg.GroupId)] = sg
+		}
+	}
+	return groups, nil
+}
+
+// changed drops the described security groups after a rule was changed, so
+// that the change is observed.
+func (e *securityGroupRule) changed() {
+	if e.batcher != nil {
+		e.batcher.invalidate(e.key)
+	}
+}
+
+func (e *securityGroupRule) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.SecurityGroupRule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errUnexpectedObject)
+	}
+	p := cr.Spec.ForProvider
+	sgID, perm := p.SecurityGroupID, expandPermission(p)
+	var err error
+	switch t := aws.ToString(p.Type); t {
+	case ruleTypeIngress:
+		_, err = e.client.UpdateSecurityGroupRuleDescriptionsIngress(ctx, &ec2.UpdateSecurityGroupRuleDescriptionsIngressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	case ruleTypeEgress:
+		_, err = e.client.UpdateSecurityGroupRuleDescriptionsEgress(ctx, &ec2.UpdateSecurityGroupRuleDescriptionsEgressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	default:
+		return managed.ExternalUpdate{}, errors.Errorf(errFmtRuleType, t)
+	}
+	e.changed()
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateRuleDescription)
+}
+
+func (e *securityGroupRule) Delete(ctx context.Context, mg xpresource.Managed) error {
+	cr, ok := mg.(*v1beta1.SecurityGroupRule)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	p := cr.Spec.ForProvider
+	sgID, perm := p.SecurityGroupID, expandPermission(p)
+	var err error
+	switch t := aws.ToString(p.Type); t {
+	case ruleTypeIngress:
+		_, err = e.client.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	case ruleTypeEgress:
+		_, err = e.client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{GroupId: sgID, IpPermissions: []types.IpPermission{perm}})
+	default:
+		return errors.Errorf(errFmtRuleType, t)
+	}
+	e.changed()
+	if isErrorCode(err, "InvalidGroup.NotFound", "InvalidPermission.NotFound") {
+		return nil
+	}
+	return errors.Wrap(err, errRevokeRule)
+}
+
+// protocolNumbers are the protocols the Terraform provider refers to by their
+// names when they are given by their numbers.
+var protocolNumbers = map[string]string{
+	"1":  "icmp",
+	"6":  "tcp",
+	"17": "udp",
+	"50": "esp",
+	"51": "ah",
+	"58": "icmpv6",
+}
+
+// protocolForValue returns the protocol of a rule as the Terraform provider
+// normalizes it.
+func protocolForValue(v string) string {
+	p := strings.ToLower(v)
+	if p == "-1" || p == "all" {
+		return "-1"
+	}
+	if name, ok := protocolNumbers[p]; ok {
+		return name
+	}
+	return p
+}
+
+// expandPermission returns the permission of the given rule.
+func expandPermission(p v1beta1.SecurityGroupRuleParameters) types.IpPermission {
+	perm := types.IpPermission{
+		IpProtocol: aws.String(protocolForValue(aws.ToString(p.Protocol))),
+		FromPort:   aws.Int32(int32(aws.ToFloat64(p.FromPort))),
+		ToPort:     aws.Int32(int32(aws.ToFloat64(p.ToPort))),
+	}
+	for _, c := range p.CidrBlocks {
+		perm.IpRanges = append(perm.IpRanges, types.IpRange{CidrIp: c, Description: p.Description})
+	}
+	for _, c := range p.IPv6CidrBlocks {
+		perm.Ipv6Ranges = append(perm.Ipv6Ranges, types.Ipv6Range{CidrIpv6: c, Description: p.Description})
+	}
+	for _, id := range p.PrefixListIds {
+		perm.PrefixListIds = append(perm.PrefixListIds, types.PrefixListId{PrefixListId: id, Description: p.Description})
+	}
+	var groups []string
+	if aws.ToBool(p.Self) {
+		groups = append(groups, aws.ToString(p.SecurityGroupID))
+	}
+	if src := aws.ToString(p.SourceSecurityGroupID); src != "" {
+		groups = append(groups, src)
+	}
+	for _, g := range groups {
+		pair := types.UserIdGroupPair{GroupId: aws.String(g), Description: p.Description}
+		// Security groups of other accounts are referred to with their
+		// account IDs.
+		if owner, id, ok := strings.Cut(g, "/"); ok {
+			pair.UserId, pair.GroupId = aws.String(owner), aws.String(id)
+		}
+		perm.UserIdGroupPairs = append(perm.UserIdGroupPairs, pair)
+	}
+	return perm
+}
+
+// ruleID returns the ID the Terraform provider assigns to the rule with the
+// given permission.
+func ruleID(sgID, ruleType string, perm types.IpPermission) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s-", sgID)
+	if aws.ToInt32(perm.FromPort) > 0 {
+		fmt.Fprintf(&buf, "%d-", aws.ToInt32(perm.FromPort))
+	}
+	if aws.ToInt32(perm.ToPort) > 0 {
+		fmt.Fprintf(&buf, "%d-", aws.ToInt32(perm.ToPort))
+	}
+	fmt.Fprintf(&buf, "%s-", aws.ToString(perm.IpProtocol))
+	fmt.Fprintf(&buf, "%s-", ruleType)
+
+	// The sources are sorted so that the ID does not depend on their order.
+	var sources []string
+	for _, r := range perm.IpRanges {
+		sources = append(sources, aws.ToString(r.CidrIp))
+	}
+	writeSorted(&buf, sources)
+	sources = sources[:0]
+	for _, r := range perm.Ipv6Ranges {
+		sources = append(sources, aws.ToString(r.CidrIpv6))
+	}
+	writeSorted(&buf, sources)
+	sources = sources[:0]
+	for _, pl := range perm.PrefixListIds {
+		sources = append(sources, aws.ToString(pl.PrefixListId))
+	}
+	writeSorted(&buf, sources)
+
+	pairs := append([]types.UserIdGroupPair{}, perm.UserIdGroupPairs...)
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].GroupId != nil && pairs[j].GroupId != nil {
+			return *pairs[i].GroupId < *pairs[j].GroupId
+		}
+		if pairs[i].GroupName != nil && pairs[j].GroupName != nil {
+			return *pairs[i].GroupName < *pairs[j].GroupName
+		}
+		return false
+	})
+	for _, pair := range pairs {
+		fmt.Fprintf(&buf, "%s-", aws.ToString(pair.GroupId))
+		fmt.Fprintf(&buf, "%s-", aws.ToString(pair.GroupName))
+	}
+	return fmt.Sprintf("sgrule-%d", crc32.ChecksumIEEE(buf.Bytes()))
+}
+
+func writeSorted(buf *bytes.Buffer, s []string) {
+	sort.Strings(s)
+	for _, v := range s {
+		fmt.Fprintf(buf, "%s-", v)
+	}
+}
+
+// findRuleMatch returns the permission of a security group that contains the
+// given permission, if any. The security groups merge the permissions with
+// the same protocol and ports, so a permission might contain the sources of
+// several rules.
+func findRuleMatch(p types.IpPermission, perms []types.IpPermission) *types.IpPermission {
+	for i := range perms {
+		r := &perms[i]
+		if aws.ToString(r.IpProtocol) != aws.ToString(p.IpProtocol) {
+			continue
+		}
+		if aws.ToString(p.IpProtocol) != "-1" && (aws.ToInt32(r.FromPort) != aws.ToInt32(p.FromPort) || aws.ToInt32(r.ToPort) != aws.ToInt32(p.ToPort)) {
+			continue
+		}
+		if containsAll(cidrs(r.IpRanges), cidrs(p.IpRanges)) &&
+			containsAll(ipv6Cidrs(r.Ipv6Ranges), ipv6Cidrs(p.Ipv6Ranges)) &&
+			containsAll(prefixLists(r.PrefixListIds), prefixLists(p.PrefixListIds)) &&
+			containsAll(groupIDs(r.UserIdGroupPairs), groupIDs(p.UserIdGroupPairs)) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ruleDescription returns the description of the sources of the given
+// permission in the matching permission of the security group.
+func ruleDescription(p types.IpPermission, match *types.IpPermission) string {
+	for _, want := range cidrs(p.IpRanges) {
+		for _, r := range match.IpRanges {
+			if aws.ToString(r.CidrIp) == want {
+				return aws.ToString(r.Description)
+			}
+		}
+	}
+	for _, want := range ipv6Cidrs(p.Ipv6Ranges) {
+		for _, r := range match.Ipv6Ranges {
+			if aws.ToString(r.CidrIpv6) == want {
+				return aws.ToString(r.Description)
+			}
+		}
+	}
+	for _, want := range prefixLists(p.PrefixListIds) {
+		for _, pl := range match.PrefixListIds {
+			if aws.ToString(pl.PrefixListId) == want {
+				return aws.ToString(pl.Description)
+			}
+		}
+	}
+	for _, want := range groupIDs(p.UserIdGroupPairs) {
+		for _, pair := range match.UserIdGroupPairs {
+			if aws.ToString(pair.GroupId) == want {
+				return aws.ToString(pair.Description)
+			}
+		}
+	}
+	return ""
+}
+
+func containsAll(have, want []string) bool {
+	set := make(map[string]struct{}, len(have))
+	for _, h := range have {
+		set[h] = struct{}{}
+	}
+	for _, w := range want {
+		if _, ok := set[w]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func cidrs(rs []types.IpRange) []string {
+	s := make([]string, len(rs))
+	for i, r := range rs {
+		s[i] = aws.ToString(r.CidrIp)
+	}
+	return s
+}
+
+func ipv6Cidrs(rs []types.Ipv6Range) []string {
+	s := make([]string, len(rs))
+	for i, r := range rs {
+		s[i] = aws.ToString(r.CidrIpv6)
+	}
+	return s
+}
+
+func prefixLists(pls []types.PrefixListId) []string {
+	s := make([]string, len(pls))
+	for i, pl := range pls {
+		s[i] = aws.ToString(pl.PrefixListId)
+	}
+	return s
+}
+
+func groupIDs(pairs []types.UserIdGroupPair) []string {
+	s := make([]string, len(pairs))
+	for i, p := range pairs {
+		s[i] = aws.ToString(p.GroupId)
+	}
+	return s
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package native
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/provider-aws/apis/ec2/v1beta1"
+)
+
+const sgID = "sg-0123456789abcdef0"
+
+// fakeEC2 is a security group whose rules are authorized and revoked.
+type fakeEC2 struct {
+	securityGroupRuleAPI
+	sg types.SecurityGroup
+	// unlisted is true if the security group is missing from the
+	// described security groups, like one created after they were.
+	unlisted bool
+	// describes is the number of calls to describe the security groups.
+	describes int
+}
+
+func (f *fakeEC2) DescribeSecurityGroups(_ context.Context, in *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	f.describes++
+	if len(in.GroupIds) == 0 {
+		if f.unlisted {
+			return &ec2.DescribeSecurityGroupsOutput{}, nil
+		}
+		return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []types.SecurityGroup{f.sg}}, nil
+	}
+	if in.GroupIds[0] != aws.ToString(f.sg.GroupId) {
+		return nil, &smithy.GenericAPIError{Code: "InvalidGroup.NotFound"}
+	}
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []types.SecurityGroup{f.sg}}, nil
+}
+
+func (f *fakeEC2) AuthorizeSecurityGroupIngress(_ context.Context, in *ec2.AuthorizeSecurityGroupIngressInput, _ ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	f.sg.IpPermissions = append(f.sg.IpPermissions, in.IpPermissions...)
+	return &ec2.AuthorizeSecurityGroupIngressOutput{}, nil
+}
+
+func (f *fakeEC2) RevokeSecurityGroupIngress(_ context.Context, _ *ec2.RevokeSecurityGroupIngressInput, _ ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	f.sg.IpPermissions = nil
+	return &ec2.RevokeSecurityGroupIngressOutput{}, nil
+}
+
+func newSecurityGroupRule(p v1beta1.SecurityGroupRuleParameters) *v1beta1.SecurityGroupRule {
+	return &v1beta1.SecurityGroupRule{Spec: v1beta1.SecurityGroupRuleSpec{ForProvider: p}}
+}
+
+func TestSecurityGroupRuleEquivalence(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		params v1beta1.SecurityGroupRuleParameters
+		// attrs are the Terraform state attributes of the rule.
+		attrs map[string]any
+	}{
+		"CidrBlocks": {
+			reason: "A rule with CIDR blocks should be observed like Terraform observes it.",
+			params: v1beta1.SecurityGroupRuleParameters{
+				SecurityGroupID: aws.String(sgID),
+				Type:            aws.String("ingress"),
+				Protocol:        aws.String("6"),
+				FromPort:        aws.Float64(443),
+				ToPort:          aws.Float64(443),
+				CidrBlocks:      []*string{aws.String("192.168.0.0/24"), aws.String("10.0.0.0/16")},
+				Description:     aws.String("https"),
+			},
+			attrs: map[string]any{
+				"id":                "sgrule-4205284990",
+				"security_group_id": sgID,
+				"type":              "ingress",
+				"protocol":          "6",
+				"from_port":         443,
+				"to_port":           443,
+				"cidr_blocks":       []any{"192.168.0.0/24", "10.0.0.0/16"},
+				"description":       "https",
+				"self":              false,
+			},
+		},
+		"Self": {
+			reason: "A rule of a security group with itself should be observed like Terraform observes it.",
+			params: v1beta1.SecurityGroupRuleParameters{
+				SecurityGroupID: aws.String(sgID),
+				Type:            aws.String("ingress"),
+				Protocol:        aws.String("all"),
+				FromPort:        aws.Float64(0),
+				ToPort:          aws.Float64(0),
+				Self:            aws.Bool(true),
+			},
+			attrs: map[string]any{
+				"id":                "sgrule-1200966529",
+				"security_group_id": sgID,
+				"type":              "ingress",
+				"protocol":          "all",
+				"from_port":         0,
+				"to_port":           0,
+				"self":              true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// The permissions of the rule are merged with those of
+			// another rule by the security group.
+			other := types.IpPermission{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int32(443),
+				ToPort:     aws.Int32(443),
+				IpRanges:   []types.IpRange{{CidrIp: aws.String("172.16.0.0/12")}},
+			}
+			api := &fakeEC2{sg: types.SecurityGroup{GroupId: aws.String(sgID), IpPermissions: []types.IpPermission{other}}}
+			e := &securityGroupRule{client: api}
+
+			native := newSecurityGroupRule(tc.params)
+			if _, err := e.Create(context.Background(), native); err != nil {
+				t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+			}
+			api.sg.IpPermissions = mergePermissions(api.sg.IpPermissions)
+			obs, err := e.Observe(context.Background(), native)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			terraform := newSecurityGroupRule(tc.params)
+			terraformObserved(t, terraform, tc.attrs)
+			equivalent(t, tc.reason, terraform, native)
+		})
+	}
+}
+
+// mergePermissions merges the permissions with the same protocol and ports
+// like the security groups do.
+func mergePermissions(perms []types.IpPermission) []types.IpPermission {
+	var merged []types.IpPermission
+	for _, p := range perms {
+		found := false
+		for i := range merged {
+			m := &merged[i]
+			if aws.ToString(m.IpProtocol) == aws.ToString(p.IpProtocol) && aws.ToInt32(m.FromPort) == aws.ToInt32(p.FromPort) && aws.ToInt32(m.ToPort) == aws.ToInt32(p.ToPort) {
+				m.IpRanges = append(m.IpRanges, p.IpRanges...)
+				m.UserIdGroupPairs = append(m.UserIdGroupPairs, p.UserIdGroupPairs...)
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+func TestSecurityGroupRuleObserve(t *testing.T) {
+	params := v1beta1.SecurityGroupRuleParameters{
+		SecurityGroupID: aws.String(sgID),
+		Type:            aws.String("ingress"),
+		Protocol:        aws.String("tcp"),
+		FromPort:        aws.Float64(443),
+		ToPort:          aws.Float64(443),
+		CidrBlocks:      []*string{aws.String("10.0.0.0/16"), aws.String("192.168.0.0/24")},
+		Description:     aws.String("https"),
+	}
+	perm := types.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int32(443),
+		ToPort:     aws.Int32(443),
+		IpRanges: []types.IpRange{
+			{CidrIp: aws.String("10.0.0.0/16"), Description: aws.String("http")},
+			{CidrIp: aws.String("192.168.0.0/24"), Description: aws.String("http")},
+		},
+	}
+	cases := map[string]struct {
+		reason string
+		id     string
+		params func(p *v1beta1.SecurityGroupRuleParameters)
+		perms  []types.IpPermission
+		want   managed.ExternalObservation
+		err    error
+	}{
+		"NoExternalName": {
+			reason: "A rule without an external name should not exist.",
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"NotFound": {
+			reason: "A rule whose sources are not all permitted should not exist.",
+			id:     "sgrule-4205284990",
+			perms:  []types.IpPermission{{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(443), ToPort: aws.Int32(443), IpRanges: perm.IpRanges[:1]}},
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"DescriptionChanged": {
+			reason: "A rule whose description changed should not be up to date.",
+			id:     "sgrule-4205284990",
+			perms:  []types.IpPermission{perm},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"RuleChanged": {
+			reason: "A rule whose permission changed should not be observed since it cannot be updated.",
+			id:     "sgrule-4205284990",
+			params: func(p *v1beta1.SecurityGroupRuleParameters) { p.ToPort = aws.Float64(444) },
+			perms:  []types.IpPermission{perm},
+			err:    errors.New(errRuleChanged),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := params
+			if tc.params != nil {
+				tc.params(&p)
+			}
+			cr := newSecurityGroupRule(p)
+			meta.SetExternalName(cr, tc.id)
+			e := &securityGroupRule{client: &fakeEC2{sg: types.SecurityGroup{GroupId: aws.String(sgID), IpPermissions: tc.perms}}}
+			got, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecurityGroupRuleBatchedObserve(t *testing.T) {
+	rule := func(cidr string) *v1beta1.SecurityGroupRule {
+		p := v1beta1.SecurityGroupRuleParameters{
+			SecurityGroupID: aws.String(sgID),
+			Type:            aws.String("ingress"),
+			Protocol:        aws.String("tcp"),
+			FromPort:        aws.Float64(443),
+			ToPort:          aws.Float64(443),
+			CidrBlocks:      []*string{aws.String(cidr)},
+		}
+		cr := newSecurityGroupRule(p)
+		meta.SetExternalName(cr, ruleID(sgID, "ingress", expandPermission(p)))
+		return cr
+	}
+	perm := types.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int32(443),
+		ToPort:     aws.Int32(443),
+		IpRanges:   []types.IpRange{{CidrIp: aws.String("10.0.0.0/16")}, {CidrIp: aws.String("10.1.0.0/16")}, {CidrIp: aws.String("10.2.0.0/16")}},
+	}
+	cases := map[string]struct {
+		reason    string
+		unlisted  bool
+		create    bool
+		describes int
+	}{
+		"Listed": {
+			reason:    "The rules of a listed security group should be observed with a single call.",
+			describes: 1,
+		},
+		"Unlisted": {
+			reason:    "The rules of a security group missing from the listed ones should be observed on their own.",
+			unlisted:  true,
+			describes: 4,
+		},
+		"Created": {
+			reason:    "The security groups should be described again once a rule was created.",
+			create:    true,
+			describes: 2,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeEC2{sg: types.SecurityGroup{GroupId: aws.String(sgID), IpPermissions: []types.IpPermission{perm}}, unlisted: tc.unlisted}
+			e := &securityGroupRule{client: api, batcher: newBatcher(time.Minute), key: batchKey{kind: v1beta1.SecurityGroupRule_Kind}}
+			for i, cidr := range []string{"10.0.0.0/16", "10.1.0.0/16", "10.2.0.0/16"} {
+				if tc.create && i == 2 {
+					if _, err := e.Create(context.Background(), rule("10.3.0.0/16")); err != nil {
+						t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+					}
+				}
+				got, err := e.Observe(context.Background(), rule(cidr))
+				if err != nil {
+					t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+				}
+				if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, got); diff != "" {
+					t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.describes, api.describes); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProtocolForValue(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		value  string
+		want   string
+	}{
+		"All": {
+			reason: "All the protocols should be normalized to -1.",
+			value:  "all",
+			want:   "-1",
+		},
+		"Number": {
+			reason: "The protocols the Terraform provider names should be referred to by their names.",
+			value:  "6",
+			want:   "tcp",
+		},
+		"ESP": {
+			reason: "ESP should be referred to by its name.",
+			value:  "50",
+			want:   "esp",
+		},
+		"AH": {
+			reason: "AH should be referred to by its name.",
+			value:  "51",
+			want:   "ah",
+		},
+		"Name": {
+			reason: "Protocol names should be lowercased.",
+			value:  "UDP",
+			want:   "udp",
+		},
+		"Unnamed": {
+			reason: "The protocols the Terraform provider does not name should be referred to by their numbers.",
+			value:  "47",
+			want:   "47",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, protocolForValue(tc.value)); diff != "" {
+				t.Errorf("\n%s\nprotocolForValue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package persistence
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	errReadState   = "cannot read the persisted workspace state"
+	errWriteState  = "cannot write the persisted workspace state"
+	errRemoveState = "cannot remove the persisted workspace state"
+)
+
+// DirectoryStore persists the workspace states as files in a directory, such
+// as the mount path of a PersistentVolume.
+type DirectoryStore struct {
+	root string
+}
+
+// NewDirectoryStore returns a new DirectoryStore that persists the states in
+// the given directory.
+func NewDirectoryStore(root string) (*DirectoryStore, error) {
+	return &DirectoryStore{root: root}, errors.Wrap(os.MkdirAll(root, 0700), errWriteState)
+}
+
+const extension = ".json.gz"
+
+func (d *DirectoryStore) path(uid types.UID) string {
+	return filepath.Join(d.root, string(uid)+extension)
+}
+
+// Load returns the persisted state of the workspace of the managed resource
+// with the given UID, or nil if there is none.
+func (d *DirectoryStore) Load(_ context.Context, uid types.UID) (*State, error) {
+	data, err := os.ReadFile(d.path(uid))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errReadState)
+	}
+	return decode(data)
+}
+
+// Save persists the given state of the workspace of the managed resource with
+// the given UID. The file is replaced atomically, so that a crash never
+// leaves a partially written state.
+func (d *DirectoryStore) Save(_ context.Context, uid types.UID, s *State) error {
+	data, err := encode(s)
+	if err != nil {
+		return err
+	}
+	tmp := d.path(uid) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrap(err, errWriteState)
+	}
+	return errors.Wrap(os.Rename(tmp, d.path(uid)), errWriteState)
+}
+
+// Delete removes the persisted state of the workspace of the managed resource
+// with the given UID, if any.
+func (d *DirectoryStore) Delete(_ context.Context, uid types.UID) error {
+	if err := os.Remove(d.path(uid)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, errRemoveState)
+	}
+	return nil
+}
+
+// List returns the UIDs of the managed resources whose workspaces have
+// persisted states. The temporary files of interrupted saves are ignored.
+func (d *DirectoryStore) List(_ context.Context) ([]types.UID, error) {
+	entries, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadState)
+	}
+	uids := make([]types.UID, 0, len(entries))
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasSuffix(name, extension) {
+			uids = append(uids, types.UID(strings.TrimSuffix(name, extension)))
+		}
+	}
+	return uids, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package persistence persists the Terraform state of the workspaces outside
+// of the provider pod, so that it is reused after the provider restarts
+// instead of being rebuilt from the managed resources.
+package persistence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	errMarshalState   = "cannot marshal the workspace state"
+	errUnmarshalState = "cannot unmarshal the workspace state"
+)
+
+// State is the persisted state of a workspace.
+type State struct {
+	// ExternalName is the external name of the managed resource when the
+	// state was saved. The state is only reused for the same external
+	// resource.
+	ExternalName string `json:"externalName"`
+
+	// SavedAt is the time the state was saved.
+	SavedAt time.Time `json:"savedAt"`
+
+	// Files are the contents of the state files of the workspace by their
+	// names.
+	Files map[string][]byte `json:"files"`
+}
+
+// A Store persists the state of the workspaces of managed resources by their
+// UIDs.
+type Store interface {
+	// Load returns the persisted state of the workspace of the managed
+	// resource with the given UID, or nil if there is none.
+	Load(ctx context.Context, uid types.UID) (*State, error)
+
+	// Save persists the given state of the workspace of the managed
+	// resource with the given UID.
+	Save(ctx context.Context, uid types.UID, s *State) error
+
+	// Delete removes the persisted state of the workspace of the managed
+	// resource with the given UID, if any.
+	Delete(ctx context.Context, uid types.UID) error
+
+	// List returns the UIDs of the managed resources whose workspaces
+	// have persisted states.
+	List(ctx context.Context) ([]types.UID, error)
+}
+
+// Fresh returns true if the given state can be reused for the external
+// resource with the given external name at the given time, that is, it was
+// saved for the same external resource at most maxAge before. States of any
+// age are fresh if maxAge is zero.
+func Fresh(s *State, externalName string, maxAge time.Duration, now time.Time) bool {
+	if s == nil || s.ExternalName != externalName {
+		return false
+	}
+	return maxAge == 0 || now.Sub(s.SavedAt) <= maxAge
+}
+
+// Digest returns a digest of the files of the given state, which changes
+// only when their contents change.
+func Digest(files map[string][]byte) string {
+	// The files are marshalled with sorted keys.
+	b, _ := json.Marshal(files) //nolint:errchkjson // a map of byte slices is always marshalled
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func encode(s *State) ([]byte, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalState)
+	}
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, errors.Wrap(err, errMarshalState)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, errMarshalState)
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte) (*State, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, errUnmarshalState)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, errors.Wrap(err, errUnmarshalState)
+	}
+	s := &State{}
+	return s, errors.Wrap(json.Unmarshal(b, s), errUnmarshalState)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFresh(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		reason       string
+		state        *State
+		externalName string
+		maxAge       time.Duration
+		want         bool
+	}{
+		"NoState": {
+			reason: "A missing state should not be fresh.",
+		},
+		"OtherExternalResource": {
+			reason:       "A state of another external resource should not be fresh.",
+			state:        &State{ExternalName: "db-1", SavedAt: now},
+			externalName: "db-2",
+		},
+		"TooOld": {
+			reason:       "A state older than the maximum age should not be fresh.",
+			state:        &State{ExternalName: "db-1", SavedAt: now.Add(-2 * time.Hour)},
+			externalName: "db-1",
+			maxAge:       time.Hour,
+		},
+		"Fresh": {
+			reason:       "A recent state of the same external resource should be fresh.",
+			state:        &State{ExternalName: "db-1", SavedAt: now.Add(-time.Minute)},
+			externalName: "db-1",
+			maxAge:       time.Hour,
+			want:         true,
+		},
+		"NoMaxAge": {
+			reason:       "A state of any age should be fresh without a maximum age.",
+			state:        &State{ExternalName: "db-1", SavedAt: now.Add(-1000 * time.Hour)},
+			externalName: "db-1",
+			want:         true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Fresh(tc.state, tc.externalName, tc.maxAge, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFresh(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// random returns n random bytes, which are not compressible, so that the
+// state is split into several chunks.
+func random(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+	uid := types.UID("2d0b0a0e-6c1a-4c4e-9d3e-1f4f0c6a7b8c")
+	dir, err := NewDirectoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().Build()
+	stores := map[string]Store{
+		"Directory": dir,
+		"Secret":    NewSecretStore(kube, "crossplane-system"),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			if got, err := s.Load(ctx, uid); err != nil || got != nil {
+				t.Fatalf("Load(...): want no state, got %v, %v", got, err)
+			}
+			for _, size := range []int{3 * chunkSize, 100} {
+				want := &State{
+					ExternalName: "db-1",
+					SavedAt:      time.Now().UTC().Truncate(time.Second),
+					Files:        map[string][]byte{"terraform.tfstate": random(t, size)},
+				}
+				if err := s.Save(ctx, uid, want); err != nil {
+					t.Fatalf("Save(...): %v", err)
+				}
+				got, err := s.Load(ctx, uid)
+				if err != nil {
+					t.Fatalf("Load(...): %v", err)
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("Load(...): -want, +got:\n%s", diff)
+				}
+			}
+			uids, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("List(...): %v", err)
+			}
+			if diff := cmp.Diff([]types.UID{uid}, uids); diff != "" {
+				t.Errorf("List(...): -want, +got:\n%s", diff)
+			}
+			if err := s.Delete(ctx, uid); err != nil {
+				t.Fatalf("Delete(...): %v", err)
+			}
+			if got, err := s.Load(ctx, uid); err != nil || got != nil {
+				t.Errorf("Load(...): want no state after Delete, got %v, %v", got, err)
+			}
+			if uids, err := s.List(ctx); err != nil || len(uids) != 0 {
+				t.Errorf("List(...): want no states after Delete, got %v, %v", uids, err)
+			}
+		})
+	}
+
+	l := &corev1.SecretList{}
+	if err := kube.List(ctx, l, client.MatchingLabels{LabelKeyWorkspaceUID: string(uid)}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(0, len(l.Items)); diff != "" {
+		t.Errorf("Secrets left after Delete: -want, +got:\n%s", diff)
+	}
+}
+
+func TestSecretStoreInterruptedSave(t *testing.T) {
+	ctx := context.Background()
+	uid := types.UID("2d0b0a0e-6c1a-4c4e-9d3e-1f4f0c6a7b8c")
+	kube := fake.NewClientBuilder().Build()
+	s := NewSecretStore(kube, "crossplane-system")
+	if err := s.Save(ctx, uid, &State{Files: map[string][]byte{"terraform.tfstate": random(t, 2*chunkSize)}}); err != nil {
+		t.Fatalf("Save(...): %v", err)
+	}
+	// Only the first chunk of a later save is written.
+	if err := s.apply(ctx, uid, 0, 2, "other", bytes.Repeat([]byte{1}, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Load(ctx, uid); err != nil || got != nil {
+		t.Errorf("Load(...): want no state from an interrupted save, got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package persistence
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelKeyWorkspaceUID is the label of the Secrets that hold the
+	// persisted state of the workspace of the managed resource with the
+	// UID in its value.
+	LabelKeyWorkspaceUID = "aws.upbound.io/workspace-uid"
+
+	annotationKeyChunk  = "aws.upbound.io/workspace-chunk"
+	annotationKeyChunks = "aws.upbound.io/workspace-chunks"
+	annotationKeyDigest = "aws.upbound.io/workspace-digest"
+
+	keyChunk = "chunk"
+
+	// chunkSize is the maximum size of the state held by a Secret, which is
+	// well below the 1 MiB limit of the Secrets.
+	chunkSize = 512 * 1024
+
+	errListSecrets  = "cannot list the Secrets of the workspace state"
+	errGetSecret    = "cannot get the Secret of the workspace state"
+	errApplySecret  = "cannot create or update the Secret of the workspace state"
+	errDeleteSecret = "cannot delete the Secret of the workspace state"
+)
+
+// SecretStore persists the workspace states in Secrets. The compressed state
+// of a workspace is split into chunks that are held by separate Secrets,
+// since it can be larger than a single Secret can hold.
+type SecretStore struct {
+	kube      client.Client
+	namespace string
+}
+
+// NewSecretStore returns a new SecretStore that persists the states in
+// Secrets in the given namespace.
+func NewSecretStore(kube client.Client, namespace string) *SecretStore {
+	return &SecretStore{kube: kube, namespace: namespace}
+}
+
+func (s *SecretStore) list(ctx context.Context, uid types.UID) ([]corev1.Secret, error) {
+	l := &corev1.SecretList{}
+	err := s.kube.List(ctx, l, client.InNamespace(s.namespace), client.MatchingLabels{LabelKeyWorkspaceUID: string(uid)})
+	return l.Items, errors.Wrap(err, errListSecrets)
+}
+
+// Load returns the persisted state of the workspace of the managed resource
+// with the given UID, or nil if there is none. A state whose chunks are not
+// all of the same save, such as when saving it was interrupted, is treated
+// as if there was none.
+func (s *SecretStore) Load(ctx context.Context, uid types.UID) (*State, error) {
+	secrets, err := s.list(ctx, uid)
+	if err != nil || len(secrets) == 0 {
+		return nil, err
+	}
+	digest := secrets[0].GetAnnotations()[annotationKeyDigest]
+	chunks := make([][]byte, len(secrets))
+	for _, sec := range secrets {
+		a := sec.GetAnnotations()
+		i, err := strconv.Atoi(a[annotationKeyChunk])
+		if err != nil || i < 0 || i >= len(chunks) || a[annotationKeyDigest] != digest || a[annotationKeyChunks] != strconv.Itoa(len(chunks)) {
+			return nil, nil
+		}
+		chunks[i] = sec.Data[keyChunk]
+	}
+	var data []byte
+	for _, c := range chunks {
+		data = append(data, c...)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != digest {
+		return nil, nil
+	}
+	return decode(data)
+}
+
+// Save persists the given state of the workspace of the managed resource with
+// the given UID.
+func (s *SecretStore) Save(ctx context.Context, uid types.UID, st *State) error {
+	data, err := encode(st)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	n := (len(data) + chunkSize - 1) / chunkSize
+	for i := 0; i < n; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := s.apply(ctx, uid, i, n, digest, data[i*chunkSize:end]); err != nil {
+			return err
+		}
+	}
+	// The chunks of a larger previous state are removed.
+	secrets, err := s.list(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for i := range secrets {
+		if idx, err := strconv.Atoi(secrets[i].GetAnnotations()[annotationKeyChunk]); err == nil && idx < n {
+			continue
+		}
+		if err := s.kube.Delete(ctx, &secrets[i]); xpresource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteSecret)
+		}
+	}
+	return nil
+}
+
+func (s *SecretStore) apply(ctx context.Context, uid types.UID, i, n int, digest string, chunk []byte) error {
+	sec := &corev1.Secret{}
+	name := fmt.Sprintf("aws-workspace-%s-%d", uid, i)
+	err := s.kube.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, sec)
+	if xpresource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+	sec.ObjectMeta = metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       s.namespace,
+		ResourceVersion: sec.GetResourceVersion(),
+		Labels:          map[string]string{LabelKeyWorkspaceUID: string(uid)},
+		Annotations: map[string]string{
+			annotationKeyChunk:  strconv.Itoa(i),
+			annotationKeyChunks: strconv.Itoa(n),
+			annotationKeyDigest: digest,
+		},
+	}
+	sec.Data = map[string][]byte{keyChunk: chunk}
+	if kerrors.IsNotFound(err) {
+		return errors.Wrap(s.kube.Create(ctx, sec), errApplySecret)
+	}
+	return errors.Wrap(s.kube.Update(ctx, sec), errApplySecret)
+}
+
+// Delete removes the persisted state of the workspace of the managed resource
+// with the given UID, if any.
+func (s *SecretStore) Delete(ctx context.Context, uid types.UID) error {
+	secrets, err := s.list(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// A partially deleted state is never loaded since its chunks are
+	// incomplete.
+	for i := range secrets {
+		if err := s.kube.Delete(ctx, &secrets[i]); xpresource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteSecret)
+		}
+	}
+	return nil
+}
+
+// List returns the UIDs of the managed resources whose workspaces have
+// persisted states, including the partially saved or deleted ones.
+func (s *SecretStore) List(ctx context.Context) ([]types.UID, error) {
+	l := &corev1.SecretList{}
+	if err := s.kube.List(ctx, l, client.InNamespace(s.namespace), client.HasLabels{LabelKeyWorkspaceUID}); err != nil {
+		return nil, errors.Wrap(err, errListSecrets)
+	}
+	seen := map[types.UID]bool{}
+	uids := make([]types.UID, 0, len(l.Items))
+	for _, sec := range l.Items {
+		uid := types.UID(sec.GetLabels()[LabelKeyWorkspaceUID])
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
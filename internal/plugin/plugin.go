@@ -0,0 +1,301 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package plugin resolves the Terraform provider plugin from mirrors, so that
+// the provider runs without access to the Terraform registry.
+package plugin
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	// registryHost is the hostname of the Terraform registry the provider
+	// sources belong to.
+	registryHost = "registry.terraform.io"
+
+	errInvalidSource    = "invalid Terraform provider source %q, expected <namespace>/<type>"
+	errNoTerraform      = "cannot find the terraform CLI in PATH"
+	errNoChecksum       = "no checksum of the Terraform provider %s %s to verify it against"
+	errNoMirrorDir      = "cannot find the Terraform provider %s %s for %s in the filesystem mirror, expected it in %s"
+	errNoNativeProvider = "cannot find the native Terraform provider %s"
+	errChecksum         = "checksum of the Terraform provider binary %s is %s, expected %s for version %s"
+	errReadBinary       = "cannot read the Terraform provider binary %s"
+	errMirrorRequest    = "cannot get the Terraform provider %s %s from the network mirror %s"
+	errMirrorStatus     = "cannot find the Terraform provider %s %s in the network mirror, %s returned %s"
+	errMirrorDecode     = "cannot decode the Terraform provider versions from the network mirror %s"
+	errNoMirrorArchive  = "cannot find the Terraform provider %s %s for %s in the network mirror %s"
+	errArchiveURL       = "invalid URL of the Terraform provider archive %s"
+	errDownloadArchive  = "cannot download the Terraform provider archive %s"
+	errArchiveStatus    = "cannot download the Terraform provider archive %s, the network mirror returned %s"
+	errArchiveHash      = "the Terraform provider archive %s matches none of the hashes %v the network mirror publishes"
+	errReadArchive      = "cannot read the Terraform provider archive %s"
+	errNoArchiveBinary  = "cannot find the Terraform provider binary in the archive %s"
+	errWriteCLIConfig   = "cannot write the Terraform CLI configuration"
+)
+
+// A Mirror the Terraform provider is installed from instead of the Terraform
+// registry. Exactly one of its fields is expected to be set.
+type Mirror struct {
+	// Dir is the path of a filesystem mirror in the unpacked layout.
+	Dir string
+	// URL is the base URL of a network mirror.
+	URL string
+}
+
+// A Requirement of the Terraform provider that must be met by a Mirror.
+type Requirement struct {
+	// Source of the provider, such as hashicorp/aws.
+	Source string
+	// Version of the provider.
+	Version string
+	// Platform the provider runs on, such as linux_amd64.
+	Platform string
+	// Checksum is the hex encoded SHA-256 checksum of the provider binary.
+	// It is required unless SkipChecksum is set.
+	Checksum string
+	// SkipChecksum opts out of verifying the checksum of the provider
+	// binary. The archives served by a network mirror are still verified
+	// against the hashes the mirror publishes.
+	SkipChecksum bool
+	// NativePath is the path of the provider binary that is run as a shared
+	// native provider, if any.
+	NativePath string
+}
+
+// CLIConfig returns the Terraform CLI configuration that installs all the
+// providers from the Mirror and never from their registries.
+func (m Mirror) CLIConfig() []byte {
+	mirror := fmt.Sprintf("  filesystem_mirror {\n    path    = %q\n    include = [\"*/*\"]\n  }\n", m.Dir)
+	if m.URL != "" {
+		mirror = fmt.Sprintf("  network_mirror {\n    url     = %q\n    include = [\"*/*\"]\n  }\n", m.URL)
+	}
+	return []byte("provider_installation {\n" + mirror + "  direct {\n    exclude = [\"*/*\"]\n  }\n}\n")
+}
+
+// WriteCLIConfig writes the Terraform CLI configuration of the Mirror to the
+// given path.
+func (m Mirror) WriteCLIConfig(path string) error {
+	return errors.Wrap(os.WriteFile(path, m.CLIConfig(), 0o600), errWriteCLIConfig)
+}
+
+// Verify returns an error naming the missing artifact if the Terraform CLI or
+// the required Terraform provider cannot be installed from the Mirror, or if
+// the checksum of the provider binary is not the required one. The archive
+// served by a network mirror is downloaded and verified against the hashes
+// the mirror publishes, and the checksum of the binary it holds is verified.
+func (m Mirror) Verify(ctx context.Context, c *http.Client, r Requirement) error {
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return errors.Wrap(err, errNoTerraform)
+	}
+	if len(strings.Split(r.Source, "/")) != 2 {
+		return errors.Errorf(errInvalidSource, r.Source)
+	}
+	if r.Checksum == "" && !r.SkipChecksum {
+		return errors.Errorf(errNoChecksum, r.Source, r.Version)
+	}
+	var binaries []string
+	switch {
+	case m.URL != "":
+		if err := m.verifyNetwork(ctx, c, r); err != nil {
+			return err
+		}
+	case m.Dir != "":
+		b, err := m.findBinary(r)
+		if err != nil {
+			return err
+		}
+		binaries = append(binaries, b)
+	}
+	if r.NativePath != "" {
+		if _, err := os.Stat(r.NativePath); err != nil {
+			return errors.Wrapf(err, errNoNativeProvider, r.NativePath)
+		}
+		binaries = append(binaries, r.NativePath)
+	}
+	if r.SkipChecksum {
+		return nil
+	}
+	for _, b := range binaries {
+		if err := verifyChecksum(b, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findBinary returns the path of the required provider binary in the
+// filesystem mirror, which is
+// <dir>/<host>/<namespace>/<type>/<version>/<platform>/terraform-provider-<type>*
+func (m Mirror) findBinary(r Requirement) (string, error) {
+	dir := filepath.Join(m.Dir, registryHost, r.Source, r.Version, r.Platform)
+	files, err := filepath.Glob(filepath.Join(dir, binaryPrefix(r)+"*"))
+	if err != nil || len(files) == 0 {
+		return "", errors.Errorf(errNoMirrorDir, r.Source, r.Version, r.Platform, dir)
+	}
+	return files[0], nil
+}
+
+// archive is a provider archive listed by the version endpoint of the
+// provider network mirror protocol.
+type archive struct {
+	URL    string   `json:"url"`
+	Hashes []string `json:"hashes"`
+}
+
+// verifyNetwork verifies that the network mirror serves the required
+// provider, using the version endpoint of the provider network mirror
+// protocol, and verifies the archive it serves.
+func (m Mirror) verifyNetwork(ctx context.Context, c *http.Client, r Requirement) error {
+	u := strings.TrimSuffix(m.URL, "/") + "/" + registryHost + "/" + r.Source + "/" + r.Version + ".json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return errors.Wrapf(err, errMirrorRequest, r.Source, r.Version, m.URL)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, errMirrorRequest, r.Source, r.Version, m.URL)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf(errMirrorStatus, r.Source, r.Version, u, resp.Status)
+	}
+	v := struct {
+		Archives map[string]archive `json:"archives"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return errors.Wrapf(err, errMirrorDecode, u)
+	}
+	a, ok := v.Archives[r.Platform]
+	if !ok {
+		return errors.Errorf(errNoMirrorArchive, r.Source, r.Version, r.Platform, m.URL)
+	}
+	// The archive URLs are relative to the URL of the version endpoint.
+	base, err := url.Parse(u)
+	if err != nil {
+		return errors.Wrapf(err, errArchiveURL, a.URL)
+	}
+	ref, err := url.Parse(a.URL)
+	if err != nil {
+		return errors.Wrapf(err, errArchiveURL, a.URL)
+	}
+	return verifyArchive(ctx, c, base.ResolveReference(ref).String(), a.Hashes, r)
+}
+
+// verifyArchive downloads the provider archive at the given URL and verifies
+// it against the given hashes, which are either zh: hashes, the SHA-256
+// checksums of the archive, or h1: hashes of its contents, and verifies the
+// checksum of the provider binary it holds.
+func verifyArchive(ctx context.Context, c *http.Client, u string, hashes []string, r Requirement) error { //nolint:gocyclo // Mostly error handling.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return errors.Wrapf(err, errDownloadArchive, u)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, errDownloadArchive, u)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf(errArchiveStatus, u, resp.Status)
+	}
+	f, err := os.CreateTemp("", "terraform-provider-*.zip")
+	if err != nil {
+		return errors.Wrapf(err, errDownloadArchive, u)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		return errors.Wrapf(err, errDownloadArchive, u)
+	}
+	if len(hashes) > 0 {
+		zh := "zh:" + hex.EncodeToString(h.Sum(nil))
+		h1, err := dirhash.HashZip(f.Name(), dirhash.Hash1)
+		if err != nil {
+			return errors.Wrapf(err, errReadArchive, u)
+		}
+		if !contains(hashes, zh) && !contains(hashes, h1) {
+			return errors.Errorf(errArchiveHash, u, hashes)
+		}
+	}
+	if r.SkipChecksum {
+		return nil
+	}
+	zr, err := zip.OpenReader(f.Name())
+	if err != nil {
+		return errors.Wrapf(err, errReadArchive, u)
+	}
+	defer func() { _ = zr.Close() }()
+	for _, zf := range zr.File {
+		if strings.HasPrefix(path.Base(zf.Name), binaryPrefix(r)) {
+			return verifyZipChecksum(zf, u, r)
+		}
+	}
+	return errors.Errorf(errNoArchiveBinary, u)
+}
+
+func verifyZipChecksum(zf *zip.File, u string, r Requirement) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return errors.Wrapf(err, errReadArchive, u)
+	}
+	defer func() { _ = rc.Close() }()
+	return checksum(rc, u+"!"+zf.Name, r)
+}
+
+func verifyChecksum(path string, r Requirement) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrapf(err, errReadBinary, path)
+	}
+	defer func() { _ = f.Close() }()
+	return checksum(f, path, r)
+}
+
+// checksum verifies the checksum of the provider binary read from the given
+// reader.
+func checksum(rd io.Reader, name string, r Requirement) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, rd); err != nil {
+		return errors.Wrapf(err, errReadBinary, name)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, r.Checksum) {
+		return errors.Errorf(errChecksum, name, got, r.Checksum, r.Version)
+	}
+	return nil
+}
+
+// binaryPrefix returns the prefix of the name of the provider binary, such
+// as terraform-provider-aws.
+func binaryPrefix(r Requirement) string {
+	return "terraform-provider-" + path.Base(r.Source)
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+func TestCLIConfig(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mirror Mirror
+		want   string
+	}{
+		"Filesystem": {
+			reason: "A filesystem mirror should be configured for all the providers.",
+			mirror: Mirror{Dir: "/terraform/provider-mirror"},
+			want: `provider_installation {
+  filesystem_mirror {
+    path    = "/terraform/provider-mirror"
+    include = ["*/*"]
+  }
+  direct {
+    exclude = ["*/*"]
+  }
+}
+`,
+		},
+		"Network": {
+			reason: "A network mirror should be configured for all the providers.",
+			mirror: Mirror{URL: "https://mirror.example.com/providers/"},
+			want: `provider_installation {
+  network_mirror {
+    url     = "https://mirror.example.com/providers/"
+    include = ["*/*"]
+  }
+  direct {
+    exclude = ["*/*"]
+  }
+}
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, string(tc.mirror.CLIConfig())); diff != "" {
+				t.Errorf("\n%s\nCLIConfig(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte("#!/bin/sh\n"), 0o700); err != nil { //nolint:gosec // A fake executable.
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	mirror := t.TempDir()
+	platform := filepath.Join(mirror, registryHost, "hashicorp", "aws", "4.38.0", "linux_amd64")
+	if err := os.MkdirAll(platform, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	binary := filepath.Join(platform, "terraform-provider-aws_v4.38.0_x5")
+	if err := os.WriteFile(binary, []byte("provider"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("provider"))
+	checksum := hex.EncodeToString(sum[:])
+
+	// The network mirror serves the archive of the provider with the hashes
+	// of the Terraform provider network mirror protocol.
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("terraform-provider-aws_v4.38.0_x5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("provider")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zipSum := sha256.Sum256(buf.Bytes())
+	zipFile := filepath.Join(t.TempDir(), "provider.zip")
+	if err := os.WriteFile(zipFile, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h1, err := dirhash.HashZip(zipFile, dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := map[string][]string{
+		"4.38.0": {"zh:" + hex.EncodeToString(zipSum[:])},
+		"4.37.0": {h1},
+		"4.36.0": {"zh:0000"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".zip") {
+			_, _ = w.Write(buf.Bytes())
+			return
+		}
+		version := strings.TrimSuffix(path.Base(r.URL.Path), ".json")
+		h, ok := hashes[version]
+		if !ok || path.Dir(r.URL.Path) != "/"+registryHost+"/hashicorp/aws" {
+			http.NotFound(w, r)
+			return
+		}
+		v := map[string]any{"archives": map[string]any{"linux_amd64": map[string]any{"url": "terraform-provider-aws_" + version + "_linux_amd64.zip", "hashes": h}}}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+	defer srv.Close()
+
+	req := func(version, platform, checksum string) Requirement {
+		return Requirement{Source: "hashicorp/aws", Version: version, Platform: platform, Checksum: checksum, SkipChecksum: checksum == ""}
+	}
+	cases := map[string]struct {
+		reason string
+		mirror Mirror
+		req    Requirement
+		want   error
+	}{
+		"FilesystemMirror": {
+			reason: "A provider binary of the required version and platform in the filesystem mirror should be verified.",
+			mirror: Mirror{Dir: mirror},
+			req:    req("4.38.0", "linux_amd64", checksum),
+		},
+		"NoChecksum": {
+			reason: "A missing checksum should be an error unless it is explicitly skipped.",
+			mirror: Mirror{Dir: mirror},
+			req:    Requirement{Source: "hashicorp/aws", Version: "4.38.0", Platform: "linux_amd64"},
+			want:   errors.Errorf(errNoChecksum, "hashicorp/aws", "4.38.0"),
+		},
+		"SkippedChecksum": {
+			reason: "The checksum should not be verified if it is explicitly skipped.",
+			mirror: Mirror{Dir: mirror},
+			req:    req("4.38.0", "linux_amd64", ""),
+		},
+		"MissingVersion": {
+			reason: "A missing provider version should be named.",
+			mirror: Mirror{Dir: mirror},
+			req:    req("4.39.0", "linux_amd64", checksum),
+			want:   errors.Errorf(errNoMirrorDir, "hashicorp/aws", "4.39.0", "linux_amd64", filepath.Join(mirror, registryHost, "hashicorp", "aws", "4.39.0", "linux_amd64")),
+		},
+		"WrongChecksum": {
+			reason: "A provider binary whose checksum is not the required one should be rejected.",
+			mirror: Mirror{Dir: mirror},
+			req:    req("4.38.0", "linux_amd64", "deadbeef"),
+			want:   errors.Errorf(errChecksum, binary, checksum, "deadbeef", "4.38.0"),
+		},
+		"NetworkMirror": {
+			reason: "An archive served by the network mirror should be verified against the zh: hashes it publishes and the checksum of the binary it holds.",
+			mirror: Mirror{URL: srv.URL},
+			req:    req("4.38.0", "linux_amd64", checksum),
+		},
+		"NetworkMirrorH1": {
+			reason: "An archive served by the network mirror should be verified against the h1: hashes it publishes.",
+			mirror: Mirror{URL: srv.URL + "/"},
+			req:    req("4.37.0", "linux_amd64", checksum),
+		},
+		"NetworkMirrorWrongHash": {
+			reason: "An archive that matches none of the hashes the network mirror publishes should be rejected.",
+			mirror: Mirror{URL: srv.URL},
+			req:    req("4.36.0", "linux_amd64", ""),
+			want:   errors.Errorf(errArchiveHash, srv.URL+"/"+registryHost+"/hashicorp/aws/terraform-provider-aws_4.36.0_linux_amd64.zip", []string{"zh:0000"}),
+		},
+		"NetworkMirrorWrongChecksum": {
+			reason: "An archive holding a provider binary whose checksum is not the required one should be rejected.",
+			mirror: Mirror{URL: srv.URL},
+			req:    req("4.38.0", "linux_amd64", "deadbeef"),
+			want:   errors.Errorf(errChecksum, srv.URL+"/"+registryHost+"/hashicorp/aws/terraform-provider-aws_4.38.0_linux_amd64.zip!terraform-provider-aws_v4.38.0_x5", checksum, "deadbeef", "4.38.0"),
+		},
+		"MissingPlatform": {
+			reason: "A provider platform missing from the network mirror should be named.",
+			mirror: Mirror{URL: srv.URL},
+			req:    req("4.38.0", "linux_arm64", ""),
+			want:   errors.Errorf(errNoMirrorArchive, "hashicorp/aws", "4.38.0", "linux_arm64", srv.URL),
+		},
+		"MissingNetworkVersion": {
+			reason: "A provider version missing from the network mirror should be named.",
+			mirror: Mirror{URL: srv.URL},
+			req:    req("4.39.0", "linux_amd64", ""),
+			want:   errors.Errorf(errMirrorStatus, "hashicorp/aws", "4.39.0", srv.URL+"/"+registryHost+"/hashicorp/aws/4.39.0.json", "404 Not Found"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.mirror.Verify(context.Background(), srv.Client(), tc.req)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package poll contains a reconciler that lets managed resources override the
+// poll interval of their controller with an annotation, and that requeues
+// frozen managed resources when their freeze window ends.
+package poll
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/provider-aws/internal/connector"
+)
+
+const (
+	// AnnotationKeyPollInterval is the annotation that overrides the poll
+	// interval of a managed resource, such as "30m".
+	AnnotationKeyPollInterval = "aws.upbound.io/poll-interval"
+
+	// MinInterval is the minimum poll interval that can be set with the
+	// annotation.
+	MinInterval = 30 * time.Second
+)
+
+// Reconciler overrides the poll interval of the wrapped managed reconciler
+// with the poll interval annotation of the reconciled resource.
+type Reconciler struct {
+	inner    reconcile.Reconciler
+	kube     client.Reader
+	newObj   func() (runtime.Object, error)
+	interval time.Duration
+	log      logging.Logger
+}
+
+// NewReconciler returns a Reconciler for the given managed reconciler of the
+// given kind, which polls with the given interval.
+func NewReconciler(r reconcile.Reconciler, c client.Reader, s *runtime.Scheme, gvk schema.GroupVersionKind, interval time.Duration, l logging.Logger) *Reconciler {
+	return &Reconciler{
+		inner:    r,
+		kube:     c,
+		newObj:   func() (runtime.Object, error) { return s.New(gvk) },
+		interval: interval,
+		log:      l,
+	}
+}
+
+// Reconcile calls the wrapped reconciler and requeues the resource after its
+// own poll interval if the wrapped reconciler requeued it to poll, or at the
+// end of the freeze window if its changes are deferred because of one.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.inner.Reconcile(ctx, req)
+	if err != nil || (!res.Requeue && res.RequeueAfter != r.interval) {
+		return res, err
+	}
+	o, err := r.newObj()
+	if err != nil {
+		return res, nil
+	}
+	obj, ok := o.(client.Object)
+	if !ok {
+		return res, nil
+	}
+	// The resource is read from the cache of the manager.
+	if err := r.kube.Get(ctx, req.NamespacedName, obj); err != nil {
+		return res, nil
+	}
+	if res.Requeue {
+		// A resource whose changes are deferred because of a freeze window
+		// is requeued when the window ends rather than with the backoff of
+		// the failed reconcile.
+		c, ok := obj.(xpresource.Conditioned)
+		if !ok {
+			return res, nil
+		}
+		if d := time.Until(connector.FrozenUntil(c)); d > 0 {
+			return reconcile.Result{RequeueAfter: d}, nil
+		}
+		return res, nil
+	}
+	v, ok := obj.GetAnnotations()[AnnotationKeyPollInterval]
+	if !ok {
+		return res, nil
+	}
+	d, err := Interval(v)
+	if err != nil {
+		r.log.Info("Ignoring the poll interval annotation", "request", req, "error", err)
+		return res, nil
+	}
+	res.RequeueAfter = d
+	return res, nil
+}
+
+// Interval parses the given poll interval annotation value. Intervals shorter
+// than MinInterval are raised to it.
+func Interval(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot parse %s annotation", AnnotationKeyPollInterval)
+	}
+	if d < MinInterval {
+		d = MinInterval
+	}
+	return d, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package poll
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/provider-aws/internal/connector"
+)
+
+func TestInterval(t *testing.T) {
+	type want struct {
+		d   time.Duration
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		v      string
+		want   want
+	}{
+		"Interval": {
+			reason: "A valid interval should be parsed.",
+			v:      "30m",
+			want:   want{d: 30 * time.Minute},
+		},
+		"Clamped": {
+			reason: "An interval shorter than the minimum interval should be raised to it.",
+			v:      "1s",
+			want:   want{d: MinInterval},
+		},
+		"Invalid": {
+			reason: "An interval that cannot be parsed should be an error.",
+			v:      "often",
+			want:   want{err: errors.Wrapf(errors.New(`time: invalid duration "often"`), "cannot parse %s annotation", AnnotationKeyPollInterval)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := Interval(tc.v)
+			if diff := cmp.Diff(tc.want.d, d); diff != "" {
+				t.Errorf("\n%s\nInterval(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInterval(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	managedGVK := schema.GroupVersionKind{Group: "fake.upbound.io", Version: "v1", Kind: "Managed"}
+	s.AddKnownTypeWithName(managedGVK, &fake.Managed{})
+	get := func(annotations map[string]string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.SetAnnotations(annotations)
+			return nil
+		}
+	}
+
+	frozen := func(until time.Time) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*fake.Managed).SetConditions(xpv1.Condition{
+				Type:    connector.TypeFrozen,
+				Status:  corev1.ConditionTrue,
+				Message: fmt.Sprintf("the changes of the external resource are deferred until %s because of an active freeze window", until.UTC().Format(time.RFC3339)),
+			})
+			return nil
+		}
+	}
+
+	// The end of a freeze window is recorded in seconds, and the requeue of
+	// a frozen resource also depends on the time the test takes.
+	approxDuration := cmp.Comparer(func(a, b time.Duration) bool { return (a - b).Abs() < 2*time.Second })
+
+	type want struct {
+		res reconcile.Result
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		res    reconcile.Result
+		err    error
+		gvk    schema.GroupVersionKind
+		get    test.MockGetFn
+		want   want
+	}{
+		"Annotated": {
+			reason: "A poll should be requeued after the poll interval of the annotation.",
+			res:    reconcile.Result{RequeueAfter: time.Minute},
+			get:    get(map[string]string{AnnotationKeyPollInterval: "1h"}),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Hour}},
+		},
+		"NotAnnotated": {
+			reason: "A poll of a resource without the annotation should be requeued after the poll interval of the controller.",
+			res:    reconcile.Result{RequeueAfter: time.Minute},
+			get:    get(nil),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"InvalidAnnotation": {
+			reason: "An invalid annotation should be ignored.",
+			res:    reconcile.Result{RequeueAfter: time.Minute},
+			get:    get(map[string]string{AnnotationKeyPollInterval: "often"}),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"GetError": {
+			reason: "The poll interval of the controller should be used if the resource cannot be read.",
+			res:    reconcile.Result{RequeueAfter: time.Minute},
+			get:    test.NewMockGetFn(errors.New("boom")),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"NotPoll": {
+			reason: "A requeue that is not a poll should not be changed.",
+			res:    reconcile.Result{RequeueAfter: time.Second},
+			get:    get(map[string]string{AnnotationKeyPollInterval: "1h"}),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Second}},
+		},
+		"Frozen": {
+			reason: "A resource whose changes are deferred because of a freeze window should be requeued when the window ends.",
+			res:    reconcile.Result{Requeue: true},
+			gvk:    managedGVK,
+			get:    frozen(time.Now().Add(time.Hour)),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Hour}},
+		},
+		"Thawed": {
+			reason: "A failed reconcile of a resource whose freeze window ended should be requeued with the backoff.",
+			res:    reconcile.Result{Requeue: true},
+			gvk:    managedGVK,
+			get:    frozen(time.Now().Add(-time.Hour)),
+			want:   want{res: reconcile.Result{Requeue: true}},
+		},
+		"NotFrozen": {
+			reason: "A failed reconcile of a resource that is not frozen should be requeued with the backoff.",
+			res:    reconcile.Result{Requeue: true},
+			gvk:    managedGVK,
+			get:    test.NewMockGetFn(nil),
+			want:   want{res: reconcile.Result{Requeue: true}},
+		},
+		"Error": {
+			reason: "A failed reconcile should not be changed.",
+			res:    reconcile.Result{RequeueAfter: time.Minute},
+			err:    errors.New("boom"),
+			get:    get(map[string]string{AnnotationKeyPollInterval: "1h"}),
+			want:   want{res: reconcile.Result{RequeueAfter: time.Minute}, err: errors.New("boom")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return tc.res, tc.err
+			})
+			k := gvk
+			if !tc.gvk.Empty() {
+				k = tc.gvk
+			}
+			r := NewReconciler(inner, &test.MockClient{MockGet: tc.get}, s, k, time.Minute, logging.NewNopLogger())
+			res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "cm"}})
+			if diff := cmp.Diff(tc.want.res, res, approxDuration); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
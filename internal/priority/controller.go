@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package priority
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+)
+
+const (
+	errNewController = "cannot create controller"
+	errWatch         = "cannot watch managed resources"
+)
+
+// Reconciler hands the waiting requests of a Queue to the workqueue of the
+// controller as the reconciles of the wrapped reconciler finish. It is
+// expected to be the outermost reconciler of the controller, so that the
+// requests the wrapped reconcilers requeue, such as the ones that are rate
+// limited or failed, are added to the Queue rather than to the workqueue, with
+// the priority they were handed to the workqueue with.
+type Reconciler struct {
+	inner reconcile.Reconciler
+	queue *Queue
+	log   logging.Logger
+}
+
+// NewReconciler returns a Reconciler that wraps the given reconciler.
+func (q *Queue) NewReconciler(r reconcile.Reconciler, l logging.Logger) *Reconciler {
+	return &Reconciler{inner: r, queue: q, log: l}
+}
+
+// Reconcile calls the wrapped reconciler and hands the next waiting request
+// to the workqueue of the controller. The errors of the wrapped reconciler
+// are logged and counted rather than returned, since the controller would
+// requeue the failed requests to its workqueue.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	defer r.queue.Pump()
+	p := r.queue.take(req)
+	res, err := r.inner.Reconcile(ctx, req)
+	switch {
+	case err != nil || res.Requeue:
+		if err != nil {
+			metrics.ReconcileErrors.WithLabelValues(r.queue.name).Inc()
+			r.log.Info("Reconciler error", "request", req, "error", err)
+		}
+		r.queue.AddAfter(req, p, r.queue.backoff.When(req))
+	case res.RequeueAfter > 0:
+		r.queue.AddAfter(req, p, res.RequeueAfter)
+	default:
+		r.queue.backoff.Forget(req)
+	}
+	return reconcile.Result{}, nil
+}
+
+// PollReconciler adds the polls the wrapped reconciler requests to a Queue
+// with low priority.
+type PollReconciler struct {
+	inner reconcile.Reconciler
+	queue *Queue
+}
+
+// NewPollReconciler returns a PollReconciler that wraps the given reconciler,
+// which is expected to be the managed reconciler.
+func (q *Queue) NewPollReconciler(r reconcile.Reconciler) *PollReconciler {
+	return &PollReconciler{inner: r, queue: q}
+}
+
+// Reconcile calls the wrapped reconciler and adds the poll it requests to the
+// Queue.
+func (r *PollReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.inner.Reconcile(ctx, req)
+	if err != nil || res.Requeue || res.RequeueAfter <= 0 {
+		return res, err
+	}
+	r.queue.AddAfter(req, Low, res.RequeueAfter)
+	return reconcile.Result{}, nil
+}
+
+// Setup adds a controller with the given name and options to the given
+// manager, which reconciles the given kind of managed resources with the
+// given reconciler in the order of their priorities. The reconciler is
+// expected to be wrapped with the NewReconciler of the given Queue, which
+// requeues the failed requests with the rate limiter of the options.
+func Setup(mgr manager.Manager, name string, o controller.Options, q *Queue, obj client.Object, r reconcile.Reconciler) error {
+	o = q.options(o)
+	o.Reconciler = r
+	c, err := controller.New(name, mgr, o)
+	if err != nil {
+		return errors.Wrap(err, errNewController)
+	}
+	return errors.Wrap(c.Watch(&source.Kind{Type: obj}, q.Handler()), errWatch)
+}
+
+// options returns the given controller options with their rate limiter
+// taken over by the Queue. The controller forgets every request its
+// reconciler does not fail, which is all of them, so the workqueue of the
+// controller must not share the rate limiter that backs off the failed
+// requests of the Queue.
+func (q *Queue) options(o controller.Options) controller.Options {
+	if o.RateLimiter != nil {
+		q.backoff = o.RateLimiter
+	}
+	o.RateLimiter = noRateLimit{}
+	return o
+}
+
+// noRateLimit is the rate limiter of the workqueues of the controllers, whose
+// requests are rate limited by their Queues.
+type noRateLimit struct{}
+
+func (noRateLimit) When(interface{}) time.Duration { return 0 }
+func (noRateLimit) Forget(interface{})             {}
+func (noRateLimit) NumRequeues(interface{}) int    { return 0 }
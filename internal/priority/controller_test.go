@@ -0,0 +1,164 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// limitAll is a rate limiter that limits every request for a minute.
+type limitAll struct{}
+
+func (limitAll) When(interface{}) time.Duration { return time.Minute }
+func (limitAll) Forget(interface{})             {}
+func (limitAll) NumRequeues(interface{}) int    { return 0 }
+
+func TestReconciler(t *testing.T) {
+	type requeue struct {
+		d time.Duration
+		p Priority
+	}
+	type want struct {
+		res reconcile.Result
+		err error
+		// requeues are the delays and priorities the request is added to
+		// the Queue again with.
+		requeues []requeue
+	}
+	cases := map[string]struct {
+		reason string
+		p      Priority
+		// inner returns the reconciler wrapped by the Reconciler.
+		inner func(q *Queue, r reconcile.Reconciler) reconcile.Reconciler
+		res   reconcile.Result
+		err   error
+		want  want
+	}{
+		"Synced": {
+			reason: "A request that is reconciled should not be requeued.",
+			p:      High,
+		},
+		"Poll": {
+			reason: "A poll should be added to the Queue with low priority.",
+			p:      High,
+			inner: func(q *Queue, r reconcile.Reconciler) reconcile.Reconciler {
+				return q.NewPollReconciler(r)
+			},
+			res:  reconcile.Result{RequeueAfter: time.Hour},
+			want: want{requeues: []requeue{{d: time.Hour, p: Low}}},
+		},
+		"Requeue": {
+			reason: "A request that is requeued should be added to the Queue after the backoff with the priority it was handed with.",
+			p:      High,
+			res:    reconcile.Result{Requeue: true},
+			want:   want{requeues: []requeue{{d: time.Second, p: High}}},
+		},
+		"Error": {
+			reason: "A request whose reconcile failed should be added to the Queue after the backoff with the priority it was handed with, rather than to the workqueue by the controller.",
+			p:      High,
+			err:    errors.New("boom"),
+			want:   want{requeues: []requeue{{d: time.Second, p: High}}},
+		},
+		"RateLimited": {
+			reason: "A request that is rate limited should be added to the Queue with the priority it was handed with, rather than to the workqueue by the controller.",
+			p:      High,
+			inner: func(q *Queue, r reconcile.Reconciler) reconcile.Reconciler {
+				return ratelimiter.NewReconciler("test", q.NewPollReconciler(r), limitAll{})
+			},
+			want: want{requeues: []requeue{{d: time.Minute, p: High}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			type timer struct {
+				d time.Duration
+				f func()
+			}
+			var timers []timer
+			q := NewQueue(name, 1)
+			q.backoff = workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute)
+			q.after = func(d time.Duration, f func()) { timers = append(timers, timer{d: d, f: f}) }
+			wq := workqueue.New()
+			defer wq.ShutDown()
+			q.Add(wq, req("a"), tc.p)
+			item, _ := wq.Get()
+			wq.Done(item)
+
+			var r reconcile.Reconciler = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return tc.res, tc.err
+			})
+			if tc.inner != nil {
+				r = tc.inner(q, r)
+			}
+			res, err := q.NewReconciler(r, logging.NewNopLogger()).Reconcile(context.Background(), req("a"))
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if wq.Len() != 0 {
+				t.Errorf("\n%s\nReconcile(...): the request was added to the workqueue rather than to the Queue", tc.reason)
+			}
+			var got []requeue
+			for _, tm := range timers {
+				tm.f()
+				for wq.Len() > 0 {
+					item, _ := wq.Get()
+					got = append(got, requeue{d: tm.d, p: q.take(item.(reconcile.Request))})
+					wq.Done(item)
+				}
+			}
+			if diff := cmp.Diff(tc.want.requeues, got, cmp.AllowUnexported(requeue{})); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want requeues, +got requeues:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcilerBackoff(t *testing.T) {
+	var delays []time.Duration
+	var timers []func()
+	q := NewQueue("backoff", 1)
+	q.after = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		timers = append(timers, f)
+	}
+	// The controller builds its workqueue with the rate limiter of the
+	// options, and forgets the requests its reconciler does not fail.
+	o := q.options(controller.Options{RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute)})
+	wq := workqueue.NewRateLimitingQueue(o.RateLimiter)
+	defer wq.ShutDown()
+	r := q.NewReconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, errors.New("boom")
+	}), logging.NewNopLogger())
+
+	q.Add(wq, req("a"), High)
+	for i := 0; i < 4; i++ {
+		item, _ := wq.Get()
+		res, err := r.Reconcile(context.Background(), item.(reconcile.Request))
+		if err == nil && !res.Requeue {
+			wq.Forget(item)
+		}
+		wq.Done(item)
+		// The request is added to the workqueue again once its backoff
+		// elapses.
+		timers[len(timers)-1]()
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	if diff := cmp.Diff(want, delays); diff != "" {
+		t.Errorf("\nA request that keeps failing should be requeued after a growing backoff, even though the controller forgets it.\nReconcile(...): -want delays, +got delays:\n%s", diff)
+	}
+}
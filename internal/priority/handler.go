@@ -0,0 +1,69 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package priority
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Handler returns the event handler that adds the requests for the watched
+// managed resources to the Queue by priority.
+func (q *Queue) Handler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, wq workqueue.RateLimitingInterface) {
+			q.Add(wq, request(e.Object), createPriority(e.Object))
+		},
+		UpdateFunc: func(e event.UpdateEvent, wq workqueue.RateLimitingInterface) {
+			q.Add(wq, request(e.ObjectNew), updatePriority(e.ObjectOld, e.ObjectNew))
+		},
+		DeleteFunc: func(e event.DeleteEvent, wq workqueue.RateLimitingInterface) {
+			q.Add(wq, request(e.Object), High)
+		},
+		GenericFunc: func(e event.GenericEvent, wq workqueue.RateLimitingInterface) {
+			q.Add(wq, request(e.Object), Low)
+		},
+	}
+}
+
+func request(o client.Object) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}}
+}
+
+// createPriority returns the priority of the creation of the given managed
+// resource. The managed resources that were already synced are only seen
+// as created by the initial list of the informer, such as after a restart of
+// the provider, which is a resync.
+func createPriority(o client.Object) Priority {
+	c, ok := o.(xpresource.Conditioned)
+	if !ok || c.GetCondition(xpv1.TypeSynced).Status == corev1.ConditionUnknown {
+		return High
+	}
+	return Low
+}
+
+// updatePriority returns the priority of the update of the given managed
+// resource. Only the changes to its spec and its deletion are changes made
+// by the users; the changes to its status and the resyncs of the informer
+// are not.
+func updatePriority(old, cur client.Object) Priority {
+	if old == nil || cur == nil {
+		return Low
+	}
+	if old.GetGeneration() != cur.GetGeneration() {
+		return High
+	}
+	if old.GetDeletionTimestamp() == nil && cur.GetDeletionTimestamp() != nil {
+		return High
+	}
+	return Low
+}
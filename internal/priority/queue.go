@@ -0,0 +1,251 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package priority lets the requests caused by changes to managed resources
+// jump ahead of their periodic resyncs in the workqueues of their
+// controllers.
+package priority
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+)
+
+// Priority of a request.
+type Priority int
+
+// Priorities of the requests.
+const (
+	// Low is the priority of the periodic resyncs, such as the polls of
+	// the managed resources and the resyncs of the informers.
+	Low Priority = iota
+	// High is the priority of the requests caused by changes to the
+	// managed resources, such as new generations, new managed resources
+	// and deletions.
+	High
+)
+
+func (p Priority) String() string {
+	if p == High {
+		return "high"
+	}
+	return "low"
+}
+
+const (
+	// highPerLow is the number of high priority requests that are handed
+	// to the controller for each low priority one while both are waiting,
+	// so that the resyncs are never starved.
+	highPerLow = 8
+
+	// pumpRetry is the interval the waiting requests are handed to the
+	// controller in while its workqueue is full and no reconcile finishes,
+	// such as while the reconciles are rate limited.
+	pumpRetry = time.Second
+)
+
+// A Queue holds the requests of a controller by priority and hands them to
+// the workqueue of the controller only while it has room for them, so that
+// the order of the requests is decided by their priorities rather than by
+// the workqueue. Requests are never lost: a request that is added again is
+// kept once, at the highest priority it was added with.
+type Queue struct {
+	name string
+	// maxQueued is the number of requests the workqueue of the controller
+	// holds at most, which keeps its workers busy.
+	maxQueued int
+
+	mu       sync.Mutex
+	wq       workqueue.Interface
+	waiting  map[reconcile.Request]Priority
+	fifos    [2][]reconcile.Request
+	counts   [2]int
+	highRun  int
+	retrying bool
+	// handed are the priorities of the requests handed to the workqueue,
+	// which they are requeued with.
+	handed map[reconcile.Request]Priority
+	// deadlines are the requests added with AddAfter, one per request.
+	deadlines map[reconcile.Request]deadline
+	// backoff decides when the requests whose reconciles failed are
+	// requeued.
+	backoff ratelimiter.RateLimiter
+	now     func() time.Time
+	after   func(d time.Duration, f func())
+}
+
+// A deadline is the time a request is added at and its priority.
+type deadline struct {
+	at time.Time
+	p  Priority
+}
+
+// NewQueue returns a Queue for the controller with the given name and number
+// of workers.
+func NewQueue(name string, workers int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Queue{
+		name:      name,
+		maxQueued: workers,
+		waiting:   map[reconcile.Request]Priority{},
+		handed:    map[reconcile.Request]Priority{},
+		deadlines: map[reconcile.Request]deadline{},
+		backoff:   workqueue.DefaultControllerRateLimiter(),
+		now:       time.Now,
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+// Add adds the given request with the given priority and hands the waiting
+// requests to the given workqueue of the controller.
+func (q *Queue) Add(wq workqueue.Interface, req reconcile.Request, p Priority) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.wq = wq
+	q.add(req, p)
+}
+
+func (q *Queue) add(req reconcile.Request, p Priority) {
+	cur, ok := q.waiting[req]
+	if ok && cur >= p {
+		return
+	}
+	if ok {
+		q.counts[cur]--
+	}
+	q.counts[p]++
+	q.waiting[req] = p
+	q.fifos[p] = append(q.fifos[p], req)
+	q.pump()
+}
+
+// AddAfter adds the given request with the given priority once the given
+// duration elapses. Like the AddAfter of a delaying workqueue, a request
+// that is already waiting to be added is added once, at the earlier of its
+// times and with the higher of its priorities.
+func (q *Queue) AddAfter(req reconcile.Request, p Priority, d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	at := q.now().Add(d)
+	cur, ok := q.deadlines[req]
+	if ok && cur.p > p {
+		p = cur.p
+	}
+	if ok && !cur.at.After(at) {
+		q.deadlines[req] = deadline{at: cur.at, p: p}
+		return
+	}
+	q.deadlines[req] = deadline{at: at, p: p}
+	q.after(d, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		// The request was added at an earlier time since.
+		cur, ok := q.deadlines[req]
+		if !ok || !cur.at.Equal(at) {
+			return
+		}
+		delete(q.deadlines, req)
+		q.add(req, cur.p)
+	})
+}
+
+// take returns the priority the given request was handed to the workqueue
+// with. Requests that were not handed to it by the Queue have low priority.
+func (q *Queue) take(req reconcile.Request) Priority {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p := q.handed[req]
+	delete(q.handed, req)
+	return p
+}
+
+// Pump hands the waiting requests to the workqueue of the controller while it
+// has room for them.
+func (q *Queue) Pump() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pump()
+}
+
+func (q *Queue) pump() {
+	defer q.observe()
+	if q.wq == nil {
+		return
+	}
+	for q.wq.Len() < q.maxQueued {
+		req, ok := q.pop()
+		if !ok {
+			return
+		}
+		q.wq.Add(req)
+	}
+	// The workqueue is full. The waiting requests are handed to it once a
+	// reconcile finishes, or after a while if none does.
+	if len(q.waiting) > 0 && !q.retrying {
+		q.retrying = true
+		q.after(pumpRetry, func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			q.retrying = false
+			q.pump()
+		})
+	}
+}
+
+// pop returns the next waiting request. A low priority request is returned
+// after highPerLow high priority ones if both are waiting.
+func (q *Queue) pop() (reconcile.Request, bool) {
+	p := High
+	if q.next(High) == nil || (q.highRun >= highPerLow && q.next(Low) != nil) {
+		p = Low
+	}
+	head := q.next(p)
+	if head == nil {
+		return reconcile.Request{}, false
+	}
+	req := *head
+	q.fifos[p] = q.fifos[p][1:]
+	delete(q.waiting, req)
+	q.counts[p]--
+	// The workqueue holds a request once, which keeps the higher of the
+	// priorities it was handed with.
+	if cur, ok := q.handed[req]; !ok || cur < p {
+		q.handed[req] = p
+	}
+	if p == High {
+		q.highRun++
+	} else {
+		q.highRun = 0
+	}
+	return req, true
+}
+
+// next returns the next waiting request with the given priority, dropping
+// the ones that were raised to a higher priority.
+func (q *Queue) next(p Priority) *reconcile.Request {
+	for len(q.fifos[p]) > 0 {
+		req := q.fifos[p][0]
+		if cur, ok := q.waiting[req]; ok && cur == p {
+			return &req
+		}
+		q.fifos[p] = q.fifos[p][1:]
+	}
+	return nil
+}
+
+func (q *Queue) observe() {
+	for _, p := range []Priority{Low, High} {
+		metrics.PriorityQueueDepth.WithLabelValues(q.name, p.String()).Set(float64(q.counts[p]))
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package priority
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/provider-aws/apis/s3/v1beta1"
+)
+
+func req(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+}
+
+func TestQueue(t *testing.T) {
+	type add struct {
+		name string
+		p    Priority
+	}
+	many := func(prefix string, n int, p Priority) []add {
+		adds := make([]add, n)
+		for i := range adds {
+			adds[i] = add{name: prefix + string(rune('a'+i)), p: p}
+		}
+		return adds
+	}
+	cases := map[string]struct {
+		reason string
+		// full keeps the workqueue full while the requests are added.
+		full bool
+		adds []add
+		want []string
+	}{
+		"PriorityOrder": {
+			reason: "High priority requests should be handed to the workqueue before the low priority ones.",
+			full:   true,
+			adds:   []add{{"poll-a", Low}, {"poll-b", Low}, {"change", High}},
+			want:   []string{"change", "poll-a", "poll-b"},
+		},
+		"Deduplicated": {
+			reason: "A request that is added again should be handed to the workqueue once.",
+			full:   true,
+			adds:   []add{{"a", Low}, {"b", Low}, {"a", Low}},
+			want:   []string{"a", "b"},
+		},
+		"Raised": {
+			reason: "A request that is added again with a higher priority should be handed to the workqueue with it.",
+			full:   true,
+			adds:   []add{{"a", Low}, {"b", Low}, {"b", High}, {"b", Low}},
+			want:   []string{"b", "a"},
+		},
+		"Fair": {
+			reason: "A low priority request should be handed to the workqueue after highPerLow high priority ones.",
+			full:   true,
+			adds:   append([]add{{"poll", Low}}, many("change-", highPerLow+1, High)...),
+			want:   []string{"change-a", "change-b", "change-c", "change-d", "change-e", "change-f", "change-g", "change-h", "poll", "change-i"},
+		},
+		"NotFull": {
+			reason: "Requests should be handed to the workqueue right away while it has room for them.",
+			adds:   []add{{"poll", Low}, {"change", High}},
+			want:   []string{"poll", "change"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := NewQueue(name, 1)
+			q.after = func(time.Duration, func()) {}
+			wq := workqueue.New()
+			defer wq.ShutDown()
+
+			var got []string
+			take := func() {
+				for wq.Len() > 0 {
+					item, _ := wq.Get()
+					got = append(got, item.(reconcile.Request).Name)
+					wq.Done(item)
+				}
+			}
+			if tc.full {
+				wq.Add(req("busy"))
+			}
+			for _, a := range tc.adds {
+				q.Add(wq, req(a.name), a.p)
+				if !tc.full {
+					take()
+				}
+			}
+			if tc.full {
+				item, _ := wq.Get()
+				wq.Done(item)
+			}
+			for {
+				q.Pump()
+				if wq.Len() == 0 {
+					break
+				}
+				take()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nQueue: -want order, +got order:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueueAddAfter(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		delays []time.Duration
+		// want are the delays after which the request is handed to the
+		// workqueue.
+		want []time.Duration
+	}{
+		"Once": {
+			reason: "A request that is added twice with the same delay should be handed to the workqueue once.",
+			delays: []time.Duration{time.Minute, time.Minute},
+			want:   []time.Duration{time.Minute},
+		},
+		"Earlier": {
+			reason: "A request that is added again with a shorter delay should be handed to the workqueue once, after the shorter one.",
+			delays: []time.Duration{time.Minute, time.Second},
+			want:   []time.Duration{time.Second},
+		},
+		"Later": {
+			reason: "A request that is added again with a longer delay should be handed to the workqueue once, after the shorter one.",
+			delays: []time.Duration{time.Second, time.Minute},
+			want:   []time.Duration{time.Second},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			type timer struct {
+				d time.Duration
+				f func()
+			}
+			var timers []timer
+			now := time.Unix(0, 0)
+			q := NewQueue(name, 1)
+			q.now = func() time.Time { return now }
+			q.after = func(d time.Duration, f func()) { timers = append(timers, timer{d: d, f: f}) }
+			wq := workqueue.New()
+			defer wq.ShutDown()
+			// The workqueue is handed to the Queue with the first request.
+			q.Add(wq, req("first"), High)
+			item, _ := wq.Get()
+			wq.Done(item)
+
+			for _, d := range tc.delays {
+				q.AddAfter(req("poll"), Low, d)
+			}
+			sort.SliceStable(timers, func(i, j int) bool { return timers[i].d < timers[j].d })
+			var got []time.Duration
+			for _, tm := range timers {
+				tm.f()
+				for wq.Len() > 0 {
+					item, _ := wq.Get()
+					got = append(got, tm.d)
+					wq.Done(item)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nAddAfter(...): -want delays, +got delays:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPriorities(t *testing.T) {
+	synced := func() *v1beta1.Bucket {
+		b := &v1beta1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "b", Generation: 1}}
+		b.SetConditions(xpv1.ReconcileSuccess())
+		return b
+	}
+	changed := synced()
+	changed.SetGeneration(2)
+	deleted := synced()
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)})
+	status := synced()
+	status.SetConditions(xpv1.Available())
+
+	cases := map[string]struct {
+		reason string
+		prio   func() Priority
+		want   Priority
+	}{
+		"Created": {
+			reason: "The creation of a managed resource that was never synced should have high priority.",
+			prio:   func() Priority { return createPriority(&v1beta1.Bucket{}) },
+			want:   High,
+		},
+		"Listed": {
+			reason: "The creation of a managed resource that was already synced is a resync, which should have low priority.",
+			prio:   func() Priority { return createPriority(synced()) },
+			want:   Low,
+		},
+		"SpecChanged": {
+			reason: "A new generation of a managed resource should have high priority.",
+			prio:   func() Priority { return updatePriority(synced(), changed) },
+			want:   High,
+		},
+		"Deleted": {
+			reason: "The deletion of a managed resource should have high priority.",
+			prio:   func() Priority { return updatePriority(synced(), deleted) },
+			want:   High,
+		},
+		"StatusChanged": {
+			reason: "A change to the status of a managed resource should have low priority.",
+			prio:   func() Priority { return updatePriority(synced(), status) },
+			want:   Low,
+		},
+		"Resynced": {
+			reason: "A resync of the informer should have low priority.",
+			prio:   func() Priority { return updatePriority(synced(), synced()) },
+			want:   Low,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.prio()); diff != "" {
+				t.Errorf("\n%s\npriority: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
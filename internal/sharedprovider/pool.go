@@ -0,0 +1,412 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package sharedprovider runs a supervised pool of native Terraform provider
+// processes that are shared by the workspaces.
+package sharedprovider
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+)
+
+const (
+	defaultProtocolVersion = 5
+
+	// unhealthyThreshold is the number of consecutive failed health checks
+	// after which a process is restarted.
+	unhealthyThreshold = 3
+	healthTimeout      = 5 * time.Second
+
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+
+	errNoProcess = "no native provider process became ready in time"
+)
+
+// Restart reasons of the processes.
+const (
+	ReasonExited     = "exited"
+	ReasonUnhealthy  = "unhealthy"
+	ReasonOperations = "operations"
+	ReasonMemory     = "memory"
+)
+
+// An Option configures a Pool.
+type Option func(*Pool)
+
+// WithNativeProviderArgs sets the arguments passed to the native provider.
+func WithNativeProviderArgs(args ...string) Option {
+	return func(p *Pool) {
+		p.args = args
+	}
+}
+
+// WithProcesses sets the number of native provider processes of the pool.
+func WithProcesses(n int) Option {
+	return func(p *Pool) {
+		p.size = n
+	}
+}
+
+// WithMaxOperations sets the number of workspace operations after which a
+// process is recycled. Processes are not recycled after any number of
+// operations if it is zero.
+func WithMaxOperations(n int) Option {
+	return func(p *Pool) {
+		p.maxOperations = n
+	}
+}
+
+// WithMaxMemory sets the resident memory in bytes above which a process is
+// recycled. Processes are not recycled for their memory usage if it is zero.
+func WithMaxMemory(bytes int64) Option {
+	return func(p *Pool) {
+		p.maxMemory = bytes
+	}
+}
+
+// WithHealthInterval sets the interval the processes are checked at.
+func WithHealthInterval(d time.Duration) Option {
+	return func(p *Pool) {
+		p.healthInterval = d
+	}
+}
+
+// WithDrainTimeout sets the time a recycled process is kept running after it
+// was last handed out, so that the operations started with it can finish.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(p *Pool) {
+		p.drainTimeout = d
+	}
+}
+
+// slot is a place in the pool for a process.
+type slot struct {
+	id       string
+	proc     *process
+	starting bool
+
+	// restarts is the number of consecutive failed starts or crashes of the
+	// processes in the slot, which the restarts are backed off by.
+	restarts  int
+	notBefore time.Time
+}
+
+// backoff delays the next start of a process in the slot.
+func (s *slot) backoff(now time.Time) {
+	d := minBackoff << s.restarts
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	s.restarts++
+	s.notBefore = now.Add(d)
+}
+
+// Pool is a terraform.ProviderRunner that runs a pool of native provider
+// processes and hands them out to the workspaces in turn. A Supervisor
+// restarts the processes that crash or become unhealthy and recycles those
+// that served too many operations or use too much memory.
+type Pool struct {
+	path            string
+	args            []string
+	name            string
+	protocolVersion int
+
+	size           int
+	maxOperations  int
+	maxMemory      int64
+	healthInterval time.Duration
+	drainTimeout   time.Duration
+
+	launch func() (*process, error)
+	check  func(ctx context.Context, pr *process) error
+	memory func(pr *process) (int64, error)
+	log    logging.Logger
+
+	mu       sync.Mutex
+	slots    []*slot
+	draining []*process
+	next     int
+	// ready is closed and replaced whenever a process becomes ready.
+	ready chan struct{}
+}
+
+// NewPool returns a new Pool of the native provider at the given path, which
+// is the provider with the given name, such as
+// registry.terraform.io/hashicorp/aws.
+func NewPool(l logging.Logger, path, name string, opts ...Option) *Pool {
+	p := &Pool{
+		path:            path,
+		name:            name,
+		protocolVersion: defaultProtocolVersion,
+		size:            1,
+		healthInterval:  30 * time.Second,
+		drainTimeout:    time.Hour,
+		check:           checkHealth,
+		memory:          residentMemory,
+		log:             l,
+		ready:           make(chan struct{}),
+	}
+	p.launch = func() (*process, error) {
+		return launch(p.path, p.args, p.name, p.protocolVersion)
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	if p.size < 1 {
+		p.size = 1
+	}
+	p.slots = make([]*slot, p.size)
+	for i := range p.slots {
+		p.slots[i] = &slot{id: strconv.Itoa(i)}
+	}
+	return p
+}
+
+// Start returns the reattach configuration of the next ready process of the
+// pool, starting the processes if they are not running yet.
+func (p *Pool) Start() (string, error) {
+	timeout := time.After(reattachTimeout)
+	for {
+		p.mu.Lock()
+		now := time.Now()
+		s := p.pick(now)
+		p.spawn(now)
+		if s != nil {
+			pr := s.proc
+			pr.operations++
+			pr.lastUse = now
+			p.mu.Unlock()
+			metrics.NativeProviderOperations.WithLabelValues(s.id).Inc()
+			return pr.reattach, nil
+		}
+		ready, wake := p.ready, p.wake(now)
+		p.mu.Unlock()
+		select {
+		case <-ready:
+		case <-wake:
+		case <-timeout:
+			return "", errors.New(errNoProcess)
+		}
+	}
+}
+
+// pick returns the slot of the next ready process in turn, if any. The
+// processes it finds to have exited are restarted with a backoff rather than
+// at the next supervision.
+func (p *Pool) pick(now time.Time) *slot {
+	for i := 0; i < len(p.slots); i++ {
+		s := p.slots[(p.next+i)%len(p.slots)]
+		if s.proc == nil {
+			continue
+		}
+		if s.proc.exited() {
+			p.replace(s, ReasonExited, now)
+			continue
+		}
+		p.next = (p.next + i + 1) % len(p.slots)
+		return s
+	}
+	return nil
+}
+
+// wake returns a channel that receives when the backoff of the first empty
+// slot elapses, or nil if no empty slot is backed off.
+func (p *Pool) wake(now time.Time) <-chan time.Time {
+	var next time.Time
+	for _, s := range p.slots {
+		if s.proc != nil || s.starting || !now.Before(s.notBefore) {
+			continue
+		}
+		if next.IsZero() || s.notBefore.Before(next) {
+			next = s.notBefore
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return time.After(next.Sub(now))
+}
+
+// spawn starts the processes of the empty slots whose backoff has elapsed.
+func (p *Pool) spawn(now time.Time) {
+	for _, s := range p.slots {
+		if s.proc != nil || s.starting || now.Before(s.notBefore) {
+			continue
+		}
+		s.starting = true
+		go p.run(s)
+	}
+}
+
+func (p *Pool) run(s *slot) {
+	pr, err := p.launch()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.starting = false
+	if err != nil {
+		p.log.Info("Cannot start native provider", "process", s.id, "error", err)
+		s.backoff(time.Now())
+		return
+	}
+	p.log.Debug("Started native provider", "process", s.id, "pid", pr.pid)
+	s.proc = pr
+	metrics.NativeProviderUp.WithLabelValues(s.id).Set(1)
+	close(p.ready)
+	p.ready = make(chan struct{})
+}
+
+// health is the result of checking a process.
+type health struct {
+	err    error
+	memory int64
+}
+
+// supervise checks the processes of the pool, restarts those that crashed or
+// are unhealthy, recycles those that are worn out and stops the recycled
+// processes that have drained.
+func (p *Pool) supervise(ctx context.Context, now time.Time) {
+	p.mu.Lock()
+	procs := make([]*process, len(p.slots))
+	for i, s := range p.slots {
+		procs[i] = s.proc
+	}
+	p.mu.Unlock()
+
+	results := make([]health, len(procs))
+	for i, pr := range procs {
+		if pr == nil || pr.exited() {
+			continue
+		}
+		hctx, cancel := context.WithTimeout(ctx, healthTimeout)
+		results[i].err = p.check(hctx, pr)
+		cancel()
+		// The memory usage is unknown on platforms without procfs, so the
+		// process is not recycled for it there.
+		if m, err := p.memory(pr); err == nil {
+			results[i].memory = m
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, s := range p.slots {
+		pr := procs[i]
+		// The process might have been replaced while it was checked.
+		if pr == nil || s.proc != pr {
+			continue
+		}
+		if reason := p.assess(pr, results[i]); reason != "" {
+			p.replace(s, reason, now)
+			continue
+		}
+		s.restarts = 0
+		metrics.NativeProviderMemoryBytes.WithLabelValues(s.id).Set(float64(results[i].memory))
+	}
+	draining := p.draining[:0]
+	for _, pr := range p.draining {
+		if !pr.exited() && now.Sub(pr.lastUse) < p.drainTimeout {
+			draining = append(draining, pr)
+			continue
+		}
+		pr.kill()
+	}
+	p.draining = draining
+	p.spawn(now)
+}
+
+// assess returns the reason the given process must be replaced for, if any.
+func (p *Pool) assess(pr *process, h health) string {
+	switch {
+	case pr.exited():
+		return ReasonExited
+	case h.err != nil:
+		pr.failures++
+		p.log.Info("Native provider is unhealthy", "pid", pr.pid, "error", h.err)
+		if pr.failures >= unhealthyThreshold {
+			return ReasonUnhealthy
+		}
+		return ""
+	case p.maxOperations > 0 && pr.operations >= p.maxOperations:
+		return ReasonOperations
+	case p.maxMemory > 0 && h.memory >= p.maxMemory:
+		return ReasonMemory
+	}
+	pr.failures = 0
+	return ""
+}
+
+// replace replaces the process of the given slot for the given reason. Worn
+// out processes are replaced right away and drained, while crashed and
+// unhealthy processes are stopped and restarted with a backoff.
+func (p *Pool) replace(s *slot, reason string, now time.Time) {
+	p.log.Info("Restarting native provider", "process", s.id, "pid", s.proc.pid, "reason", reason)
+	metrics.NativeProviderRestarts.WithLabelValues(s.id, reason).Inc()
+	metrics.NativeProviderUp.WithLabelValues(s.id).Set(0)
+	switch reason {
+	case ReasonOperations, ReasonMemory:
+		p.draining = append(p.draining, s.proc)
+	default:
+		s.proc.kill()
+		s.backoff(now)
+	}
+	s.proc = nil
+}
+
+// stop stops all the processes of the pool.
+func (p *Pool) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.slots {
+		if s.proc != nil {
+			s.proc.kill()
+			s.proc = nil
+			metrics.NativeProviderUp.WithLabelValues(s.id).Set(0)
+		}
+	}
+	for _, pr := range p.draining {
+		pr.kill()
+	}
+	p.draining = nil
+}
+
+// Supervisor supervises the processes of a Pool.
+type Supervisor struct {
+	pool *Pool
+}
+
+// NewSupervisor returns a new Supervisor of the given Pool.
+func NewSupervisor(p *Pool) *Supervisor {
+	return &Supervisor{pool: p}
+}
+
+// NeedLeaderElection returns false since every replica of the provider runs
+// its own native providers.
+func (s *Supervisor) NeedLeaderElection() bool {
+	return false
+}
+
+// Start supervises the processes of the pool at every health interval until
+// the given context is done, and stops them then.
+func (s *Supervisor) Start(ctx context.Context) error {
+	t := time.NewTicker(s.pool.healthInterval)
+	defer t.Stop()
+	defer s.pool.stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			s.pool.supervise(ctx, time.Now())
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package sharedprovider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// fakeProcesses launches fake processes whose reattach configurations are
+// numbered in the order they are launched.
+type fakeProcesses struct {
+	mu       sync.Mutex
+	launched []*process
+	killed   int
+}
+
+func (f *fakeProcesses) launch() (*process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pr := &process{reattach: fmt.Sprintf("p%d", len(f.launched)), done: make(chan struct{})}
+	pr.kill = func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.killed++
+	}
+	f.launched = append(f.launched, pr)
+	return pr, nil
+}
+
+func newTestPool(f *fakeProcesses, opts ...Option) *Pool {
+	p := NewPool(logging.NewNopLogger(), "terraform-provider-aws", "registry.terraform.io/hashicorp/aws", opts...)
+	p.launch = f.launch
+	p.check = func(context.Context, *process) error { return nil }
+	p.memory = func(*process) (int64, error) { return 0, nil }
+	return p
+}
+
+func start(t *testing.T, p *Pool, n int) []string {
+	t.Helper()
+	got := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		c, err := p.Start()
+		if err != nil {
+			t.Fatalf("Start(): %v", err)
+		}
+		got = append(got, c)
+	}
+	return got
+}
+
+func TestStart(t *testing.T) {
+	f := &fakeProcesses{}
+	p := newTestPool(f, WithProcesses(2))
+	// The processes are started in the background and only the first one
+	// to run is waited for.
+	start(t, p, 1)
+	for {
+		p.mu.Lock()
+		ready := p.slots[0].proc != nil && p.slots[1].proc != nil
+		p.mu.Unlock()
+		if ready {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	got := start(t, p, 4)
+	if diff := cmp.Diff(got[0:2], got[2:4]); diff != "" || got[0] == got[1] {
+		t.Errorf("Start(): the processes should be handed out in turn, got %v", got)
+	}
+}
+
+func TestStartExited(t *testing.T) {
+	f := &fakeProcesses{}
+	p := newTestPool(f)
+	start(t, p, 1)
+	close(f.launched[0].done)
+	// The process that exited is restarted once its backoff elapses, which
+	// is waited for rather than the next supervision.
+	got := start(t, p, 1)
+	if diff := cmp.Diff([]string{"p1"}, got); diff != "" {
+		t.Errorf("Start(): -want, +got:\n%s", diff)
+	}
+	f.mu.Lock()
+	killed := f.killed
+	f.mu.Unlock()
+	if diff := cmp.Diff(1, killed); diff != "" {
+		t.Errorf("Start(): -want killed, +got killed:\n%s", diff)
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		reason  string
+		opts    []Option
+		prepare func(p *Pool, pr *process)
+		// restarted is whether the process should be replaced and
+		// delayed whether its replacement should be backed off.
+		restarted bool
+		delayed   bool
+		draining  int
+	}{
+		"Healthy": {
+			reason: "A healthy process should be kept.",
+		},
+		"Exited": {
+			reason:    "A process that exited should be restarted with a backoff.",
+			prepare:   func(_ *Pool, pr *process) { close(pr.done) },
+			restarted: true,
+			delayed:   true,
+		},
+		"Unhealthy": {
+			reason: "A process that failed consecutive health checks should be restarted with a backoff.",
+			prepare: func(p *Pool, pr *process) {
+				p.check = func(context.Context, *process) error { return errors.New("boom") }
+				pr.failures = unhealthyThreshold - 1
+			},
+			restarted: true,
+			delayed:   true,
+		},
+		"FailedHealthCheck": {
+			reason: "A process that failed fewer health checks than the threshold should be kept.",
+			prepare: func(p *Pool, _ *process) {
+				p.check = func(context.Context, *process) error { return errors.New("boom") }
+			},
+		},
+		"MaxOperations": {
+			reason:    "A process that served the maximum number of operations should be drained and replaced right away.",
+			opts:      []Option{WithMaxOperations(10)},
+			prepare:   func(_ *Pool, pr *process) { pr.operations = 10; pr.lastUse = now },
+			restarted: true,
+			draining:  1,
+		},
+		"MaxMemory": {
+			reason: "A process that uses more than the maximum memory should be drained and replaced right away.",
+			opts:   []Option{WithMaxMemory(1 << 30)},
+			prepare: func(p *Pool, pr *process) {
+				p.memory = func(*process) (int64, error) { return 2 << 30, nil }
+				pr.lastUse = now
+			},
+			restarted: true,
+			draining:  1,
+		},
+		"Drained": {
+			reason:    "A recycled process that was not handed out for the drain timeout should be stopped.",
+			opts:      []Option{WithMaxOperations(10), WithDrainTimeout(time.Minute)},
+			prepare:   func(_ *Pool, pr *process) { pr.operations = 10; pr.lastUse = now.Add(-time.Hour) },
+			restarted: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeProcesses{}
+			p := newTestPool(f, tc.opts...)
+			start(t, p, 1)
+			pr := f.launched[0]
+			pr.operations, pr.lastUse = 0, time.Time{}
+			if tc.prepare != nil {
+				tc.prepare(p, pr)
+			}
+			p.supervise(context.Background(), now)
+
+			p.mu.Lock()
+			s := p.slots[0]
+			restarted := s.proc != pr
+			delayed := s.notBefore.After(now)
+			draining := len(p.draining)
+			p.mu.Unlock()
+			if diff := cmp.Diff(tc.restarted, restarted); diff != "" {
+				t.Errorf("\n%s\nsupervise(...): -want restarted, +got restarted:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.delayed, delayed); diff != "" {
+				t.Errorf("\n%s\nsupervise(...): -want delayed, +got delayed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.draining, draining); diff != "" {
+				t.Errorf("\n%s\nsupervise(...): -want draining, +got draining:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	now := time.Now()
+	s := &slot{}
+	var got []time.Duration
+	for i := 0; i < 11; i++ {
+		s.backoff(now)
+		got = append(got, s.notBefore.Sub(now))
+	}
+	want := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second,
+		64 * time.Second, 128 * time.Second, 256 * time.Second, maxBackoff, maxBackoff,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("backoff(...): -want delays, +got delays:\n%s", diff)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package sharedprovider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// fmtReattachEnv is the value of the TF_REATTACH_PROVIDERS environment
+	// variable of the Terraform CLI that makes it use a running provider.
+	fmtReattachEnv = `{"%s":{"Protocol":"grpc","ProtocolVersion":%d,"Pid":%d,"Test":true,"Addr":{"Network":"unix","String":"%s"}}}`
+
+	// The native provider expects this magic cookie in its environment:
+	// https://github.com/hashicorp/terraform/blob/d35bc0531255b496beb5d932f185cbcdb2d61a99/internal/plugin/serve.go#L33
+	envMagicCookie = "TF_PLUGIN_MAGIC_COOKIE"
+	valMagicCookie = "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2"
+
+	// healthService is the name of the gRPC health service the plugin
+	// servers of the native providers register.
+	healthService = "plugin"
+
+	reattachTimeout = time.Minute
+
+	errStartProcess = "cannot start the native provider"
+	errFmtExited    = "native provider exited before it could be used: %v"
+	errFmtTimeout   = "timed out after %v while waiting for the reattach configuration of the native provider"
+	errDial         = "cannot connect to the native provider"
+	errHealthCheck  = "cannot check the health of the native provider"
+	errFmtNotServed = "native provider is not serving: %s"
+	errReadMemory   = "cannot read the memory usage of the native provider"
+)
+
+var regexReattachLine = regexp.MustCompile(`.*unix\|(.*)\|grpc.*`)
+
+// process is a running native provider process.
+type process struct {
+	pid      int
+	addr     string
+	reattach string
+	kill     func()
+
+	// done is closed once the process has exited.
+	done chan struct{}
+	err  error
+
+	// operations is the number of times the process was handed out to a
+	// workspace and lastUse is the last time it was.
+	operations int
+	lastUse    time.Time
+
+	// failures is the number of consecutive failed health checks.
+	failures int
+}
+
+// exited returns true if the process has exited.
+func (pr *process) exited() bool {
+	select {
+	case <-pr.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// launch starts the native provider at the given path with the given arguments
+// and returns once it serves.
+func launch(path string, args []string, name string, protocolVersion int) (*process, error) {
+	//#nosec G204 no user input
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), envMagicCookie+"="+valMagicCookie)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, errStartProcess)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, errStartProcess)
+	}
+	pr := &process{
+		pid:  cmd.Process.Pid,
+		kill: func() { _ = cmd.Process.Kill() },
+		done: make(chan struct{}),
+	}
+	addr := make(chan string, 1)
+	go func() {
+		s := bufio.NewScanner(stdout)
+		for s.Scan() {
+			if m := regexReattachLine.FindStringSubmatch(s.Text()); m != nil {
+				addr <- m[1]
+				break
+			}
+		}
+		// The output is drained so that the process is never blocked on
+		// writing it.
+		_, _ = io.Copy(io.Discard, stdout)
+		pr.err = cmd.Wait()
+		close(pr.done)
+	}()
+	select {
+	case pr.addr = <-addr:
+		pr.reattach = fmt.Sprintf(fmtReattachEnv, name, protocolVersion, pr.pid, pr.addr)
+		return pr, nil
+	case <-pr.done:
+		return nil, errors.Errorf(errFmtExited, pr.err)
+	case <-time.After(reattachTimeout):
+		pr.kill()
+		return nil, errors.Errorf(errFmtTimeout, reattachTimeout)
+	}
+}
+
+// checkHealth checks the gRPC health service of the given process.
+func checkHealth(ctx context.Context, pr *process) error {
+	conn, err := grpc.DialContext(ctx, "unix://"+pr.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return errors.Wrap(err, errDial)
+	}
+	defer func() { _ = conn.Close() }()
+	res, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: healthService})
+	if err != nil {
+		return errors.Wrap(err, errHealthCheck)
+	}
+	if res.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return errors.Errorf(errFmtNotServed, res.GetStatus())
+	}
+	return nil
+}
+
+// residentMemory returns the resident set size of the given process in bytes.
+func residentMemory(pr *process) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pr.pid))
+	if err != nil {
+		return 0, errors.Wrap(err, errReadMemory)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, errors.New(errReadMemory)
+	}
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, errReadMemory)
+	}
+	return pages * int64(os.Getpagesize()), nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/docdb"
+	docdbtypes "github.com/aws/aws-sdk-go-v2/service/docdb/types"
+	"github.com/pkg/errors"
+)
+
+type docdbCluster struct {
+	client *docdb.Client
+}
+
+func newDocDBCluster(cfg aws.Config) *docdbCluster {
+	return &docdbCluster{client: docdb.NewFromConfig(cfg)}
+}
+
+func (s *docdbCluster) Type() string {
+	return "DBClusterSnapshot"
+}
+
+func (s *docdbCluster) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	t := make([]docdbtypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		t = append(t, docdbtypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err := s.client.CreateDBClusterSnapshot(ctx, &docdb.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(resourceID),
+		DBClusterSnapshotIdentifier: aws.String(id),
+		Tags:                        t,
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *docdbCluster) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeDBClusterSnapshots(ctx, &docdb.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.DBClusterSnapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.DBClusterSnapshots[0].Status) == stateAvailable, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/pkg/errors"
+)
+
+type elastiCacheReplicationGroup struct {
+	client *elasticache.Client
+}
+
+func newElastiCacheReplicationGroup(cfg aws.Config) *elastiCacheReplicationGroup {
+	return &elastiCacheReplicationGroup{client: elasticache.NewFromConfig(cfg)}
+}
+
+func (s *elastiCacheReplicationGroup) Type() string {
+	return "Snapshot"
+}
+
+func (s *elastiCacheReplicationGroup) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	t := make([]elasticachetypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		t = append(t, elasticachetypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err := s.client.CreateSnapshot(ctx, &elasticache.CreateSnapshotInput{
+		ReplicationGroupId: aws.String(resourceID),
+		SnapshotName:       aws.String(id),
+		Tags:               t,
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *elastiCacheReplicationGroup) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeSnapshots(ctx, &elasticache.DescribeSnapshotsInput{
+		SnapshotName: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.Snapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.Snapshots[0].SnapshotStatus) == stateAvailable, nil
+}
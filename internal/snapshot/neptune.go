@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/neptune"
+	neptunetypes "github.com/aws/aws-sdk-go-v2/service/neptune/types"
+	"github.com/pkg/errors"
+)
+
+type neptuneCluster struct {
+	client *neptune.Client
+}
+
+func newNeptuneCluster(cfg aws.Config) *neptuneCluster {
+	return &neptuneCluster{client: neptune.NewFromConfig(cfg)}
+}
+
+func (s *neptuneCluster) Type() string {
+	return "DBClusterSnapshot"
+}
+
+func (s *neptuneCluster) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	t := make([]neptunetypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		t = append(t, neptunetypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err := s.client.CreateDBClusterSnapshot(ctx, &neptune.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(resourceID),
+		DBClusterSnapshotIdentifier: aws.String(id),
+		Tags:                        t,
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *neptuneCluster) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeDBClusterSnapshots(ctx, &neptune.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.DBClusterSnapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.DBClusterSnapshots[0].Status) == stateAvailable, nil
+}
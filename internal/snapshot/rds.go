@@ -0,0 +1,96 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/pkg/errors"
+)
+
+func rdsTags(tags map[string]string) []rdstypes.Tag {
+	r := make([]rdstypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		r = append(r, rdstypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return r
+}
+
+type rdsInstance struct {
+	client *rds.Client
+}
+
+func newRDSInstance(cfg aws.Config) *rdsInstance {
+	return &rdsInstance{client: rds.NewFromConfig(cfg)}
+}
+
+func (s *rdsInstance) Type() string {
+	return "DBSnapshot"
+}
+
+func (s *rdsInstance) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	_, err := s.client.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(resourceID),
+		DBSnapshotIdentifier: aws.String(id),
+		Tags:                 rdsTags(tags),
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *rdsInstance) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.DBSnapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.DBSnapshots[0].Status) == stateAvailable, nil
+}
+
+type rdsCluster struct {
+	client *rds.Client
+}
+
+func newRDSCluster(cfg aws.Config) *rdsCluster {
+	return &rdsCluster{client: rds.NewFromConfig(cfg)}
+}
+
+func (s *rdsCluster) Type() string {
+	return "DBClusterSnapshot"
+}
+
+func (s *rdsCluster) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	_, err := s.client.CreateDBClusterSnapshot(ctx, &rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(resourceID),
+		DBClusterSnapshotIdentifier: aws.String(id),
+		Tags:                        rdsTags(tags),
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *rdsCluster) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeDBClusterSnapshots(ctx, &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.DBClusterSnapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.DBClusterSnapshots[0].Status) == stateAvailable, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+	"github.com/pkg/errors"
+)
+
+type redshiftCluster struct {
+	client *redshift.Client
+}
+
+func newRedshiftCluster(cfg aws.Config) *redshiftCluster {
+	return &redshiftCluster{client: redshift.NewFromConfig(cfg)}
+}
+
+func (s *redshiftCluster) Type() string {
+	return "ClusterSnapshot"
+}
+
+func (s *redshiftCluster) Create(ctx context.Context, id, resourceID string, tags map[string]string) error {
+	t := make([]redshifttypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		t = append(t, redshifttypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err := s.client.CreateClusterSnapshot(ctx, &redshift.CreateClusterSnapshotInput{
+		ClusterIdentifier:  aws.String(resourceID),
+		SnapshotIdentifier: aws.String(id),
+		Tags:               t,
+	})
+	if alreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *redshiftCluster) Available(ctx context.Context, id string) (bool, error) {
+	out, err := s.client.DescribeClusterSnapshots(ctx, &redshift.DescribeClusterSnapshotsInput{
+		SnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(out.Snapshots) == 0 {
+		return false, errors.Errorf(errNotFound, id)
+	}
+	return aws.ToString(out.Snapshots[0].Status) == stateAvailable, nil
+}
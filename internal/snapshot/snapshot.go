@@ -0,0 +1,109 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package snapshot takes the final snapshots of the external resources of
+// database managed resources before they are deleted.
+package snapshot
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxIdentifierLength is the maximum length of the snapshot identifiers,
+	// which is the lowest limit of the supported services.
+	maxIdentifierLength = 63
+
+	stateAvailable = "available"
+
+	errNotFound = "snapshot %s is not found"
+)
+
+// A Snapshotter takes snapshots of external resources.
+type Snapshotter interface {
+	// Type returns the type of the snapshots, such as DBSnapshot.
+	Type() string
+
+	// Create starts taking a snapshot with the given identifier and tags of
+	// the external resource with the given identifier. It does not return
+	// an error if the snapshot already exists.
+	Create(ctx context.Context, id, resourceID string, tags map[string]string) error
+
+	// Available returns true if the snapshot with the given identifier is
+	// available.
+	Available(ctx context.Context, id string) (bool, error)
+}
+
+// snapshotters are the constructors of the Snapshotters of the Terraform
+// resources whose final snapshots are taken.
+var snapshotters = map[string]func(cfg aws.Config) Snapshotter{
+	"aws_db_instance":                   func(cfg aws.Config) Snapshotter { return newRDSInstance(cfg) },
+	"aws_rds_cluster":                   func(cfg aws.Config) Snapshotter { return newRDSCluster(cfg) },
+	"aws_docdb_cluster":                 func(cfg aws.Config) Snapshotter { return newDocDBCluster(cfg) },
+	"aws_neptune_cluster":               func(cfg aws.Config) Snapshotter { return newNeptuneCluster(cfg) },
+	"aws_redshift_cluster":              func(cfg aws.Config) Snapshotter { return newRedshiftCluster(cfg) },
+	"aws_elasticache_replication_group": func(cfg aws.Config) Snapshotter { return newElastiCacheReplicationGroup(cfg) },
+}
+
+// Supported returns true if final snapshots are taken of the given Terraform
+// resource.
+func Supported(resource string) bool {
+	_, ok := snapshotters[resource]
+	return ok
+}
+
+// New returns the Snapshotter of the given Terraform resource, or false if
+// final snapshots are not taken of it.
+func New(resource string, cfg aws.Config) (Snapshotter, bool) {
+	fn, ok := snapshotters[resource]
+	if !ok {
+		return nil, false
+	}
+	return fn(cfg), true
+}
+
+var invalidCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Identifier returns a unique snapshot identifier for the external resource
+// with the given identifier. It starts with a letter, contains only lower
+// case letters, digits and single hyphens and ends with the given time, so
+// that it is valid for all the supported services.
+func Identifier(resourceID string, t time.Time) string {
+	suffix := "-" + t.UTC().Format("20060102150405")
+	name := strings.Trim(invalidCharacters.ReplaceAllString(strings.ToLower(resourceID), "-"), "-")
+	name = "final-" + name
+	if max := maxIdentifierLength - len(suffix); len(name) > max {
+		name = strings.TrimRight(name[:max], "-")
+	}
+	return name + suffix
+}
+
+// alreadyExists returns true if the given error reports that a snapshot
+// already exists.
+func alreadyExists(err error) bool {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return false
+	}
+	return strings.Contains(ae.ErrorCode(), "AlreadyExists")
+}
+
+// sortedKeys returns the keys of the given tags in order, so that the tags
+// are always sent in the same order.
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIdentifier(t *testing.T) {
+	at := time.Date(2022, 10, 3, 14, 5, 9, 0, time.UTC)
+	cases := map[string]struct {
+		reason     string
+		resourceID string
+		want       string
+	}{
+		"Simple": {
+			reason:     "The identifier should contain the resource identifier and the time.",
+			resourceID: "orders-db",
+			want:       "final-orders-db-20221003140509",
+		},
+		"InvalidCharacters": {
+			reason:     "Upper case letters should be lowered and other invalid characters replaced with single hyphens.",
+			resourceID: "Orders_DB--primary.",
+			want:       "final-orders-db-primary-20221003140509",
+		},
+		"Long": {
+			reason:     "Long identifiers should be truncated without a trailing hyphen.",
+			resourceID: strings.Repeat("a", 41) + "-b",
+			want:       "final-" + strings.Repeat("a", 41) + "-20221003140509",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Identifier(tc.resourceID, at)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIdentifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if len(got) > maxIdentifierLength {
+				t.Errorf("\n%s\nIdentifier(...): length %d exceeds %d", tc.reason, len(got), maxIdentifierLength)
+			}
+		})
+	}
+}
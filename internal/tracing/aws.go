@@ -0,0 +1,38 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const apiCallSpansID = "ProviderAWSAPICallSpans"
+
+// AddAPICallSpans adds a middleware to the given AWS SDK stack that opens a
+// span for every API call made with it. It's added to the end of the
+// initialize step so that the service metadata of the operation is already
+// available in the context.
+func AddAPICallSpans(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(apiCallSpansID, func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+		ctx, span := Start(ctx, service+"."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+			attribute.String("rpc.system", "aws-api"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", operation),
+			attribute.String("aws.region", awsmiddleware.GetRegion(ctx)),
+		))
+		out, md, err := next.HandleInitialize(ctx, in)
+		if id, ok := awsmiddleware.GetRequestIDMetadata(md); ok {
+			span.SetAttributes(attribute.String("aws.request_id", id))
+		}
+		End(span, err)
+		return out, md, err
+	}), middleware.After)
+}
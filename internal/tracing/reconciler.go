@@ -0,0 +1,38 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler opens a span for every reconcile of the wrapped reconciler.
+type Reconciler struct {
+	inner reconcile.Reconciler
+	gvk   schema.GroupVersionKind
+}
+
+// NewReconciler returns a Reconciler that traces the reconciles of the given
+// reconciler for the managed resources of the given kind.
+func NewReconciler(r reconcile.Reconciler, gvk schema.GroupVersionKind) *Reconciler {
+	return &Reconciler{inner: r, gvk: gvk}
+}
+
+// Reconcile opens the reconcile span and calls the wrapped reconciler with it.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Start(ctx, "Reconcile "+r.gvk.Kind)
+	span.SetAttributes(
+		AttrGroup.String(r.gvk.Group),
+		AttrVersion.String(r.gvk.Version),
+		AttrKind.String(r.gvk.Kind),
+		AttrName.String(req.Name),
+	)
+	res, err := r.inner.Reconcile(ctx, req)
+	End(span, err)
+	return res, err
+}
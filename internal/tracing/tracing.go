@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package tracing contains the OpenTelemetry tracing setup of the provider
+// and helpers to open spans for reconciles, credential resolution, Terraform
+// operations and AWS API calls.
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/upbound/provider-aws/internal/version"
+)
+
+const (
+	tracerName  = "github.com/upbound/provider-aws"
+	serviceName = "provider-aws"
+)
+
+// Span attribute keys.
+const (
+	AttrGroup        = attribute.Key("crossplane.resource.group")
+	AttrVersion      = attribute.Key("crossplane.resource.version")
+	AttrKind         = attribute.Key("crossplane.resource.kind")
+	AttrName         = attribute.Key("crossplane.resource.name")
+	AttrExternalName = attribute.Key("crossplane.resource.external_name")
+)
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC collector. Tracing is
+	// disabled if it's empty.
+	Endpoint string
+
+	// Insecure disables the transport security of the connection to the
+	// collector.
+	Insecure bool
+
+	// SampleRatio is the ratio of the root spans that are sampled. Child
+	// spans follow the sampling decision of their parent.
+	SampleRatio float64
+}
+
+// Setup configures the global OpenTelemetry tracer provider to export spans
+// to an OTLP collector with the given options. The returned function flushes
+// the remaining spans and shuts down the exporter. If no endpoint is given,
+// the global no-op tracer provider is kept and the returned function does
+// nothing.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	eo := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		eo = append(eo, otlptracegrpc.WithInsecure())
+	}
+	exp, err := otlptracegrpc.New(ctx, eo...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create OTLP trace exporter")
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(version.Version),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build tracing resource")
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Start opens a new span with the given name as a child of the span in the
+// given context, if any.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End records the given error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// span is the part of a recorded span that the tests compare.
+type span struct {
+	Name       string
+	Attributes map[attribute.Key]string
+	Status     codes.Code
+}
+
+// record records the spans opened by the tests until they end.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return sr
+}
+
+// ended returns the spans that ended.
+func ended(sr *tracetest.SpanRecorder) []span {
+	var spans []span
+	for _, s := range sr.Ended() {
+		attrs := map[attribute.Key]string{}
+		for _, kv := range s.Attributes() {
+			attrs[kv.Key] = kv.Value.Emit()
+		}
+		spans = append(spans, span{Name: s.Name(), Attributes: attrs, Status: s.Status().Code})
+	}
+	return spans
+}
+
+func TestReconciler(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	attrs := map[attribute.Key]string{
+		AttrGroup:   "s3.aws.upbound.io",
+		AttrVersion: "v1beta1",
+		AttrKind:    "Bucket",
+		AttrName:    "bucket",
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   []span
+	}{
+		"Reconciled": {
+			reason: "A span with the kind and the name of the managed resource should be opened for a reconcile.",
+			want:   []span{{Name: "Reconcile Bucket", Attributes: attrs}},
+		},
+		"Error": {
+			reason: "The span of a failed reconcile should record the error.",
+			err:    errors.New("boom"),
+			want:   []span{{Name: "Reconcile Bucket", Attributes: attrs, Status: codes.Error}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := record(t)
+			r := NewReconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, tc.err
+			}), gvk)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "bucket"}}); !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nReconcile(...): want error %v, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(tc.want, ended(sr)); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want spans, +got spans:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAddAPICallSpans(t *testing.T) {
+	attrs := map[attribute.Key]string{
+		"rpc.system":  "aws-api",
+		"rpc.service": "S3",
+		"rpc.method":  "GetObject",
+		"aws.region":  "us-east-1",
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   []span
+	}{
+		"Called": {
+			reason: "A span with the service and the operation should be opened for an AWS API call.",
+			want:   []span{{Name: "S3.GetObject", Attributes: attrs}},
+		},
+		"Error": {
+			reason: "The span of a failed AWS API call should record the error.",
+			err:    errors.New("boom"),
+			want:   []span{{Name: "S3.GetObject", Attributes: attrs, Status: codes.Error}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := record(t)
+			stack := middleware.NewStack("test", func() any { return struct{}{} })
+			if err := stack.Initialize.Add(&awsmiddleware.RegisterServiceMetadata{ServiceID: "S3", OperationName: "GetObject", Region: "us-east-1"}, middleware.Before); err != nil {
+				t.Fatal(err)
+			}
+			if err := AddAPICallSpans(stack); err != nil {
+				t.Fatal(err)
+			}
+			h := middleware.DecorateHandler(middleware.HandlerFunc(func(context.Context, any) (any, middleware.Metadata, error) {
+				return nil, middleware.Metadata{}, tc.err
+			}), stack)
+			if _, _, err := h.Handle(context.Background(), struct{}{}); !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nHandle(...): want error %v, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(tc.want, ended(sr)); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want spans, +got spans:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("\nTracing should be disabled without an endpoint.\nSetup(...): %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("\nTracing should be disabled without an endpoint.\nshutdown(...): %v", err)
+	}
+	if otel.GetTracerProvider() != prev {
+		t.Errorf("\nTracing should be disabled without an endpoint.\nSetup(...): the global tracer provider was replaced")
+	}
+}
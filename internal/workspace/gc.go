@@ -0,0 +1,331 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+// Package workspace manages the disk usage of the Terraform workspaces of the
+// managed resources.
+package workspace
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/provider-aws/internal/metrics"
+	"github.com/upbound/provider-aws/internal/persistence"
+)
+
+const (
+	// minIdle is the time a workspace must not have been used for to be
+	// removed, so that the workspaces of managed resources that are not yet
+	// in the cache and those about to be used are kept.
+	minIdle = time.Minute
+
+	// Removal reasons of the workspaces.
+	reasonOrphaned = "orphaned"
+	reasonEvicted  = "evicted"
+
+	errReadWorkspaces   = "cannot read the workspace directories"
+	errListManaged      = "cannot list the managed resources"
+	errRemoveWorkspace  = "cannot remove the workspace directory"
+	errWorkspaceDiskUse = "cannot compute the disk usage of the workspace"
+	errListStates       = "cannot list the persisted workspace states"
+	errLoadState        = "cannot load the persisted workspace state"
+	errDeleteState      = "cannot delete the persisted workspace state"
+)
+
+// A GCOption configures a GC.
+type GCOption func(*GC)
+
+// WithInterval sets the interval the workspaces are collected at.
+func WithInterval(d time.Duration) GCOption {
+	return func(g *GC) {
+		g.interval = d
+	}
+}
+
+// WithDiskBudget sets the maximum number of bytes the workspaces may use on
+// disk. The least recently used workspaces are evicted while they use more.
+// The disk usage of the workspaces is not limited if it is zero.
+func WithDiskBudget(bytes int64) GCOption {
+	return func(g *GC) {
+		g.budget = bytes
+	}
+}
+
+// WithInUse sets the function that reports whether the workspace of the
+// managed resource with the given UID is in use, such as by a running sync or
+// async operation, and must not be evicted.
+func WithInUse(fn func(uid types.UID) bool) GCOption {
+	return func(g *GC) {
+		g.inUse = fn
+	}
+}
+
+// WithStateStore sets the Store of the persisted workspace states, whose
+// states of managed resources that no longer exist are deleted along with
+// their workspaces.
+func WithStateStore(st persistence.Store) GCOption {
+	return func(g *GC) {
+		g.states = st
+	}
+}
+
+// GC periodically removes the workspaces of managed resources that no longer
+// exist, such as those deleted while the provider was not running, and
+// evicts the least recently used workspaces while the workspaces use more
+// disk than their budget. Evicted workspaces are created again the next time
+// their managed resources are reconciled.
+type GC struct {
+	kube     client.Reader
+	scheme   *runtime.Scheme
+	root     string
+	interval time.Duration
+	budget   int64
+	inUse    func(uid types.UID) bool
+	states   persistence.Store
+	log      logging.Logger
+}
+
+// NewGC returns a GC of the workspaces in the given root directory, whose
+// names are the UIDs of the managed resources of the kinds registered with
+// the given scheme.
+func NewGC(kube client.Reader, s *runtime.Scheme, root string, l logging.Logger, opts ...GCOption) *GC {
+	g := &GC{
+		kube:     kube,
+		scheme:   s,
+		root:     root,
+		interval: 10 * time.Minute,
+		inUse:    func(types.UID) bool { return false },
+		log:      l,
+	}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
+}
+
+// NeedLeaderElection returns false since every replica of the provider has
+// its own workspaces.
+func (g *GC) NeedLeaderElection() bool {
+	return false
+}
+
+// Start collects the workspaces at every interval until the given context is
+// done.
+func (g *GC) Start(ctx context.Context) error {
+	t := time.NewTicker(g.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := g.Collect(ctx, time.Now()); err != nil {
+				g.log.Info("Cannot collect the workspaces", "error", err)
+			}
+		}
+	}
+}
+
+// dir is a workspace directory.
+type dir struct {
+	uid     types.UID
+	path    string
+	size    int64
+	lastUse time.Time
+}
+
+// Collect removes the orphaned workspaces and evicts the least recently used
+// workspaces that exceed the disk budget.
+func (g *GC) Collect(ctx context.Context, now time.Time) error {
+	dirs, err := g.dirs()
+	if err != nil {
+		return err
+	}
+	// The disk usage is still limited if the managed resources cannot be
+	// listed.
+	uids, err := g.managedUIDs(ctx)
+	if err != nil {
+		g.log.Info("Cannot remove orphaned workspaces", "error", err)
+	}
+	if uids != nil && g.states != nil {
+		if err := g.collectStates(ctx, uids, now); err != nil {
+			g.log.Info("Cannot remove orphaned workspace states", "error", err)
+		}
+	}
+	kept := dirs[:0]
+	for _, d := range dirs {
+		_, exists := uids[d.uid]
+		if uids == nil || exists || now.Sub(d.lastUse) < minIdle {
+			kept = append(kept, d)
+			continue
+		}
+		if err := g.remove(d, reasonOrphaned); err != nil {
+			return err
+		}
+	}
+	dirs = kept
+
+	var total int64
+	for _, d := range dirs {
+		total += d.size
+	}
+	if g.budget > 0 && total > g.budget {
+		sort.Slice(dirs, func(i, j int) bool { return dirs[i].lastUse.Before(dirs[j].lastUse) })
+		kept = dirs[:0]
+		for _, d := range dirs {
+			if total <= g.budget || now.Sub(d.lastUse) < minIdle || g.inUse(d.uid) {
+				kept = append(kept, d)
+				continue
+			}
+			if err := g.remove(d, reasonEvicted); err != nil {
+				return err
+			}
+			total -= d.size
+		}
+		dirs = kept
+		if total > g.budget {
+			g.log.Info("Workspaces in use exceed the disk budget", "bytes", total, "budget", g.budget)
+		}
+	}
+	metrics.TerraformWorkspaceDirectories.Set(float64(len(dirs)))
+	metrics.TerraformWorkspaceDiskBytes.Set(float64(total))
+	return nil
+}
+
+// collectStates deletes the persisted states of the workspaces of the
+// managed resources that are not among the given ones, such as those deleted
+// while the provider was not running. Recently saved states are kept, since
+// their managed resources might not be cached yet.
+func (g *GC) collectStates(ctx context.Context, managed map[types.UID]struct{}, now time.Time) error {
+	uids, err := g.states.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, errListStates)
+	}
+	for _, uid := range uids {
+		if _, exists := managed[uid]; exists {
+			continue
+		}
+		st, err := g.states.Load(ctx, uid)
+		if err != nil {
+			return errors.Wrap(err, errLoadState)
+		}
+		// A state that cannot be loaded, such as a partially saved one,
+		// is deleted regardless of its age.
+		if st != nil && now.Sub(st.SavedAt) < minIdle {
+			continue
+		}
+		if err := g.states.Delete(ctx, uid); err != nil {
+			return errors.Wrap(err, errDeleteState)
+		}
+		g.log.Debug("Removed persisted workspace state", "uid", uid, "reason", reasonOrphaned)
+	}
+	return nil
+}
+
+func (g *GC) remove(d dir, reason string) error {
+	if err := os.RemoveAll(d.path); err != nil {
+		return errors.Wrap(err, errRemoveWorkspace)
+	}
+	g.log.Debug("Removed workspace", "uid", d.uid, "reason", reason, "bytes", d.size)
+	metrics.TerraformWorkspaceRemovals.WithLabelValues(reason).Inc()
+	return nil
+}
+
+// dirs returns the workspace directories in the root directory. The other
+// entries of the root directory, such as the temporary files of other
+// programs, are ignored.
+func (g *GC) dirs() ([]dir, error) {
+	entries, err := os.ReadDir(g.root)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadWorkspaces)
+	}
+	dirs := make([]dir, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := uuid.Parse(e.Name()); err != nil {
+			continue
+		}
+		d := dir{uid: types.UID(e.Name()), path: filepath.Join(g.root, e.Name())}
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadWorkspaces)
+		}
+		// The connectors touch the workspace directories whenever they
+		// use them.
+		d.lastUse = info.ModTime()
+		if d.size, err = diskUsage(d.path); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, nil
+}
+
+// diskUsage returns the size of the regular files in the given directory.
+// Symbolic links, such as those to the cached provider plugins, are not
+// followed.
+func diskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, e fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil || !e.Type().IsRegular() {
+			return err
+		}
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, errors.Wrap(err, errWorkspaceDiskUse)
+}
+
+// managedUIDs returns the UIDs of all the managed resources of the provider.
+func (g *GC) managedUIDs(ctx context.Context) (map[types.UID]struct{}, error) {
+	uids := map[types.UID]struct{}{}
+	for gvk := range g.scheme.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		obj, err := g.scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		l, ok := obj.(xpresource.ManagedList)
+		if !ok {
+			continue
+		}
+		if err := g.kube.List(ctx, l); err != nil {
+			return nil, errors.Wrapf(err, "%s: %s", errListManaged, gvk.GroupKind())
+		}
+		for _, mg := range l.GetItems() {
+			uids[mg.GetUID()] = struct{}{}
+		}
+	}
+	return uids, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	s3 "github.com/upbound/provider-aws/apis/s3/v1beta1"
+	"github.com/upbound/provider-aws/internal/persistence"
+)
+
+const (
+	uidManaged = "0c3bd1a4-8a1e-4b8e-a0a4-3c0e5e1f9d2a"
+	uidOrphan  = "7f0e2b8c-1d4a-4f6e-9b3c-2a5d8e7f6c1b"
+	uidOld     = "3e1f6a2b-5c7d-4e8f-9a0b-1c2d3e4f5a6b"
+	uidRunning = "9a8b7c6d-5e4f-4a3b-8c1d-0e9f8a7b6c5d"
+)
+
+// workspaceDir is a workspace directory to create.
+type workspaceDir struct {
+	size    int
+	lastUse time.Duration
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		reason  string
+		managed []string
+		dirs    map[string]workspaceDir
+		budget  int64
+		running string
+		want    []string
+	}{
+		"Orphaned": {
+			reason:  "The workspaces of managed resources that no longer exist should be removed.",
+			managed: []string{uidManaged},
+			dirs: map[string]workspaceDir{
+				uidManaged: {size: 10, lastUse: time.Hour},
+				uidOrphan:  {size: 10, lastUse: time.Hour},
+			},
+			want: []string{uidManaged},
+		},
+		"RecentlyUsed": {
+			reason: "Recently used workspaces should be kept since their managed resources might not be cached yet.",
+			dirs: map[string]workspaceDir{
+				uidOrphan: {size: 10},
+			},
+			want: []string{uidOrphan},
+		},
+		"OtherDirectories": {
+			reason: "Directories that are not workspaces should be kept.",
+			dirs: map[string]workspaceDir{
+				"terraform-plugins": {size: 10, lastUse: time.Hour},
+			},
+			want: []string{"terraform-plugins"},
+		},
+		"Evicted": {
+			reason:  "The least recently used workspaces should be evicted while the workspaces exceed the disk budget.",
+			managed: []string{uidManaged, uidOld, uidRunning},
+			dirs: map[string]workspaceDir{
+				uidManaged: {size: 10, lastUse: time.Hour},
+				uidOld:     {size: 10, lastUse: 2 * time.Hour},
+				uidRunning: {size: 10, lastUse: 3 * time.Hour},
+			},
+			budget:  15,
+			running: uidRunning,
+			want:    []string{uidRunning},
+		},
+		"WithinBudget": {
+			reason:  "No workspaces should be evicted while the workspaces are within the disk budget.",
+			managed: []string{uidManaged, uidOld},
+			dirs: map[string]workspaceDir{
+				uidManaged: {size: 10, lastUse: time.Hour},
+				uidOld:     {size: 10, lastUse: 2 * time.Hour},
+			},
+			budget: 20,
+			want:   []string{uidManaged, uidOld},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, d := range tc.dirs {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(path, 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(path, "terraform.tfstate"), make([]byte, d.size), 0600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, now.Add(-d.lastUse), now.Add(-d.lastUse)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := runtime.NewScheme()
+			if err := s3.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			c := fake.NewClientBuilder().WithScheme(s)
+			for _, uid := range tc.managed {
+				c.WithObjects(&s3.Bucket{ObjectMeta: metav1.ObjectMeta{Name: uid, UID: types.UID(uid)}})
+			}
+			g := NewGC(c.Build(), s, root, logging.NewNopLogger(),
+				WithDiskBudget(tc.budget),
+				WithInUse(func(uid types.UID) bool { return string(uid) == tc.running }))
+			if err := g.Collect(context.Background(), now); err != nil {
+				t.Fatalf("\n%s\nCollect(...): %v", tc.reason, err)
+			}
+			entries, err := os.ReadDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(entries))
+			for _, e := range entries {
+				got = append(got, e.Name())
+			}
+			want := append([]string{}, tc.want...)
+			sort.Strings(want)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nCollect(...): -want workspaces, +got workspaces:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCollectStates(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		reason  string
+		managed []string
+		states  map[string]time.Duration
+		want    []types.UID
+	}{
+		"Orphaned": {
+			reason:  "The persisted states of managed resources that no longer exist should be deleted.",
+			managed: []string{uidManaged},
+			states: map[string]time.Duration{
+				uidManaged: time.Hour,
+				uidOrphan:  time.Hour,
+			},
+			want: []types.UID{uidManaged},
+		},
+		"RecentlySaved": {
+			reason: "Recently saved states should be kept since their managed resources might not be cached yet.",
+			states: map[string]time.Duration{
+				uidOrphan: 0,
+			},
+			want: []types.UID{uidOrphan},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			st, err := persistence.NewDirectoryStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for uid, age := range tc.states {
+				if err := st.Save(ctx, types.UID(uid), &persistence.State{SavedAt: now.Add(-age)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := runtime.NewScheme()
+			if err := s3.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			c := fake.NewClientBuilder().WithScheme(s)
+			for _, uid := range tc.managed {
+				c.WithObjects(&s3.Bucket{ObjectMeta: metav1.ObjectMeta{Name: uid, UID: types.UID(uid)}})
+			}
+			g := NewGC(c.Build(), s, t.TempDir(), logging.NewNopLogger(), WithStateStore(st))
+			if err := g.Collect(ctx, now); err != nil {
+				t.Fatalf("\n%s\nCollect(...): %v", tc.reason, err)
+			}
+			got, err := st.List(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCollect(...): -want states, +got states:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}